@@ -2,16 +2,70 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
 )
 
+const (
+	EventStoreDriverPostgres = "postgres"
+	EventStoreDriverSQLite   = "sqlite"
+	EventStoreDriverInMemory = "memory"
+
+	EmailDriverFile = "file"
+	EmailDriverSMTP = "smtp"
+
+	EventStorePayloadFormatJSON     = "json"
+	EventStorePayloadFormatProtobuf = "protobuf"
+
+	defaultSnapshotInterval     = 50
+	defaultIdempotencyKeyTTL    = 24 * time.Hour
+	defaultConfirmationTTL      = 72 * time.Hour
+	defaultConfirmationMaxFails = 5
+	defaultConfirmationWindow   = 15 * time.Minute
+	defaultConfirmationCooldown = 15 * time.Minute
+	defaultEmailLocale          = "en"
+	defaultEmailFrom            = "no-reply@go-iddd.local"
+)
+
 type Config struct {
+	EventStore struct {
+		Driver           string
+		SnapshotInterval uint   // 0 disables snapshots
+		ContentType      string // one of the es.ContentType* constants
+	}
 	Postgres struct {
 		DSN                    string
 		MigrationsPathCustomer string
 	}
+	SQLite struct {
+		DSN                    string
+		MigrationsPathCustomer string
+	}
+	Idempotency struct {
+		KeyTTL time.Duration
+	}
+	EmailConfirmation struct {
+		HashTTL           time.Duration // 0 disables the expiry
+		MaxFailedAttempts uint          // 0 disables the lockout
+		FailureWindow     time.Duration
+		LockoutCooldown   time.Duration
+	}
+	Email struct {
+		Driver        string
+		Locale        string
+		From          string
+		FileDirectory string
+		SMTP          struct {
+			HostAndPort string
+			Username    string
+			Password    string
+		}
+	}
 	GRPC struct {
 		HostAndPort string
 	}
@@ -23,25 +77,141 @@ type Config struct {
 // This is also used by Config_test.go to check that all keys exist in Env,
 // so always add new keys here!
 var ConfigExpectedEnvKeys = map[string]string{
-	"pgDSN":  "POSTGRES_DSN",
-	"pgMPC":  "POSTGRES_MIGRATIONS_PATH_CUSTOMER",
 	"grpcHP": "GRPC_HOST_AND_PORT",
 	"restHP": "REST_HOST_AND_PORT",
 }
 
+// Optional keys fall back to the given default value if they are missing in Env.
+var ConfigOptionalEnvKeys = map[string]string{
+	"esDriver": "EVENTSTORE_DRIVER",
+	"pgDSN":    "POSTGRES_DSN",                      // required if EVENTSTORE_DRIVER is postgres
+	"pgMPC":    "POSTGRES_MIGRATIONS_PATH_CUSTOMER", // required if EVENTSTORE_DRIVER is postgres
+	"esSI":     "EVENTSTORE_SNAPSHOT_INTERVAL",
+	"esPF":     "EVENTSTORE_PAYLOAD_FORMAT",
+	"idemTTL":  "IDEMPOTENCY_KEY_TTL",
+	"sqlDSN":   "SQLITE_DSN",                      // required if EVENTSTORE_DRIVER is sqlite
+	"sqlMPC":   "SQLITE_MIGRATIONS_PATH_CUSTOMER", // required if EVENTSTORE_DRIVER is sqlite
+	"ecTTL":    "EMAIL_CONFIRMATION_HASH_TTL",
+	"ecMax":    "EMAIL_CONFIRMATION_MAX_FAILED_ATTEMPTS",
+	"ecWin":    "EMAIL_CONFIRMATION_FAILURE_WINDOW",
+	"ecCool":   "EMAIL_CONFIRMATION_LOCKOUT_COOLDOWN",
+	"mailDrv":  "EMAIL_DRIVER",
+	"mailLoc":  "EMAIL_LOCALE",
+	"mailFrom": "EMAIL_FROM",
+	"mailDir":  "EMAIL_FILE_DIRECTORY",
+	"smtpHP":   "SMTP_HOST_AND_PORT", // required if EMAIL_DRIVER is smtp
+	"smtpUser": "SMTP_USERNAME",
+	"smtpPW":   "SMTP_PASSWORD",
+}
+
 func MustBuildConfigFromEnv(logger *shared.Logger) *Config {
 	var err error
 	conf := &Config{}
 	msg := "mustBuildConfigFromEnv: %s - Hasta la vista, baby!"
 
-	if conf.Postgres.DSN, err = conf.stringFromEnv(ConfigExpectedEnvKeys["pgDSN"]); err != nil {
+	conf.EventStore.Driver = conf.stringFromEnvWithDefault(ConfigOptionalEnvKeys["esDriver"], EventStoreDriverPostgres)
+
+	switch conf.EventStore.Driver {
+	case EventStoreDriverPostgres:
+		if conf.Postgres.DSN, err = conf.stringFromEnv(ConfigOptionalEnvKeys["pgDSN"]); err != nil {
+			logger.Panicf(msg, err)
+		}
+
+		if conf.Postgres.MigrationsPathCustomer, err = conf.stringFromEnv(ConfigOptionalEnvKeys["pgMPC"]); err != nil {
+			logger.Panicf(msg, err)
+		}
+	case EventStoreDriverInMemory:
+	case EventStoreDriverSQLite:
+		if conf.SQLite.DSN, err = conf.stringFromEnv(ConfigOptionalEnvKeys["sqlDSN"]); err != nil {
+			logger.Panicf(msg, err)
+		}
+
+		if conf.SQLite.MigrationsPathCustomer, err = conf.stringFromEnv(ConfigOptionalEnvKeys["sqlMPC"]); err != nil {
+			logger.Panicf(msg, err)
+		}
+	default:
+		logger.Panicf(msg, errors.Newf("config value [%s] is not supported", ConfigOptionalEnvKeys["esDriver"]))
+	}
+
+	if conf.EventStore.SnapshotInterval, err = conf.uintFromEnvWithDefault(ConfigOptionalEnvKeys["esSI"], defaultSnapshotInterval); err != nil {
 		logger.Panicf(msg, err)
 	}
 
-	if conf.Postgres.MigrationsPathCustomer, err = conf.stringFromEnv(ConfigExpectedEnvKeys["pgMPC"]); err != nil {
+	switch conf.stringFromEnvWithDefault(ConfigOptionalEnvKeys["esPF"], EventStorePayloadFormatJSON) {
+	case EventStorePayloadFormatJSON:
+		conf.EventStore.ContentType = es.ContentTypeJSON
+	case EventStorePayloadFormatProtobuf:
+		conf.EventStore.ContentType = es.ContentTypeProtobuf
+	default:
+		logger.Panicf(msg, errors.Newf("config value [%s] is not supported", ConfigOptionalEnvKeys["esPF"]))
+	}
+
+	conf.Idempotency.KeyTTL, err = conf.durationFromEnvWithDefault(
+		ConfigOptionalEnvKeys["idemTTL"],
+		defaultIdempotencyKeyTTL,
+	)
+
+	if err != nil {
 		logger.Panicf(msg, err)
 	}
 
+	conf.EmailConfirmation.HashTTL, err = conf.durationFromEnvWithDefault(
+		ConfigOptionalEnvKeys["ecTTL"],
+		defaultConfirmationTTL,
+	)
+
+	if err != nil {
+		logger.Panicf(msg, err)
+	}
+
+	conf.EmailConfirmation.MaxFailedAttempts, err = conf.uintFromEnvWithDefault(
+		ConfigOptionalEnvKeys["ecMax"],
+		defaultConfirmationMaxFails,
+	)
+
+	if err != nil {
+		logger.Panicf(msg, err)
+	}
+
+	conf.EmailConfirmation.FailureWindow, err = conf.durationFromEnvWithDefault(
+		ConfigOptionalEnvKeys["ecWin"],
+		defaultConfirmationWindow,
+	)
+
+	if err != nil {
+		logger.Panicf(msg, err)
+	}
+
+	conf.EmailConfirmation.LockoutCooldown, err = conf.durationFromEnvWithDefault(
+		ConfigOptionalEnvKeys["ecCool"],
+		defaultConfirmationCooldown,
+	)
+
+	if err != nil {
+		logger.Panicf(msg, err)
+	}
+
+	conf.Email.Driver = conf.stringFromEnvWithDefault(ConfigOptionalEnvKeys["mailDrv"], EmailDriverFile)
+	conf.Email.Locale = conf.stringFromEnvWithDefault(ConfigOptionalEnvKeys["mailLoc"], defaultEmailLocale)
+	conf.Email.From = conf.stringFromEnvWithDefault(ConfigOptionalEnvKeys["mailFrom"], defaultEmailFrom)
+
+	switch conf.Email.Driver {
+	case EmailDriverFile:
+		conf.Email.FileDirectory = conf.stringFromEnvWithDefault(
+			ConfigOptionalEnvKeys["mailDir"],
+			filepath.Join(os.TempDir(), "go-iddd-emails"),
+		)
+	case EmailDriverSMTP:
+		if conf.Email.SMTP.HostAndPort, err = conf.stringFromEnv(ConfigOptionalEnvKeys["smtpHP"]); err != nil {
+			logger.Panicf(msg, err)
+		}
+
+		conf.Email.SMTP.Username = conf.stringFromEnvWithDefault(ConfigOptionalEnvKeys["smtpUser"], "")
+		conf.Email.SMTP.Password = conf.stringFromEnvWithDefault(ConfigOptionalEnvKeys["smtpPW"], "")
+	default:
+		logger.Panicf(msg, errors.Newf("config value [%s] is not supported", ConfigOptionalEnvKeys["mailDrv"]))
+	}
+
 	if conf.GRPC.HostAndPort, err = conf.stringFromEnv(ConfigExpectedEnvKeys["grpcHP"]); err != nil {
 		logger.Panicf(msg, err)
 	}
@@ -61,3 +231,40 @@ func (conf Config) stringFromEnv(envKey string) (string, error) {
 
 	return envVal, nil
 }
+
+func (conf Config) stringFromEnvWithDefault(envKey string, defaultVal string) string {
+	envVal, ok := os.LookupEnv(envKey)
+	if !ok || envVal == "" {
+		return defaultVal
+	}
+
+	return envVal
+}
+
+func (conf Config) uintFromEnvWithDefault(envKey string, defaultVal uint) (uint, error) {
+	envVal, ok := os.LookupEnv(envKey)
+	if !ok || envVal == "" {
+		return defaultVal, nil
+	}
+
+	uintVal, err := strconv.ParseUint(envVal, 10, 32)
+	if err != nil {
+		return 0, errors.Mark(errors.Newf("config value [%s] is not an unsigned integer", envKey), shared.ErrTechnical)
+	}
+
+	return uint(uintVal), nil
+}
+
+func (conf Config) durationFromEnvWithDefault(envKey string, defaultVal time.Duration) (time.Duration, error) {
+	envVal, ok := os.LookupEnv(envKey)
+	if !ok || envVal == "" {
+		return defaultVal, nil
+	}
+
+	durationVal, err := time.ParseDuration(envVal)
+	if err != nil {
+		return 0, errors.Mark(errors.Newf("config value [%s] is not a duration", envKey), shared.ErrTechnical)
+	}
+
+	return durationVal, nil
+}
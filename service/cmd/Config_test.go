@@ -44,4 +44,128 @@ func TestMustBuildConfigFromEnv(t *testing.T) {
 			So(err, ShouldBeNil)
 		})
 	}
+
+	for _, envKey := range []string{ConfigOptionalEnvKeys["sqlDSN"], ConfigOptionalEnvKeys["sqlMPC"]} {
+		currentEnvKey := envKey
+
+		Convey(fmt.Sprintf("Given the sqlite driver is selected and %s is missing in Env", envKey), t, func() {
+			origEnv := map[string]string{}
+
+			for _, key := range []string{"esDriver", "sqlDSN", "sqlMPC"} {
+				if envVal, ok := os.LookupEnv(ConfigOptionalEnvKeys[key]); ok {
+					origEnv[ConfigOptionalEnvKeys[key]] = envVal
+				}
+			}
+
+			So(os.Setenv(ConfigOptionalEnvKeys["esDriver"], EventStoreDriverSQLite), ShouldBeNil)
+			So(os.Setenv(ConfigOptionalEnvKeys["sqlDSN"], "file:test.db"), ShouldBeNil)
+			So(os.Setenv(ConfigOptionalEnvKeys["sqlMPC"], "migrations"), ShouldBeNil)
+			So(os.Unsetenv(currentEnvKey), ShouldBeNil)
+
+			Convey("When MustBuildConfigFromEnv is invoked", func() {
+				wrapper := func() { MustBuildConfigFromEnv(logger) }
+
+				Convey("It should panic", func() {
+					So(wrapper, ShouldPanic)
+				})
+			})
+
+			for _, key := range []string{"esDriver", "sqlDSN", "sqlMPC"} {
+				So(os.Unsetenv(ConfigOptionalEnvKeys[key]), ShouldBeNil)
+			}
+
+			for envKey, envVal := range origEnv {
+				So(os.Setenv(envKey, envVal), ShouldBeNil)
+			}
+		})
+	}
+
+	for _, envKey := range []string{ConfigOptionalEnvKeys["pgDSN"], ConfigOptionalEnvKeys["pgMPC"]} {
+		currentEnvKey := envKey
+
+		Convey(fmt.Sprintf("Given the postgres driver is selected and %s is missing in Env", envKey), t, func() {
+			origEnv := map[string]string{}
+
+			for _, key := range []string{"esDriver", "pgDSN", "pgMPC"} {
+				if envVal, ok := os.LookupEnv(ConfigOptionalEnvKeys[key]); ok {
+					origEnv[ConfigOptionalEnvKeys[key]] = envVal
+				}
+			}
+
+			So(os.Setenv(ConfigOptionalEnvKeys["esDriver"], EventStoreDriverPostgres), ShouldBeNil)
+			So(os.Setenv(ConfigOptionalEnvKeys["pgDSN"], "postgresql://localhost/test"), ShouldBeNil)
+			So(os.Setenv(ConfigOptionalEnvKeys["pgMPC"], "migrations"), ShouldBeNil)
+			So(os.Unsetenv(currentEnvKey), ShouldBeNil)
+
+			Convey("When MustBuildConfigFromEnv is invoked", func() {
+				wrapper := func() { MustBuildConfigFromEnv(logger) }
+
+				Convey("It should panic", func() {
+					So(wrapper, ShouldPanic)
+				})
+			})
+
+			Convey(fmt.Sprintf("When the memory driver is selected instead and %s is still missing", currentEnvKey), func() {
+				So(os.Setenv(ConfigOptionalEnvKeys["esDriver"], EventStoreDriverInMemory), ShouldBeNil)
+				wrapper := func() { MustBuildConfigFromEnv(logger) }
+
+				Convey("It should not panic", func() {
+					So(wrapper, ShouldNotPanic)
+				})
+			})
+
+			for _, key := range []string{"esDriver", "pgDSN", "pgMPC"} {
+				So(os.Unsetenv(ConfigOptionalEnvKeys[key]), ShouldBeNil)
+			}
+
+			for envKey, envVal := range origEnv {
+				So(os.Setenv(envKey, envVal), ShouldBeNil)
+			}
+		})
+	}
+
+	Convey("Given IDEMPOTENCY_KEY_TTL is not a valid duration", t, func() {
+		envKey := ConfigOptionalEnvKeys["idemTTL"]
+		origEnvVal, isSet := os.LookupEnv(envKey)
+		So(os.Setenv(envKey, "one day"), ShouldBeNil)
+
+		Convey("When MustBuildConfigFromEnv is invoked", func() {
+			wrapper := func() { MustBuildConfigFromEnv(logger) }
+
+			Convey("It should panic", func() {
+				So(wrapper, ShouldPanic)
+			})
+		})
+
+		So(os.Unsetenv(envKey), ShouldBeNil)
+
+		if isSet {
+			So(os.Setenv(envKey, origEnvVal), ShouldBeNil)
+		}
+	})
+	Convey("Given the smtp email driver is selected and SMTP_HOST_AND_PORT is missing in Env", t, func() {
+		driverKey, hostAndPortKey := ConfigOptionalEnvKeys["mailDrv"], ConfigOptionalEnvKeys["smtpHP"]
+		origDriver, isDriverSet := os.LookupEnv(driverKey)
+		origHostAndPort, isHostAndPortSet := os.LookupEnv(hostAndPortKey)
+		So(os.Setenv(driverKey, EmailDriverSMTP), ShouldBeNil)
+		So(os.Unsetenv(hostAndPortKey), ShouldBeNil)
+
+		Convey("When MustBuildConfigFromEnv is invoked", func() {
+			wrapper := func() { MustBuildConfigFromEnv(logger) }
+
+			Convey("It should panic", func() {
+				So(wrapper, ShouldPanic)
+			})
+		})
+
+		So(os.Unsetenv(driverKey), ShouldBeNil)
+
+		if isDriverSet {
+			So(os.Setenv(driverKey, origDriver), ShouldBeNil)
+		}
+
+		if isHostAndPortSet {
+			So(os.Setenv(hostAndPortKey, origHostAndPort), ShouldBeNil)
+		}
+	})
 }
@@ -1,27 +1,101 @@
 package cmd
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/email"
 	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/memory"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/ndjson"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/postgres"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/projection"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/publisher"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/sqlite"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 const (
 	eventStoreTableName           = "eventstore"
 	uniqueEmailAddressesTableName = "unique_email_addresses"
+	snapshotsTableName            = "snapshots"
+	outboxTableName               = "outbox"
+	encryptionKeysTableName       = "encryption_keys"
+	idempotencyKeysTableName      = "idempotency_keys"
+	customersTableName            = "customers"
+	checkpointsTableName          = "projection_checkpoints"
+	changeNotificationChannel     = "eventstore_appended"
+
+	outboxRelayBatchSize        = 100
+	outboxRelayPollInterval     = 200 * time.Millisecond
+	outboxRelayMaxRetryInterval = 30 * time.Second
+
+	customerListBatchSize    = 100
+	customerListPollInterval = time.Second
+	customerListGapTimeout   = 5 * time.Second
+
+	changeNotificationPollInterval = time.Second
+
+	idempotencyKeyReservationTimeout = time.Minute
+)
+
+// Service names of the background workers in the gRPC health checks.
+const (
+	HealthServiceCustomerListProjection     = "customeraccounts.CustomerListProjection"
+	HealthServiceChangeNotificationListener = "customeraccounts.ChangeNotificationListener"
+	HealthServiceOutboxRelay                = "customeraccounts.OutboxRelay"
 )
 
 type DIOption func(container *DIContainer) error
 
+// CustomerEventStore is implemented by all adapters which can serve as the event store for Customers.
+type CustomerEventStore interface {
+	RetrieveEventStream(ctx context.Context, id value.CustomerID) (es.EventStream, error)
+	RetrieveFullEventStream(ctx context.Context, id value.CustomerID) (es.EventStream, error)
+	RetrieveEventStreamRange(ctx context.Context, id value.CustomerID, fromVersion, maxEvents uint) (es.EventStream, error)
+	StartEventStream(ctx context.Context, customerRegistered domain.CustomerRegistered) error
+	AppendToEventStream(ctx context.Context, recordedEvents es.RecordedEvents, id value.CustomerID) error
+	PurgeEventStream(ctx context.Context, id value.CustomerID) error
+	ReadGlobalEvents(ctx context.Context, afterPosition uint64, maxEvents uint) ([]es.GlobalEvent, error)
+	ReadOutboxMessages(ctx context.Context, afterID uint64, maxMessages uint) ([]es.OutboxMessage, error)
+	MarkOutboxMessageAsPublished(ctx context.Context, id uint64) error
+	RetrieveCustomerIDByEmailAddress(ctx context.Context, emailAddress value.EmailAddress) (value.CustomerID, error)
+}
+
+// EncryptionKeyStore is implemented by all adapters which can hold the keys for crypto-shredding.
+type EncryptionKeyStore interface {
+	RetrieveOrCreateEncryptionKey(ctx context.Context, subjectID string) ([]byte, error)
+	RetrieveEncryptionKey(ctx context.Context, subjectID string) ([]byte, error)
+	ShredEncryptionKey(ctx context.Context, subjectID string) error
+}
+
+// IdempotencyKeyStore is implemented by all adapters which can record the results of commands by idempotency key.
+type IdempotencyKeyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, idempotencyKey, fingerprint string) (string, bool, error)
+	RecordIdempotentResult(ctx context.Context, idempotencyKey, result string) error
+	ReleaseIdempotencyKey(ctx context.Context, idempotencyKey string) error
+}
+
+// CustomerListStore is implemented by all adapters which can hold the Customer list projection.
+type CustomerListStore interface {
+	SaveCustomerListView(ctx context.Context, view customer.ListView, globalPosition uint64) error
+	ForgetCustomerListView(ctx context.Context, customerID string) error
+	CustomerListCheckpoint(ctx context.Context) (uint64, error)
+	RetrieveCustomerListViews(ctx context.Context, query application.CustomerListQuery) ([]customer.ListView, error)
+}
+
 func UsePostgresDBConn(dbConn *sql.DB) DIOption {
 	return func(container *DIContainer) error {
 		if dbConn == nil {
@@ -34,6 +108,25 @@ func UsePostgresDBConn(dbConn *sql.DB) DIOption {
 	}
 }
 
+func UseSQLiteDBConn(dbConn *sql.DB) DIOption {
+	return func(container *DIContainer) error {
+		if dbConn == nil {
+			return errors.New("sqliteDBConn must not be nil")
+		}
+
+		container.infra.sqliteDBConn = dbConn
+
+		return nil
+	}
+}
+
+func UseInMemoryCustomerEventStore() DIOption {
+	return func(container *DIContainer) error {
+		container.infra.useInMemoryEventStore = true
+		return nil
+	}
+}
+
 func WithMarshalCustomerEvents(fn es.MarshalDomainEvent) DIOption {
 	return func(container *DIContainer) error {
 		container.dependency.marshalCustomerEvent = fn
@@ -55,6 +148,27 @@ func WithBuildUniqueEmailAddressAssertions(fn customer.ForBuildingUniqueEmailAdd
 	}
 }
 
+func WithBuildCustomerSnapshots(fn es.BuildSnapshot) DIOption {
+	return func(container *DIContainer) error {
+		container.dependency.buildCustomerSnapshot = fn
+		return nil
+	}
+}
+
+func WithPublishCustomerEvents(fn es.PublishOutboxMessage) DIOption {
+	return func(container *DIContainer) error {
+		container.dependency.publishCustomerEvent = fn
+		return nil
+	}
+}
+
+func WithSendEmails(fn application.ForSendingEmails) DIOption {
+	return func(container *DIContainer) error {
+		container.dependency.sendEmail = fn
+		return nil
+	}
+}
+
 func ReplaceGRPCCustomerServer(server customergrpc.CustomerServer) DIOption {
 	return func(container *DIContainer) error {
 		if server == nil {
@@ -69,34 +183,68 @@ func ReplaceGRPCCustomerServer(server customergrpc.CustomerServer) DIOption {
 
 type DIContainer struct {
 	config *Config
+	logger *shared.Logger
 
 	infra struct {
-		pgDBConn *sql.DB
+		pgDBConn              *sql.DB
+		sqliteDBConn          *sql.DB
+		useInMemoryEventStore bool
 	}
 
 	dependency struct {
 		marshalCustomerEvent              es.MarshalDomainEvent
 		unmarshalCustomerEvent            es.UnmarshalDomainEvent
 		buildUniqueEmailAddressAssertions customer.ForBuildingUniqueEmailAddressAssertions
+		buildCustomerSnapshot             es.BuildSnapshot
+		publishCustomerEvent              es.PublishOutboxMessage
+		sendEmail                         application.ForSendingEmails
 	}
 
 	service struct {
-		customerEventStore     *postgres.CustomerEventStore
-		customerCommandHandler *application.CustomerCommandHandler
-		customerQueryHandler   *application.CustomerQueryHandler
-		grpcCustomerServer     customergrpc.CustomerServer
-		grpcServer             *grpc.Server
+		customerPIIProtection      *serialization.CustomerPIIProtection
+		marshalProtectedEvent      es.MarshalDomainEvent
+		unmarshalProtectedEvent    es.UnmarshalDomainEvent
+		sqlEventStore              *es.SQLEventStore
+		customerEventStore         CustomerEventStore
+		encryptionKeyStore         EncryptionKeyStore
+		idempotencyKeyStore        IdempotencyKeyStore
+		customerListStore          CustomerListStore
+		customerOutboxRelay        *es.OutboxRelay
+		confirmationEmailSender    *application.ConfirmationEmailSender
+		customerListProjection     *application.CustomerListProjection
+		customerListSubscription   *es.CatchUpSubscription
+		changeNotificationListener *postgres.ChangeNotificationListener
+		customerStreamExporter     *ndjson.CustomerEventStreamExporter
+		customerStreamImporter     *ndjson.CustomerEventStreamImporter
+		customerCommandHandler     *application.CustomerCommandHandler
+		customerQueryHandler       *application.CustomerQueryHandler
+		grpcCustomerServer         customergrpc.CustomerServer
+		grpcServer                 *grpc.Server
+		healthServer               *health.Server
 	}
 }
 
 func MustBuildDIContainer(config *Config, logger *shared.Logger, opts ...DIOption) *DIContainer {
 	container := &DIContainer{}
 	container.config = config
+	container.logger = logger
 
 	/*** Define default dependencies ***/
 	container.dependency.marshalCustomerEvent = serialization.MarshalCustomerEvent
 	container.dependency.unmarshalCustomerEvent = serialization.UnmarshalCustomerEvent
 	container.dependency.buildUniqueEmailAddressAssertions = customer.BuildUniqueEmailAddressAssertions
+	container.dependency.buildCustomerSnapshot = customer.BuildSnapshot
+	container.dependency.publishCustomerEvent = publisher.NewLoggingPublisher(logger).Publish
+	container.dependency.sendEmail = email.NewFileMailbox(config.Email.FileDirectory, config.Email.From).Send
+
+	if config.Email.Driver == EmailDriverSMTP {
+		container.dependency.sendEmail = email.NewSMTPSender(
+			config.Email.SMTP.HostAndPort,
+			config.Email.SMTP.Username,
+			config.Email.SMTP.Password,
+			config.Email.From,
+		).Send
+	}
 
 	/*** Apply options for infra, dependencies, services ***/
 	for _, opt := range opts {
@@ -110,72 +258,380 @@ func MustBuildDIContainer(config *Config, logger *shared.Logger, opts ...DIOptio
 	return container
 }
 
-func (container DIContainer) init() {
+func (container *DIContainer) init() {
+	_ = container.GetEncryptionKeyStore()
+	_ = container.GetIdempotencyKeyStore()
 	_ = container.GetCustomerEventStore()
+	_ = container.GetCustomerListStore()
+	_ = container.GetConfirmationEmailSender()
+	_ = container.GetCustomerOutboxRelay()
+	_ = container.GetCustomerListProjection()
+	_ = container.GetChangeNotificationListener()
+	_ = container.GetCustomerEventStreamExporter()
+	_ = container.GetCustomerEventStreamImporter()
 	_ = container.GetCustomerCommandHandler()
 	_ = container.GetCustomerQueryHandler()
 	_ = container.GetGRPCCustomerServer()
 	_ = container.GetGRPCServer()
 }
 
-func (container DIContainer) GetCustomerEventStore() *postgres.CustomerEventStore {
-	if container.service.customerEventStore == nil {
-		container.service.customerEventStore = postgres.NewCustomerEventStore(
-			container.infra.pgDBConn,
-			eventStoreTableName,
-			container.dependency.marshalCustomerEvent,
-			container.dependency.unmarshalCustomerEvent,
-			uniqueEmailAddressesTableName,
+func (container *DIContainer) GetEncryptionKeyStore() EncryptionKeyStore {
+	if container.service.encryptionKeyStore == nil && container.infra.useInMemoryEventStore {
+		container.service.encryptionKeyStore = memory.NewEncryptionKeyStore()
+	}
+
+	if container.service.encryptionKeyStore == nil {
+		db := container.infra.pgDBConn
+
+		if container.infra.sqliteDBConn != nil {
+			db = container.infra.sqliteDBConn
+		}
+
+		container.service.encryptionKeyStore = postgres.NewEncryptionKeyStore(
+			db,
+			encryptionKeysTableName,
+		)
+	}
+
+	return container.service.encryptionKeyStore
+}
+
+func (container *DIContainer) GetIdempotencyKeyStore() IdempotencyKeyStore {
+	if container.service.idempotencyKeyStore == nil && container.infra.useInMemoryEventStore {
+		container.service.idempotencyKeyStore = memory.NewIdempotencyKeyStore(
+			container.config.Idempotency.KeyTTL,
+			idempotencyKeyReservationTimeout,
+		)
+	}
+
+	if container.service.idempotencyKeyStore == nil {
+		db := container.infra.pgDBConn
+
+		if container.infra.sqliteDBConn != nil {
+			db = container.infra.sqliteDBConn
+		}
+
+		container.service.idempotencyKeyStore = es.NewSQLIdempotencyKeyStore(
+			db,
+			idempotencyKeysTableName,
+			container.config.Idempotency.KeyTTL,
+			idempotencyKeyReservationTimeout,
+		)
+	}
+
+	return container.service.idempotencyKeyStore
+}
+
+func (container *DIContainer) GetCustomerListStore() CustomerListStore {
+	if container.service.customerListStore == nil && container.infra.useInMemoryEventStore {
+		container.service.customerListStore = memory.NewCustomerListStore()
+	}
+
+	if container.service.customerListStore == nil {
+		db := container.infra.pgDBConn
+
+		if container.infra.sqliteDBConn != nil {
+			db = container.infra.sqliteDBConn
+		}
+
+		container.service.customerListStore = projection.NewSQLCustomerListStore(
+			db,
+			customersTableName,
+			checkpointsTableName,
+		)
+	}
+
+	return container.service.customerListStore
+}
+
+func (container *DIContainer) GetCustomerEventStore() CustomerEventStore {
+	if container.service.customerEventStore != nil {
+		return container.service.customerEventStore
+	}
+
+	marshalCustomerEvent, unmarshalCustomerEvent := container.getProtectedCustomerEventSerialization()
+
+	if container.infra.useInMemoryEventStore {
+		container.service.customerEventStore = memory.NewCustomerEventStore(
+			marshalCustomerEvent,
+			unmarshalCustomerEvent,
+			container.config.EventStore.ContentType,
 			container.dependency.buildUniqueEmailAddressAssertions,
+			container.config.EventStore.SnapshotInterval,
+			container.dependency.buildCustomerSnapshot,
+		)
+
+		return container.service.customerEventStore
+	}
+
+	db, dialect := container.infra.pgDBConn, es.SQLDialect(es.PostgresDialect{})
+
+	if container.infra.sqliteDBConn != nil {
+		db, dialect = container.infra.sqliteDBConn, sqlite.Dialect{}
+	}
+
+	if container.service.sqlEventStore == nil {
+		container.service.sqlEventStore = es.NewSQLEventStore(
+			db,
+			dialect,
+			eventStoreTableName,
+			marshalCustomerEvent,
+			unmarshalCustomerEvent,
+			container.config.EventStore.ContentType,
+			snapshotsTableName,
+			container.config.EventStore.SnapshotInterval,
+			container.dependency.buildCustomerSnapshot,
+			outboxTableName,
+			changeNotificationChannel,
 		)
 	}
 
+	container.service.customerEventStore = postgres.NewCustomerEventStore(
+		db,
+		dialect,
+		container.service.sqlEventStore,
+		uniqueEmailAddressesTableName,
+		container.dependency.buildUniqueEmailAddressAssertions,
+	)
+
 	return container.service.customerEventStore
 }
 
-func (container DIContainer) GetCustomerCommandHandler() *application.CustomerCommandHandler {
+func (container *DIContainer) getCustomerPIIProtection() *serialization.CustomerPIIProtection {
+	if container.service.customerPIIProtection == nil {
+		container.service.customerPIIProtection = serialization.NewCustomerPIIProtection(
+			container.GetEncryptionKeyStore().RetrieveOrCreateEncryptionKey,
+			container.GetEncryptionKeyStore().RetrieveEncryptionKey,
+		)
+	}
+
+	return container.service.customerPIIProtection
+}
+
+// getProtectedCustomerEventSerialization encrypts the personal data and encodes the configured content type,
+// the payloads of the events and of the outbox messages are stored this way.
+func (container *DIContainer) getProtectedCustomerEventSerialization() (
+	es.MarshalDomainEvent,
+	es.UnmarshalDomainEvent,
+) {
+
+	if container.service.marshalProtectedEvent == nil {
+		piiProtection := container.getCustomerPIIProtection()
+
+		container.service.marshalProtectedEvent = piiProtection.Marshal(container.dependency.marshalCustomerEvent)
+		container.service.unmarshalProtectedEvent = serialization.DecodeCustomerEventsFromProtobuf(
+			piiProtection.Unmarshal(container.dependency.unmarshalCustomerEvent),
+		)
+
+		if container.config.EventStore.ContentType == es.ContentTypeProtobuf {
+			container.service.marshalProtectedEvent = serialization.EncodeCustomerEventsAsProtobuf(
+				container.service.marshalProtectedEvent,
+			)
+		}
+	}
+
+	return container.service.marshalProtectedEvent, container.service.unmarshalProtectedEvent
+}
+
+// GetSQLEventStore returns nil if the in-memory event store is used.
+func (container *DIContainer) GetSQLEventStore() *es.SQLEventStore {
+	_ = container.GetCustomerEventStore()
+
+	return container.service.sqlEventStore
+}
+
+func (container *DIContainer) GetCustomerOutboxRelay() *es.OutboxRelay {
+	if container.service.customerOutboxRelay == nil {
+		_, unmarshalCustomerEvent := container.getProtectedCustomerEventSerialization()
+
+		container.service.customerOutboxRelay = es.NewOutboxRelay(
+			container.GetCustomerEventStore().ReadOutboxMessages,
+			publisher.NewConfirmationEmailPublisher(
+				unmarshalCustomerEvent,
+				container.GetConfirmationEmailSender().SendConfirmationEmail,
+				container.dependency.publishCustomerEvent,
+			).Publish,
+			container.GetCustomerEventStore().MarkOutboxMessageAsPublished,
+			container.logger,
+			outboxRelayBatchSize,
+			outboxRelayPollInterval,
+			outboxRelayMaxRetryInterval,
+		)
+	}
+
+	return container.service.customerOutboxRelay
+}
+
+func (container *DIContainer) GetConfirmationEmailSender() *application.ConfirmationEmailSender {
+	if container.service.confirmationEmailSender == nil {
+		sender, err := application.NewConfirmationEmailSender(
+			container.GetCustomerEventStore().RetrieveEventStream,
+			container.dependency.sendEmail,
+			container.config.Email.Locale,
+		)
+
+		if err != nil {
+			container.logger.Panicf("getConfirmationEmailSender: %s", err)
+		}
+
+		container.service.confirmationEmailSender = sender
+	}
+
+	return container.service.confirmationEmailSender
+}
+
+func (container *DIContainer) GetCustomerListProjection() *application.CustomerListProjection {
+	if container.service.customerListProjection == nil {
+		container.service.customerListProjection = application.NewCustomerListProjection(
+			container.GetCustomerEventStore().RetrieveEventStream,
+			container.GetCustomerListStore().SaveCustomerListView,
+		)
+	}
+
+	return container.service.customerListProjection
+}
+
+// GetCustomerListSubscription continues after the checkpoint which the Customer list has stored,
+// so it needs the DB and is not built when the DIContainer is created.
+func (container *DIContainer) GetCustomerListSubscription() *es.CatchUpSubscription {
+	if container.service.customerListSubscription == nil {
+		checkpoint, err := container.GetCustomerListStore().CustomerListCheckpoint(context.Background())
+		if err != nil {
+			container.logger.Panicf("getCustomerListSubscription: %s", err)
+		}
+
+		container.service.customerListSubscription = es.NewCatchUpSubscription(
+			container.GetCustomerEventStore().ReadGlobalEvents,
+			container.GetCustomerListProjection().Project,
+			checkpoint,
+			customerListBatchSize,
+			customerListPollInterval,
+			customerListGapTimeout,
+		)
+	}
+
+	return container.service.customerListSubscription
+}
+
+// GetChangeNotificationListener returns nil if Postgres is not used, the other event stores have no notifications.
+func (container *DIContainer) GetChangeNotificationListener() *postgres.ChangeNotificationListener {
+	if container.service.changeNotificationListener == nil && container.infra.pgDBConn != nil {
+		container.service.changeNotificationListener = postgres.NewChangeNotificationListener(
+			container.config.Postgres.DSN,
+			changeNotificationChannel,
+			changeNotificationPollInterval,
+			container.logger,
+		)
+	}
+
+	return container.service.changeNotificationListener
+}
+
+func (container *DIContainer) GetCustomerEventStreamExporter() *ndjson.CustomerEventStreamExporter {
+	if container.service.customerStreamExporter == nil {
+		container.service.customerStreamExporter = ndjson.NewCustomerEventStreamExporter(
+			container.GetCustomerEventStore().ReadGlobalEvents,
+			container.getCustomerPIIProtection().Marshal(container.dependency.marshalCustomerEvent),
+			container.dependency.marshalCustomerEvent,
+		)
+	}
+
+	return container.service.customerStreamExporter
+}
+
+func (container *DIContainer) GetCustomerEventStreamImporter() *ndjson.CustomerEventStreamImporter {
+	if container.service.customerStreamImporter == nil {
+		container.service.customerStreamImporter = ndjson.NewCustomerEventStreamImporter(
+			container.getCustomerPIIProtection().UnmarshalWithRequiredKeys(container.dependency.unmarshalCustomerEvent),
+			container.GetCustomerEventStore().RetrieveEventStream,
+			container.GetCustomerEventStore().AppendToEventStream,
+		)
+	}
+
+	return container.service.customerStreamImporter
+}
+
+func (container *DIContainer) GetCustomerCommandHandler() *application.CustomerCommandHandler {
 	if container.service.customerCommandHandler == nil {
 		container.service.customerCommandHandler = application.NewCustomerCommandHandler(
 			container.GetCustomerEventStore().RetrieveEventStream,
 			container.GetCustomerEventStore().StartEventStream,
 			container.GetCustomerEventStore().AppendToEventStream,
+			func(ctx context.Context, id value.CustomerID) error {
+				if err := container.GetEncryptionKeyStore().ShredEncryptionKey(ctx, id.String()); err != nil {
+					return err
+				}
+
+				// the Customer list holds the decrypted personal data, which must be forgotten as well
+				return container.GetCustomerListStore().ForgetCustomerListView(ctx, id.String())
+			},
+			container.GetIdempotencyKeyStore().ReserveIdempotencyKey,
+			container.GetIdempotencyKeyStore().RecordIdempotentResult,
+			container.GetIdempotencyKeyStore().ReleaseIdempotencyKey,
+			customer.EmailConfirmationPolicy{
+				ConfirmationHashTTL: container.config.EmailConfirmation.HashTTL,
+				MaxFailedAttempts:   container.config.EmailConfirmation.MaxFailedAttempts,
+				FailureWindow:       container.config.EmailConfirmation.FailureWindow,
+				LockoutCooldown:     container.config.EmailConfirmation.LockoutCooldown,
+			},
 		)
 	}
 
 	return container.service.customerCommandHandler
 }
 
-func (container DIContainer) GetCustomerQueryHandler() *application.CustomerQueryHandler {
+func (container *DIContainer) GetCustomerQueryHandler() *application.CustomerQueryHandler {
 	if container.service.customerQueryHandler == nil {
 		container.service.customerQueryHandler = application.NewCustomerQueryHandler(
 			container.GetCustomerEventStore().RetrieveEventStream,
+			container.GetCustomerEventStore().RetrieveFullEventStream,
+			container.GetCustomerListStore().RetrieveCustomerListViews,
+			container.GetCustomerEventStore().RetrieveCustomerIDByEmailAddress,
+			container.GetCustomerEventStore().RetrieveEventStreamRange,
 		)
 	}
 
 	return container.service.customerQueryHandler
 }
 
-func (container DIContainer) GetGRPCCustomerServer() customergrpc.CustomerServer {
+func (container *DIContainer) GetGRPCCustomerServer() customergrpc.CustomerServer {
 	if container.service.grpcCustomerServer == nil {
 		container.service.grpcCustomerServer = customergrpc.NewCustomerServer(
 			container.GetCustomerCommandHandler().RegisterCustomer,
 			container.GetCustomerCommandHandler().ConfirmCustomerEmailAddress,
+			container.GetCustomerCommandHandler().RegenerateCustomerEmailConfirmation,
 			container.GetCustomerCommandHandler().ChangeCustomerEmailAddress,
 			container.GetCustomerCommandHandler().ChangeCustomerName,
 			container.GetCustomerCommandHandler().DeleteCustomer,
+			container.GetCustomerCommandHandler().ForgetCustomer,
 			container.GetCustomerQueryHandler().CustomerViewByID,
+			container.GetCustomerQueryHandler().CustomerViewAt,
+			container.GetCustomerQueryHandler().CustomerViewByEmailAddress,
+			container.GetCustomerQueryHandler().CustomerHistory,
+			container.GetCustomerQueryHandler().ListCustomers,
 		)
 	}
 
 	return container.service.grpcCustomerServer
 }
 
-func (container DIContainer) GetGRPCServer() *grpc.Server {
+func (container *DIContainer) GetGRPCServer() *grpc.Server {
 	if container.service.grpcServer == nil {
 		container.service.grpcServer = grpc.NewServer()
 		customergrpc.RegisterCustomerServer(container.service.grpcServer, container.GetGRPCCustomerServer())
+		healthpb.RegisterHealthServer(container.service.grpcServer, container.GetHealthServer())
 		reflection.Register(container.service.grpcServer)
 	}
 
 	return container.service.grpcServer
 }
+
+// GetHealthServer serves the standard gRPC health checks. The background workers report their status
+// with their own service names, e.g. HealthServiceCustomerListProjection.
+func (container *DIContainer) GetHealthServer() *health.Server {
+	if container.service.healthServer == nil {
+		container.service.healthServer = health.NewServer()
+	}
+
+	return container.service.healthServer
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
@@ -11,11 +12,11 @@ import (
 )
 
 func TestNewDIContainer(t *testing.T) {
-	marshalDomainEvent := func(event es.DomainEvent) ([]byte, error) {
+	marshalDomainEvent := func(ctx context.Context, event es.DomainEvent) ([]byte, error) {
 		return nil, nil
 	}
 
-	unmarshalDomainEvent := func(name string, payload []byte, streamVersion uint) (es.DomainEvent, error) {
+	unmarshalDomainEvent := func(ctx context.Context, name string, payload []byte, streamVersion uint) (es.DomainEvent, error) {
 		return nil, nil
 	}
 
@@ -67,4 +68,26 @@ func TestNewDIContainer(t *testing.T) {
 			So(callback, ShouldPanic)
 		})
 	})
+
+	Convey("When a DIContainer is created with a nil sqlite DB connection", t, func() {
+		var db *sql.DB
+
+		logger := shared.NewNilLogger()
+		config := MustBuildConfigFromEnv(logger)
+
+		callback := func() {
+			_ = MustBuildDIContainer(
+				config,
+				logger,
+				UseSQLiteDBConn(db),
+				WithMarshalCustomerEvents(marshalDomainEvent),
+				WithUnmarshalCustomerEvents(unmarshalDomainEvent),
+				WithBuildUniqueEmailAddressAssertions(buildUniqueEmailAddressAssertions),
+			)
+		}
+
+		Convey("Then it should panic", func() {
+			So(callback, ShouldPanic)
+		})
+	})
 }
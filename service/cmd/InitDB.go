@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"database/sql"
+	"strings"
+
+	postgresdatabase "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/postgres/database"
+	sqlitedatabase "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/sqlite/database"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+)
+
+type dbMigrator interface {
+	Up() error
+}
+
+// MustInitDB opens the DB which is selected by Config.EventStore.Driver (postgres or sqlite) and runs its migrations.
+func MustInitDB(config *Config, logger *shared.Logger) *sql.DB {
+	var err error
+	var driverName, dsn string
+
+	switch config.EventStore.Driver {
+	case EventStoreDriverPostgres:
+		driverName, dsn = "postgres", config.Postgres.DSN
+	case EventStoreDriverSQLite:
+		driverName, dsn = "sqlite3", sqliteDSNWithDefaults(config.SQLite.DSN)
+	default:
+		logger.Panicf("bootstrapDB: driver [%s] has no DB", config.EventStore.Driver)
+	}
+
+	logger.Infof("bootstrapDB: opening %s DB connection ...", driverName)
+
+	dbConn, err := sql.Open(driverName, dsn)
+	if err != nil {
+		logger.Panicf("bootstrapDB: failed to open %s DB connection: %s", driverName, err)
+	}
+
+	err = dbConn.Ping()
+	if err != nil {
+		logger.Panicf("bootstrapDB: failed to connect to %s DB: %s", driverName, err)
+	}
+
+	if config.EventStore.Driver == EventStoreDriverSQLite {
+		// SQLite has a single writer anyway, with one connection the service never waits for itself
+		dbConn.SetMaxOpenConns(1)
+	}
+
+	/***/
+
+	logger.Info("bootstrapDB: running DB migrations for customer ...")
+
+	var migratorCustomer dbMigrator
+
+	switch config.EventStore.Driver {
+	case EventStoreDriverPostgres:
+		var migrator *postgresdatabase.Migrator
+
+		if migrator, err = postgresdatabase.NewMigrator(dbConn, config.Postgres.MigrationsPathCustomer); err == nil {
+			migratorCustomer = migrator.WithLogger(logger)
+		}
+	case EventStoreDriverSQLite:
+		var migrator *sqlitedatabase.Migrator
+
+		if migrator, err = sqlitedatabase.NewMigrator(dbConn, config.SQLite.MigrationsPathCustomer); err == nil {
+			migratorCustomer = migrator.WithLogger(logger)
+		}
+	}
+
+	if err != nil {
+		logger.Panicf("bootstrapDB: failed to create DB migrator for customer: %s", err)
+	}
+
+	err = migratorCustomer.Up()
+	if err != nil {
+		logger.Panicf("bootstrapDB: failed to run DB migrations for customer: %s", err)
+	}
+
+	return dbConn
+}
+
+// sqliteDSNWithDefaults adds the options which the SQLite event store needs, unless the DSN sets them itself:
+// a busy timeout, so that other processes (e.g. the eventstore command) don't fail with "database is locked",
+// immediate transactions, so that concurrent appends fail with a concurrency conflict instead of a deadlock,
+// and the WAL journal, so that readers don't block the writer.
+func sqliteDSNWithDefaults(dsn string) string {
+	defaults := []struct {
+		keys  []string // the go-sqlite3 driver has aliases for some options
+		value string
+	}{
+		{keys: []string{"_busy_timeout", "_timeout"}, value: "5000"},
+		{keys: []string{"_txlock"}, value: "immediate"},
+		{keys: []string{"_journal_mode", "_journal"}, value: "WAL"},
+	}
+
+	var options string
+	if idx := strings.IndexRune(dsn, '?'); idx >= 0 {
+		options = dsn[idx+1:]
+	}
+
+	for _, option := range defaults {
+		if !hasDSNOption(options, option.keys) {
+			dsn += dsnOptionSeparator(dsn) + option.keys[0] + "=" + option.value
+		}
+	}
+
+	return dsn
+}
+
+func hasDSNOption(options string, keys []string) bool {
+	for _, option := range strings.Split(options, "&") {
+		for _, key := range keys {
+			if strings.HasPrefix(option, key+"=") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func dsnOptionSeparator(dsn string) string {
+	if strings.ContainsRune(dsn, '?') {
+		return "&"
+	}
+
+	return "?"
+}
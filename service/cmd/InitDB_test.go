@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSqliteDSNWithDefaults(t *testing.T) {
+	Convey("When a SQLite DSN without options gets the defaults", t, func() {
+		dsn := sqliteDSNWithDefaults("file:/tmp/test.db")
+
+		Convey("Then it should have all options which the event store needs", func() {
+			So(dsn, ShouldEqual, "file:/tmp/test.db?_busy_timeout=5000&_txlock=immediate&_journal_mode=WAL")
+		})
+	})
+
+	Convey("When a SQLite DSN with some of the options gets the defaults", t, func() {
+		dsn := sqliteDSNWithDefaults("file:/tmp/test.db?_timeout=100&cache=shared&_journal_mode=DELETE")
+
+		Convey("Then it should only add the missing ones and keep the given ones, also by their alias", func() {
+			So(dsn, ShouldEqual, "file:/tmp/test.db?_timeout=100&cache=shared&_journal_mode=DELETE&_txlock=immediate")
+		})
+	})
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"database/sql"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+)
+
+// MustInitEventStore prepares the event store which is selected by Config.EventStore.Driver.
+// It returns the DIOption to use it and the DB connection, which is nil for the in-memory event store.
+func MustInitEventStore(config *Config, logger *shared.Logger) (DIOption, *sql.DB) {
+	switch config.EventStore.Driver {
+	case EventStoreDriverInMemory:
+		logger.Info("initEventStore: using the in-memory event store ...")
+
+		return UseInMemoryCustomerEventStore(), nil
+	case EventStoreDriverSQLite:
+		sqliteDBConn := MustInitDB(config, logger)
+
+		return UseSQLiteDBConn(sqliteDBConn), sqliteDBConn
+	default:
+		postgresDBConn := MustInitDB(config, logger)
+
+		return UsePostgresDBConn(postgresDBConn), postgresDBConn
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/cmd"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/ndjson"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+const usage = `usage: eventstore <command> [arguments]
+
+commands:
+  verify [streamID]    verify the hash chain of one stream, or of all streams if no streamID is given
+  export [-stream streamID] [-from RFC3339 time] [-to RFC3339 time] [-decrypt-pii] <file>
+                       export all events, or those of one stream and/or in [from, to), as NDJSON
+                       with encrypted personal data, or in clear text with -decrypt-pii
+  import <file>        import the events of an export, appending them to the streams in the event store`
+
+func main() {
+	logger := shared.NewStandardLogger()
+
+	if len(os.Args) < 2 {
+		logger.Info(usage)
+		os.Exit(2)
+	}
+
+	config := cmd.MustBuildConfigFromEnv(logger)
+	useEventStore, dbConn := cmd.MustInitEventStore(config, logger)
+	diContainer := cmd.MustBuildDIContainer(config, logger, useEventStore)
+	ctx := cancelOnStopSignal(context.Background())
+
+	var exitCode int
+
+	switch os.Args[1] {
+	case "verify":
+		exitCode = verify(ctx, logger, diContainer.GetSQLEventStore(), os.Args[2:])
+	case "export":
+		exitCode = export(ctx, logger, diContainer.GetCustomerEventStreamExporter(), os.Args[2:])
+	case "import":
+		exitCode = importStreams(ctx, logger, diContainer.GetCustomerEventStreamImporter(), os.Args[2:])
+	default:
+		logger.Info(usage)
+		exitCode = 2
+	}
+
+	if dbConn != nil {
+		_ = dbConn.Close()
+	}
+
+	os.Exit(exitCode)
+}
+
+// cancelOnStopSignal lets a long running command stop at the next query instead of being killed in the middle.
+func cancelOnStopSignal(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+
+	stopSignalChannel := make(chan os.Signal, 1)
+	signal.Notify(stopSignalChannel, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-stopSignalChannel
+		cancel()
+	}()
+
+	return ctx
+}
+
+func verify(ctx context.Context, logger *shared.Logger, eventStore *es.SQLEventStore, args []string) int {
+	var breaks []es.HashChainBreak
+	var err error
+
+	if eventStore == nil {
+		logger.Error("verify: the hash chain only exists in the SQL event stores")
+
+		return 2
+	}
+
+	switch len(args) {
+	case 0:
+		logger.Info("verify: verifying the hash chains of all streams ...")
+		breaks, err = eventStore.VerifyAllHashChains(ctx)
+	case 1:
+		logger.Infof("verify: verifying the hash chain of stream [%s] ...", args[0])
+		breaks, err = eventStore.VerifyHashChain(ctx, es.NewStreamID(args[0]))
+	default:
+		logger.Info(usage)
+
+		return 2
+	}
+
+	if err != nil {
+		logger.Errorf("verify: %s", err)
+
+		return 2
+	}
+
+	for _, hashChainBreak := range breaks {
+		logger.Warnf("verify: chain broken at %s", hashChainBreak)
+	}
+
+	if len(breaks) > 0 {
+		logger.Errorf("verify: found %d break(s)", len(breaks))
+
+		return 1
+	}
+
+	logger.Info("verify: no breaks found")
+
+	return 0
+}
+
+func export(
+	ctx context.Context,
+	logger *shared.Logger,
+	exporter *ndjson.CustomerEventStreamExporter,
+	args []string,
+) int {
+
+	var streamID, from, to string
+	var decryptPII bool
+	var filter ndjson.ExportFilter
+	var err error
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.StringVar(&streamID, "stream", "", "")
+	flags.StringVar(&from, "from", "", "")
+	flags.StringVar(&to, "to", "", "")
+	flags.BoolVar(&decryptPII, "decrypt-pii", false, "")
+
+	if err = flags.Parse(args); err != nil || flags.NArg() != 1 {
+		logger.Info(usage)
+
+		return 2
+	}
+
+	if streamID != "" {
+		filter.StreamID = es.NewStreamID(streamID)
+	}
+
+	for _, timeArg := range []struct {
+		value  string
+		target *time.Time
+	}{{from, &filter.From}, {to, &filter.To}} {
+		if timeArg.value == "" {
+			continue
+		}
+
+		if *timeArg.target, err = time.Parse(time.RFC3339Nano, timeArg.value); err != nil {
+			logger.Errorf("export: %s", err)
+
+			return 2
+		}
+	}
+
+	file, err := os.Create(flags.Arg(0))
+	if err != nil {
+		logger.Errorf("export: %s", err)
+
+		return 2
+	}
+
+	defer file.Close()
+
+	if decryptPII {
+		logger.Warn("export: the export file contains personal data in clear text, forgetting Customers won't reach it")
+		exporter = exporter.WithDecryptedPII()
+	}
+
+	exported, err := exporter.Export(ctx, file, filter)
+	if err != nil {
+		logger.Errorf("export: %s", err)
+
+		return 1
+	}
+
+	if err = file.Sync(); err != nil {
+		logger.Errorf("export: %s", err)
+
+		return 1
+	}
+
+	logger.Infof("export: exported %d event(s) to [%s]", exported, flags.Arg(0))
+
+	return 0
+}
+
+func importStreams(
+	ctx context.Context,
+	logger *shared.Logger,
+	importer *ndjson.CustomerEventStreamImporter,
+	args []string,
+) int {
+
+	if len(args) != 1 {
+		logger.Info(usage)
+
+		return 2
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		logger.Errorf("import: %s", err)
+
+		return 2
+	}
+
+	defer file.Close()
+
+	imported, err := importer.Import(ctx, file)
+	if err != nil {
+		logger.Errorf("import: imported %d event(s) before failing: %s", imported, err)
+
+		return 1
+	}
+
+	logger.Infof("import: imported %d event(s) from [%s]", imported, args[0])
+
+	return 0
+}
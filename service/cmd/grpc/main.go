@@ -1,33 +1,59 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"net"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/AntonStoeckl/go-iddd/service/cmd"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/postgres"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// restartPolicy tells how long a failed background worker waits before it is restarted.
+// The delay doubles with each failure up to maxDelay, it starts at minDelay again once a worker ran longer than that.
+type restartPolicy struct {
+	minDelay time.Duration
+	maxDelay time.Duration
+}
+
+var backgroundWorkerRestarts = restartPolicy{minDelay: time.Second, maxDelay: time.Minute}
+
 func main() {
 	logger := shared.NewStandardLogger()
 	config := cmd.MustBuildConfigFromEnv(logger)
-	postgresDBConn := cmd.MustInitPostgresDB(config, logger)
+	useEventStore, dbConn := cmd.MustInitEventStore(config, logger)
 	diContainer := cmd.MustBuildDIContainer(
 		config,
 		logger,
-		cmd.UsePostgresDBConn(postgresDBConn),
+		useEventStore,
 	)
 	grpcServer := diContainer.GetGRPCServer()
+	backgroundWorkersCtx, stopBackgroundWorkers := context.WithCancel(context.Background())
 
 	shutdown := func() {
-		shutdown(logger, grpcServer, postgresDBConn, func() { os.Exit(1) })
+		shutdown(logger, grpcServer, stopBackgroundWorkers, dbConn, func() { os.Exit(1) })
 	}
 
+	go startOutboxRelay(backgroundWorkersCtx, logger, diContainer.GetHealthServer(), diContainer.GetCustomerOutboxRelay())
+	go startCustomerListProjection(
+		backgroundWorkersCtx,
+		logger,
+		diContainer.GetHealthServer(),
+		diContainer.GetCustomerListSubscription(),
+		diContainer.GetCustomerListStore().CustomerListCheckpoint,
+		diContainer.GetChangeNotificationListener(),
+	)
 	go startGRPCServer(config, logger, grpcServer, shutdown)
 
 	waitForStopSignal(logger, shutdown)
@@ -56,6 +82,133 @@ func startGRPCServer(
 	}
 }
 
+// startOutboxRelay restarts the relay if it fails, it continues with the messages which are still in the outbox.
+func startOutboxRelay(
+	ctx context.Context,
+	logger *shared.Logger,
+	healthServer *health.Server,
+	outboxRelay *es.OutboxRelay,
+) {
+
+	logger.Info("starting outbox relay ...")
+
+	superviseBackgroundWorker(
+		ctx,
+		logger,
+		healthServer,
+		cmd.HealthServiceOutboxRelay,
+		backgroundWorkerRestarts,
+		outboxRelay.Run,
+	)
+}
+
+// startCustomerListProjection polls for new events, with Postgres it is also woken up by change notifications.
+// The change notification listener is supervised on its own, until it is back the projection only polls.
+// If the projection fails, it is restarted after the checkpoint which the Customer list has stored.
+func startCustomerListProjection(
+	ctx context.Context,
+	logger *shared.Logger,
+	healthServer *health.Server,
+	subscription *es.CatchUpSubscription,
+	loadCheckpoint func(ctx context.Context) (uint64, error),
+	changeNotificationListener *postgres.ChangeNotificationListener,
+) {
+
+	logger.Info("starting customer list projection ...")
+
+	if changeNotificationListener != nil {
+		go superviseBackgroundWorker(
+			ctx,
+			logger,
+			healthServer,
+			cmd.HealthServiceChangeNotificationListener,
+			backgroundWorkerRestarts,
+			changeNotificationListener.Run,
+		)
+
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-changeNotificationListener.Notifications():
+					subscription.WakeUp()
+				}
+			}
+		}()
+	}
+
+	run := func(ctx context.Context) error {
+		checkpoint, err := loadCheckpoint(ctx)
+		if err != nil {
+			return err
+		}
+
+		subscription.ContinueAfter(checkpoint)
+
+		if err = subscription.Run(ctx); err != nil {
+			return errors.Wrapf(err, "failed at checkpoint %d", subscription.Checkpoint())
+		}
+
+		return nil
+	}
+
+	superviseBackgroundWorker(
+		ctx,
+		logger,
+		healthServer,
+		cmd.HealthServiceCustomerListProjection,
+		backgroundWorkerRestarts,
+		run,
+	)
+}
+
+// superviseBackgroundWorker runs a worker until ctx is cancelled and restarts it whenever it fails.
+// While a failed worker waits for its restart, it is reported as NOT_SERVING in the health checks.
+func superviseBackgroundWorker(
+	ctx context.Context,
+	logger *shared.Logger,
+	healthServer *health.Server,
+	serviceName string,
+	restarts restartPolicy,
+	run func(ctx context.Context) error,
+) {
+
+	delay := restarts.minDelay
+
+	for {
+		healthServer.SetServingStatus(serviceName, healthpb.HealthCheckResponse_SERVING)
+		startedAt := time.Now()
+
+		err := run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			err = errors.New("stopped unexpectedly")
+		}
+
+		healthServer.SetServingStatus(serviceName, healthpb.HealthCheckResponse_NOT_SERVING)
+
+		if time.Since(startedAt) > restarts.maxDelay {
+			delay = restarts.minDelay
+		}
+
+		logger.Errorf("%s failed, restarting it in %s: %s", serviceName, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		if delay *= 2; delay > restarts.maxDelay {
+			delay = restarts.maxDelay
+		}
+	}
+}
+
 func waitForStopSignal(logger *shared.Logger, shutdown func()) {
 	logger.Info("start waiting for stop signal ...")
 
@@ -75,7 +228,8 @@ func waitForStopSignal(logger *shared.Logger, shutdown func()) {
 func shutdown(
 	logger *shared.Logger,
 	grpcServer *grpc.Server,
-	postgresDBConn *sql.DB,
+	stopBackgroundWorkers context.CancelFunc,
+	dbConn *sql.DB,
 	exit func(),
 ) {
 
@@ -86,10 +240,15 @@ func shutdown(
 		grpcServer.GracefulStop()
 	}
 
-	if postgresDBConn != nil {
-		logger.Info("shutdown: closing Postgres DB connection ...")
-		if err := postgresDBConn.Close(); err != nil {
-			logger.Warnf("shutdown: failed to close the Postgres DB connection: %s", err)
+	if stopBackgroundWorkers != nil {
+		logger.Info("shutdown: stopping outbox relay and customer list projection ...")
+		stopBackgroundWorkers()
+	}
+
+	if dbConn != nil {
+		logger.Info("shutdown: closing DB connection ...")
+		if err := dbConn.Close(); err != nil {
+			logger.Warnf("shutdown: failed to close the DB connection: %s", err)
 		}
 	}
 
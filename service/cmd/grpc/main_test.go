@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
@@ -12,36 +13,43 @@ import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
 	. "github.com/smartystreets/goconvey/convey"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
 func TestStartGRPCServer(t *testing.T) {
 	logger := shared.NewNilLogger()
 	config := cmd.MustBuildConfigFromEnv(logger)
-	postgresDBConn := cmd.MustInitPostgresDB(config, logger)
+	useEventStore, dbConn := cmd.MustInitEventStore(config, logger)
 	diContainer := cmd.MustBuildDIContainer(
 		config,
 		logger,
 		cmd.ReplaceGRPCCustomerServer(buildCustomerGRPCServer()),
-		cmd.UsePostgresDBConn(postgresDBConn),
+		useEventStore,
 	)
 	grpcServer := diContainer.GetGRPCServer()
 
+	backgroundWorkersCtx, stopBackgroundWorkers := context.WithCancel(context.Background())
+
 	exitWasCalled := false
 	exit := func() {
 		exitWasCalled = true
 	}
 	myShutdown := func() {
-		shutdown(logger, grpcServer, postgresDBConn, exit)
+		shutdown(logger, grpcServer, stopBackgroundWorkers, dbConn, exit)
 	}
 
 	terminateDelay := time.Millisecond * 100
 
 	Convey("Start the gRPC server as a goroutine", t, func() {
 		go startGRPCServer(config, logger, grpcServer, myShutdown)
+		time.Sleep(20 * time.Millisecond) // otherwise the client might dial before the server listens and back off for 1s
 
 		Convey(fmt.Sprintf("Schedule stop signal to be sent after %s", terminateDelay), func() {
 			start := time.Now()
@@ -57,6 +65,13 @@ func TestStartGRPCServer(t *testing.T) {
 				So(res, ShouldNotBeNil)
 				So(res.Id, ShouldNotBeEmpty)
 
+				healthRes, err := healthpb.NewHealthClient(buildGRPCClientConn(config)).Check(
+					context.Background(),
+					&healthpb.HealthCheckRequest{},
+				)
+				So(err, ShouldBeNil)
+				So(healthRes.Status, ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+
 				Convey("Start waiting for stop signal", func() {
 					waitForStopSignal(logger, myShutdown)
 
@@ -69,13 +84,19 @@ func TestStartGRPCServer(t *testing.T) {
 								So(err, ShouldBeError)
 								So(status.Code(err), ShouldResemble, codes.Unavailable)
 
-								Convey("Shutdown should close PostgreSQL connection", func() {
-									err := postgresDBConn.Ping()
-									So(err, ShouldBeError)
-									So(err.Error(), ShouldEqual, "sql: database is closed")
+								Convey("Shutdown should stop the outbox relay and the customer list projection", func() {
+									So(backgroundWorkersCtx.Err(), ShouldBeError)
+
+									Convey("Shutdown should close PostgreSQL connection (if Postgres is used)", func() {
+										if dbConn != nil {
+											err := dbConn.Ping()
+											So(err, ShouldBeError)
+											So(err.Error(), ShouldEqual, "sql: database is closed")
+										}
 
-									Convey("Shutdown should call exit", func() {
-										So(exitWasCalled, ShouldBeTrue)
+										Convey("Shutdown should call exit", func() {
+											So(exitWasCalled, ShouldBeTrue)
+										})
 									})
 								})
 							})
@@ -87,36 +108,154 @@ func TestStartGRPCServer(t *testing.T) {
 	})
 }
 
+func TestSuperviseBackgroundWorker(t *testing.T) {
+	logger := shared.NewNilLogger()
+	serviceName := "some-worker"
+
+	Convey("Given a background worker which fails twice and then keeps running", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		healthServer := health.NewServer()
+		var runs int32
+
+		run := func(ctx context.Context) error {
+			if atomic.AddInt32(&runs, 1) <= 2 {
+				return errors.New("mocked error")
+			}
+
+			<-ctx.Done()
+
+			return nil
+		}
+
+		Convey("When it is supervised", func() {
+			stopped := make(chan struct{})
+
+			go func() {
+				superviseBackgroundWorker(ctx, logger, healthServer, serviceName, restartPolicy{10 * time.Millisecond, 40 * time.Millisecond}, run)
+				close(stopped)
+			}()
+
+			Convey("Then it should be restarted until it keeps running and be reported as serving", func() {
+				So(waitFor(func() bool { return atomic.LoadInt32(&runs) == 3 }), ShouldBeTrue)
+				So(servingStatus(healthServer, serviceName), ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+
+				Convey("and it should stop when ctx is cancelled", func() {
+					cancel()
+					So(waitFor(func() bool { return isClosed(stopped) }), ShouldBeTrue)
+				})
+			})
+		})
+
+		Convey("When it is supervised with a long restart delay", func() {
+			stopped := make(chan struct{})
+
+			go func() {
+				superviseBackgroundWorker(ctx, logger, healthServer, serviceName, restartPolicy{time.Hour, time.Hour}, run)
+				close(stopped)
+			}()
+
+			Convey("Then it should be reported as not serving while it waits for the restart", func() {
+				So(waitFor(func() bool {
+					return servingStatus(healthServer, serviceName) == healthpb.HealthCheckResponse_NOT_SERVING
+				}), ShouldBeTrue)
+				So(atomic.LoadInt32(&runs), ShouldEqual, 1)
+
+				Convey("and it should stop when ctx is cancelled", func() {
+					cancel()
+					So(waitFor(func() bool { return isClosed(stopped) }), ShouldBeTrue)
+				})
+			})
+		})
+
+		Reset(cancel)
+	})
+}
+
 /*** Helper functions ***/
 
+func waitFor(condition func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isClosed(channel chan struct{}) bool {
+	select {
+	case <-channel:
+		return true
+	default:
+		return false
+	}
+}
+
+func servingStatus(healthServer *health.Server, serviceName string) healthpb.HealthCheckResponse_ServingStatus {
+	res, err := healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: serviceName})
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN
+	}
+
+	return res.Status
+}
+
 func buildCustomerGRPCServer() customergrpc.CustomerServer {
 	customerServer := customergrpc.NewCustomerServer(
-		func(emailAddress, givenName, familyName string) (value.CustomerID, error) {
+		func(ctx context.Context, emailAddress, givenName, familyName string, messageMeta es.MessageMeta) (value.CustomerID, error) {
 			return value.GenerateCustomerID(), nil
 		},
-		func(customerID, confirmationHash string) error {
+		func(ctx context.Context, customerID, confirmationHash string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID, emailAddress string) error {
+		func(ctx context.Context, customerID string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID, givenName, familyName string) error {
+		func(ctx context.Context, customerID, emailAddress string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID string) error {
+		func(ctx context.Context, customerID, givenName, familyName string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID string) (customer.View, error) {
+		func(ctx context.Context, customerID string, expectedVersion uint, messageMeta es.MessageMeta) error {
+			return nil
+		},
+		func(ctx context.Context, customerID string, messageMeta es.MessageMeta) error {
+			return nil
+		},
+		func(ctx context.Context, customerID string) (customer.View, error) {
+			return customer.View{}, nil
+		},
+		func(ctx context.Context, customerID string, asOfVersion uint, asOfTime string) (customer.View, error) {
+			return customer.View{}, nil
+		},
+		func(ctx context.Context, emailAddress string) (customer.View, error) {
 			return customer.View{}, nil
 		},
+		func(ctx context.Context, customerID string, fromVersion uint, pageSize uint) (customer.History, error) {
+			return customer.History{}, nil
+		},
+		func(
+			ctx context.Context,
+			pageSize uint,
+			pageToken, orderBy, confirmationStatus, deletionStatus, registeredFrom, registeredTo string,
+		) (customer.ListPage, error) {
+			return customer.ListPage{}, nil
+		},
 	)
 
 	return customerServer
 }
 
 func buildCustomerGRPCClient(config *cmd.Config) customergrpc.CustomerClient {
-	grpcClientConn, _ := grpc.DialContext(context.Background(), config.GRPC.HostAndPort, grpc.WithInsecure(), grpc.WithBlock())
-	client := customergrpc.NewCustomerClient(grpcClientConn)
+	client := customergrpc.NewCustomerClient(buildGRPCClientConn(config))
 
 	return client
 }
+
+func buildGRPCClientConn(config *cmd.Config) *grpc.ClientConn {
+	grpcClientConn, _ := grpc.DialContext(context.Background(), config.GRPC.HostAndPort, grpc.WithInsecure(), grpc.WithBlock())
+
+	return grpcClientConn
+}
@@ -52,6 +52,8 @@ func buildRestServer(
 
 	rmux := runtime.NewServeMux(
 		runtime.WithProtoErrorHandler(customerrest.CustomHTTPError),
+		runtime.WithIncomingHeaderMatcher(customerrest.CustomHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(customerrest.CustomOutgoingHeaderMatcher),
 	)
 
 	client := customergrpc.NewCustomerClient(grpcClientConn)
@@ -1,8 +1,10 @@
 package customeraccounts_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/AntonStoeckl/go-iddd/service/cmd"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon"
@@ -13,20 +15,33 @@ import (
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
+var atRetrieveFullCustomerEventStream application.ForRetrievingFullCustomerEventStreams
 var atStartCustomerEventStream application.ForStartingCustomerEventStreams
 var atAppendToCustomerEventStream application.ForAppendingToCustomerEventStreams
 var atPurgeCustomerEventStream application.ForPurgingCustomerEventStreams
+var atMaxFailedConfirmations uint
+var atMessageMeta = es.BuildMessageMeta("acceptance-test", "acceptance-test", "acceptance-test")
+var atAnyVersion = uint(0)
+var atCtx = context.Background()
 
 type acceptanceTestCollaborators struct {
 	registerCustomer            hexagon.ForRegisteringCustomers
 	confirmCustomerEmailAddress hexagon.ForConfirmingCustomerEmailAddresses
+	regenerateConfirmation      hexagon.ForRegeneratingCustomerEmailConfirmations
 	changeCustomerEmailAddress  hexagon.ForChangingCustomerEmailAddresses
 	changeCustomerName          hexagon.ForChangingCustomerNames
 	deleteCustomer              hexagon.ForDeletingCustomers
+	forgetCustomer              hexagon.ForForgettingCustomers
 	customerViewByID            hexagon.ForRetrievingCustomerViews
+	customerViewAt              hexagon.ForRetrievingCustomerViewsAt
+	customerViewByEmailAddress  hexagon.ForRetrievingCustomerViewsByEmailAddress
+	customerHistory             hexagon.ForRetrievingCustomerHistories
+	listCustomers               hexagon.ForListingCustomers
+	customerListSubscription    *es.CatchUpSubscription
 }
 
 type acceptanceTestArtifacts struct {
@@ -57,7 +72,7 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 
 		Convey("\nSCENARIO: A prospective Customer registers her account", func() {
 			Convey(fmt.Sprintf("When a Customer registers as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
-				customerID, err = ac.registerCustomer(aa.emailAddress, aa.givenName, aa.familyName)
+				customerID, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, atMessageMeta)
 				So(err, ShouldBeNil)
 
 				expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
@@ -67,7 +82,7 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 				details += fmt.Sprintf("\n\tIsEmailAddressConfirmed: %t", expectedCustomerView.IsEmailAddressConfirmed)
 
 				Convey(fmt.Sprintf("Then her account should show the data she supplied: %s", details), func() {
-					actualCustomerView, err = ac.customerViewByID(customerID.String())
+					actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 					So(err, ShouldBeNil)
 					So(actualCustomerView, ShouldResemble, expectedCustomerView)
 				})
@@ -79,12 +94,21 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 				customerID, _ = givenCustomerRegistered(aa)
 
 				Convey(fmt.Sprintf("When another Customer registers with the same email address [%s]", aa.emailAddress), func() {
-					_, err = ac.registerCustomer(aa.emailAddress, aa.givenName, aa.familyName)
+					_, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, atMessageMeta)
 
 					Convey("Then she should receive an error", func() {
 						So(err, ShouldBeError)
 					})
 				})
+
+				Convey("When another Customer registers with the same email address in other case [ Fiona@GALLAGHER.net]", func() {
+					_, err = ac.registerCustomer(atCtx, " Fiona@GALLAGHER.net", aa.givenName, aa.familyName, atMessageMeta)
+
+					Convey("Then she should receive an error", func() {
+						So(err, ShouldBeError)
+						So(errors.Is(err, shared.ErrDuplicate), ShouldBeTrue)
+					})
+				})
 			})
 		})
 
@@ -93,11 +117,11 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 				customerID, _ = givenCustomerRegistered(aa)
 
 				Convey("And given the first Customer deleted her account", func() {
-					err = ac.deleteCustomer(customerID.String())
+					err = ac.deleteCustomer(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
 					So(err, ShouldBeNil)
 
 					Convey(fmt.Sprintf("When another Customer registers with the same email address [%s]", aa.emailAddress), func() {
-						otherCustomerID, err = ac.registerCustomer(aa.emailAddress, aa.givenName, aa.familyName)
+						otherCustomerID, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, atMessageMeta)
 
 						Convey("Then she should be able to register", func() {
 							So(err, ShouldBeNil)
@@ -106,11 +130,11 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 				})
 
 				Convey(fmt.Sprintf("Or given the first Customer changed her email address to [%s]", aa.newEmailAddress), func() {
-					err = ac.changeCustomerEmailAddress(customerID.String(), aa.newEmailAddress)
+					err = ac.changeCustomerEmailAddress(atCtx, customerID.String(), aa.newEmailAddress, atAnyVersion, atMessageMeta)
 					So(err, ShouldBeNil)
 
 					Convey(fmt.Sprintf("When another Customer registers with the same email address [%s]", aa.emailAddress), func() {
-						otherCustomerID, err = ac.registerCustomer(aa.emailAddress, aa.givenName, aa.familyName)
+						otherCustomerID, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, atMessageMeta)
 
 						Convey("Then she should be able to register", func() {
 							So(err, ShouldBeNil)
@@ -124,7 +148,7 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 			invalidEmailAddress := "fiona@galagher.c"
 
 			Convey(fmt.Sprintf("When she supplies an invalid email address [%s]", invalidEmailAddress), func() {
-				_, err = ac.registerCustomer(invalidEmailAddress, aa.givenName, aa.familyName)
+				_, err = ac.registerCustomer(atCtx, invalidEmailAddress, aa.givenName, aa.familyName, atMessageMeta)
 
 				Convey("Then she should receive an error", func() {
 					So(err, ShouldBeError)
@@ -133,7 +157,7 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 			})
 
 			Convey("When she supplies an empty givenName", func() {
-				_, err = ac.registerCustomer(aa.emailAddress, "", aa.familyName)
+				_, err = ac.registerCustomer(atCtx, aa.emailAddress, "", aa.familyName, atMessageMeta)
 
 				Convey("Then she should receive an error", func() {
 					So(err, ShouldBeError)
@@ -142,7 +166,7 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 			})
 
 			Convey("When she supplies an empty familyName", func() {
-				_, err = ac.registerCustomer(aa.emailAddress, aa.givenName, "")
+				_, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, "", atMessageMeta)
 
 				Convey("Then she should receive an error", func() {
 					So(err, ShouldBeError)
@@ -152,10 +176,10 @@ func TestCustomerAcceptanceScenarios_ForRegisteringCustomers(t *testing.T) {
 		})
 
 		Reset(func() {
-			err = atPurgeCustomerEventStream(customerID)
+			err = atPurgeCustomerEventStream(atCtx, customerID)
 			So(err, ShouldBeNil)
 
-			err = atPurgeCustomerEventStream(otherCustomerID)
+			err = atPurgeCustomerEventStream(atCtx, otherCustomerID)
 			So(err, ShouldBeNil)
 		})
 	})
@@ -183,11 +207,11 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 				customerID, confirmationHash = givenCustomerRegistered(aa)
 
 				Convey("When he confirms his email address", func() {
-					err = ac.confirmCustomerEmailAddress(customerID.String(), confirmationHash.String())
+					err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
 					So(err, ShouldBeNil)
 
 					Convey("Then his email address should be confirmed", func() {
-						actualCustomerView, err = ac.customerViewByID(customerID.String())
+						actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 						So(err, ShouldBeNil)
 						expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
 						expectedCustomerView.IsEmailAddressConfirmed = true
@@ -195,11 +219,11 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 						So(actualCustomerView, ShouldResemble, expectedCustomerView)
 
 						Convey("And when he confirms his email address again", func() {
-							err = ac.confirmCustomerEmailAddress(customerID.String(), confirmationHash.String())
+							err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
 							So(err, ShouldBeNil)
 
 							Convey("Then his email address should still be confirmed", func() {
-								actualCustomerView, err = ac.customerViewByID(customerID.String())
+								actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 								So(err, ShouldBeNil)
 								So(actualCustomerView, ShouldResemble, expectedCustomerView)
 							})
@@ -214,13 +238,13 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 				customerID, _ = givenCustomerRegistered(aa)
 
 				Convey("When he tries to confirm his email address with a wrong confirmation hash", func() {
-					err = ac.confirmCustomerEmailAddress(customerID.String(), "invalid_confirmation_hash")
+					err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), "invalid_confirmation_hash", atAnyVersion, atMessageMeta)
 
 					Convey("Then he should receive an error", func() {
 						So(errors.Is(err, shared.ErrDomainConstraintsViolation), ShouldBeTrue)
 
 						Convey("And his email address should still be unconfirmed", func() {
-							actualCustomerView, err = ac.customerViewByID(customerID.String())
+							actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 							So(err, ShouldBeNil)
 							expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
 							expectedCustomerView.Version = 2
@@ -231,6 +255,33 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 			})
 		})
 
+		Convey("\nSCENARIO: A Customer is locked out after too many attempts with wrong confirmation hashes", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, confirmationHash = givenCustomerRegistered(aa)
+
+				Convey(fmt.Sprintf("and he tried to confirm his email address %d times with a wrong confirmation hash", atMaxFailedConfirmations), func() {
+					for i := uint(0); i < atMaxFailedConfirmations; i++ {
+						err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), "invalid_confirmation_hash", atAnyVersion, atMessageMeta)
+						So(errors.Is(err, shared.ErrDomainConstraintsViolation), ShouldBeTrue)
+					}
+
+					Convey("When he confirms his email address with the right confirmation hash", func() {
+						err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
+
+						Convey("Then he should receive an error", func() {
+							So(errors.Is(err, shared.ErrTooManyAttempts), ShouldBeTrue)
+
+							Convey("And his email address should still be unconfirmed", func() {
+								actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
+								So(err, ShouldBeNil)
+								So(actualCustomerView.IsEmailAddressConfirmed, ShouldBeFalse)
+							})
+						})
+					})
+				})
+			})
+		})
+
 		Convey("\nSCENARIO: A Customer fails to confirm his already confirmed email address", func() {
 			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
 				customerID, _ = givenCustomerRegistered(aa)
@@ -239,13 +290,13 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 					givenCustomerEmailAddressWasConfirmed(customerID, aa, 2)
 
 					Convey("When he tries to confirm his email address again with a wrong confirmation hash", func() {
-						err = ac.confirmCustomerEmailAddress(customerID.String(), "invalid_confirmation_hash")
+						err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), "invalid_confirmation_hash", atAnyVersion, atMessageMeta)
 
 						Convey("Then he should receive an error", func() {
 							So(errors.Is(err, shared.ErrDomainConstraintsViolation), ShouldBeTrue)
 
 							Convey("And his email address should still be confirmed", func() {
-								actualCustomerView, err = ac.customerViewByID(customerID.String())
+								actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 								So(err, ShouldBeNil)
 								expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
 								expectedCustomerView.IsEmailAddressConfirmed = true
@@ -269,11 +320,11 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 						confirmationHash = givenCustomerEmailAddressWasChanged(customerID, aa, 3)
 
 						Convey("When he confirms his changed email address", func() {
-							err = ac.confirmCustomerEmailAddress(customerID.String(), confirmationHash.String())
+							err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
 							So(err, ShouldBeNil)
 
 							Convey(fmt.Sprintf("Then his email address should be [%s] and confirmed", aa.newEmailAddress), func() {
-								actualCustomerView, err = ac.customerViewByID(customerID.String())
+								actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 								So(err, ShouldBeNil)
 								expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
 								expectedCustomerView.EmailAddress = aa.newEmailAddress
@@ -292,7 +343,7 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 				customerID, confirmationHash = givenCustomerRegistered(aa)
 
 				Convey("When he supplies an empty confirmation hash", func() {
-					err = ac.confirmCustomerEmailAddress(customerID.String(), "")
+					err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), "", atAnyVersion, atMessageMeta)
 
 					Convey("Then he should receive an error", func() {
 						So(err, ShouldBeError)
@@ -303,7 +354,99 @@ func TestCustomerAcceptanceScenarios_ForConfirmingCustomerEmailAddresses(t *test
 		})
 
 		Reset(func() {
-			err = atPurgeCustomerEventStream(customerID)
+			err = atPurgeCustomerEventStream(atCtx, customerID)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestCustomerAcceptanceScenarios_ForRegeneratingCustomerEmailConfirmations(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var customerID value.CustomerID
+		var confirmationHash value.ConfirmationHash
+
+		aa := acceptanceTestArtifacts{
+			emailAddress: "kevin@ball.net",
+			givenName:    "Kevin",
+			familyName:   "Ball",
+		}
+
+		Convey("\nSCENARIO: A Customer lost the confirmation email and requests a new confirmation hash", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, confirmationHash = givenCustomerRegistered(aa)
+
+				Convey("When he requests a new confirmation hash", func() {
+					err = ac.regenerateConfirmation(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
+					So(err, ShouldBeNil)
+
+					eventStream, err := atRetrieveFullCustomerEventStream(atCtx, customerID)
+					So(err, ShouldBeNil)
+					regenerated, ok := eventStream[len(eventStream)-1].(domain.CustomerEmailConfirmationRegenerated)
+					So(ok, ShouldBeTrue)
+					So(regenerated.ConfirmationHash().Equals(confirmationHash), ShouldBeFalse)
+
+					Convey("Then he can't confirm his email address with the previous confirmation hash", func() {
+						err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
+						So(errors.Is(err, shared.ErrDomainConstraintsViolation), ShouldBeTrue)
+
+						Convey("But he can confirm it with the new confirmation hash", func() {
+							newConfirmationHash := regenerated.ConfirmationHash().String()
+							err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), newConfirmationHash, atAnyVersion, atMessageMeta)
+							So(err, ShouldBeNil)
+
+							view, err := ac.customerViewByID(atCtx, customerID.String())
+							So(err, ShouldBeNil)
+							So(view.IsEmailAddressConfirmed, ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: A Customer can't request a new confirmation hash, because his email address is confirmed", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, _ = givenCustomerRegistered(aa)
+
+				Convey("and his email address was confirmed", func() {
+					givenCustomerEmailAddressWasConfirmed(customerID, aa, 2)
+
+					Convey("When he requests a new confirmation hash", func() {
+						err = ac.regenerateConfirmation(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
+
+						Convey("Then he should receive an error", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, shared.ErrDomainConstraintsViolation), ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: A Customer can't request a new confirmation hash, because he was deleted", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, _ = givenCustomerRegistered(aa)
+
+				Convey("and he was deleted", func() {
+					err = ac.deleteCustomer(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
+					So(err, ShouldBeNil)
+
+					Convey("When he requests a new confirmation hash", func() {
+						err = ac.regenerateConfirmation(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
+
+						Convey("Then he should receive an error", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Reset(func() {
+			err = atPurgeCustomerEventStream(atCtx, customerID)
 			So(err, ShouldBeNil)
 		})
 	})
@@ -334,11 +477,11 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerEmailAddresses(t *testin
 					givenCustomerEmailAddressWasConfirmed(customerID, aa, 2)
 
 					Convey(fmt.Sprintf("When she changes her email address to [%s]", aa.newEmailAddress), func() {
-						err = ac.changeCustomerEmailAddress(customerID.String(), aa.newEmailAddress)
+						err = ac.changeCustomerEmailAddress(atCtx, customerID.String(), aa.newEmailAddress, atAnyVersion, atMessageMeta)
 						So(err, ShouldBeNil)
 
 						Convey(fmt.Sprintf("Then her email address should be [%s] and unconfirmed", aa.newEmailAddress), func() {
-							actualCustomerView, err = ac.customerViewByID(customerID.String())
+							actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 							So(err, ShouldBeNil)
 							expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
 							expectedCustomerView.EmailAddress = aa.newEmailAddress
@@ -346,11 +489,11 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerEmailAddresses(t *testin
 							So(actualCustomerView, ShouldResemble, expectedCustomerView)
 
 							Convey(fmt.Sprintf("And when she tries to change her email address to [%s] again", aa.newEmailAddress), func() {
-								err = ac.changeCustomerEmailAddress(customerID.String(), aa.newEmailAddress)
+								err = ac.changeCustomerEmailAddress(atCtx, customerID.String(), aa.newEmailAddress, atAnyVersion, atMessageMeta)
 								So(err, ShouldBeNil)
 
 								Convey(fmt.Sprintf("Then her email address should still be [%s]", aa.newEmailAddress), func() {
-									actualCustomerView, err = ac.customerViewByID(customerID.String())
+									actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 									So(err, ShouldBeNil)
 									So(actualCustomerView, ShouldResemble, expectedCustomerView)
 								})
@@ -372,7 +515,7 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerEmailAddresses(t *testin
 						otherCustomerID, _ = givenCustomerRegistered(aa)
 
 						Convey(fmt.Sprintf("When she also tries to change her email address to [%s]", aa.newEmailAddress), func() {
-							err = ac.changeCustomerEmailAddress(otherCustomerID.String(), aa.newEmailAddress)
+							err = ac.changeCustomerEmailAddress(atCtx, otherCustomerID.String(), aa.newEmailAddress, atAnyVersion, atMessageMeta)
 
 							Convey("Then she should receive an error", func() {
 								So(err, ShouldBeError)
@@ -391,7 +534,7 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerEmailAddresses(t *testin
 				customerID, _ = givenCustomerRegistered(aa)
 
 				Convey(fmt.Sprintf("When she supplies an invalid email address [%s]", invalidEmailAddress), func() {
-					err = ac.changeCustomerEmailAddress(customerID.String(), invalidEmailAddress)
+					err = ac.changeCustomerEmailAddress(atCtx, customerID.String(), invalidEmailAddress, atAnyVersion, atMessageMeta)
 
 					Convey("Then she should receive an error", func() {
 						So(err, ShouldBeError)
@@ -402,10 +545,10 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerEmailAddresses(t *testin
 		})
 
 		Reset(func() {
-			err = atPurgeCustomerEventStream(customerID)
+			err = atPurgeCustomerEventStream(atCtx, customerID)
 			So(err, ShouldBeNil)
 
-			err = atPurgeCustomerEventStream(otherCustomerID)
+			err = atPurgeCustomerEventStream(atCtx, otherCustomerID)
 			So(err, ShouldBeNil)
 		})
 	})
@@ -433,11 +576,11 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerNames(t *testing.T) {
 				customerID, _ = givenCustomerRegistered(aa)
 
 				Convey(fmt.Sprintf("When he changes his name to [%s %s]", aa.newGivenName, aa.newFamilyName), func() {
-					err = ac.changeCustomerName(customerID.String(), aa.newGivenName, aa.newFamilyName)
+					err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, atMessageMeta)
 					So(err, ShouldBeNil)
 
 					Convey(fmt.Sprintf("Then his name should be [%s %s]", aa.newGivenName, aa.newFamilyName), func() {
-						actualCustomerView, err = ac.customerViewByID(customerID.String())
+						actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 						So(err, ShouldBeNil)
 						expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
 						expectedCustomerView.GivenName = aa.newGivenName
@@ -446,11 +589,11 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerNames(t *testing.T) {
 						So(actualCustomerView, ShouldResemble, expectedCustomerView)
 
 						Convey(fmt.Sprintf("And when he tries to change his name to [%s %s] again", aa.newGivenName, aa.newFamilyName), func() {
-							err = ac.changeCustomerName(customerID.String(), aa.newGivenName, aa.newFamilyName)
+							err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, atMessageMeta)
 							So(err, ShouldBeNil)
 
 							Convey(fmt.Sprintf("Then his name should still be [%s %s]", aa.newGivenName, aa.newFamilyName), func() {
-								actualCustomerView, err = ac.customerViewByID(customerID.String())
+								actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 								So(err, ShouldBeNil)
 								So(actualCustomerView, ShouldResemble, expectedCustomerView)
 							})
@@ -465,7 +608,7 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerNames(t *testing.T) {
 				customerID, _ = givenCustomerRegistered(aa)
 
 				Convey("When he supplies an empty given name", func() {
-					err = ac.changeCustomerName(customerID.String(), "", aa.familyName)
+					err = ac.changeCustomerName(atCtx, customerID.String(), "", aa.familyName, atAnyVersion, atMessageMeta)
 
 					Convey("Then he should receive an error", func() {
 						So(err, ShouldBeError)
@@ -474,7 +617,7 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerNames(t *testing.T) {
 				})
 
 				Convey("When he supplies an empty family name", func() {
-					err = ac.changeCustomerName(customerID.String(), aa.givenName, "")
+					err = ac.changeCustomerName(atCtx, customerID.String(), aa.givenName, "", atAnyVersion, atMessageMeta)
 
 					Convey("Then he should receive an error", func() {
 						So(err, ShouldBeError)
@@ -485,7 +628,7 @@ func TestCustomerAcceptanceScenarios_ForChangingCustomerNames(t *testing.T) {
 		})
 
 		Reset(func() {
-			err = atPurgeCustomerEventStream(customerID)
+			err = atPurgeCustomerEventStream(atCtx, customerID)
 			So(err, ShouldBeNil)
 		})
 	})
@@ -570,11 +713,11 @@ func TestCustomerAcceptanceScenarios_ForDeletingCustomers(t *testing.T) {
 				customerID, confirmationHash = givenCustomerRegistered(aa)
 
 				Convey("When she deletes her account", func() {
-					err = ac.deleteCustomer(customerID.String())
+					err = ac.deleteCustomer(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
 					So(err, ShouldBeNil)
 
 					Convey("And when she tries to retrieve her account data", func() {
-						actualCustomerView, err = ac.customerViewByID(customerID.String())
+						actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 
 						Convey("Then she should receive an error", func() {
 							So(err, ShouldBeError)
@@ -584,11 +727,11 @@ func TestCustomerAcceptanceScenarios_ForDeletingCustomers(t *testing.T) {
 					})
 
 					Convey("And when she tries to delete her account again", func() {
-						err = ac.deleteCustomer(customerID.String())
+						err = ac.deleteCustomer(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
 						So(err, ShouldBeNil)
 
 						Convey("Then her account should still be deleted", func() {
-							actualCustomerView, err = ac.customerViewByID(customerID.String())
+							actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
 							So(err, ShouldBeError)
 							So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
 							So(actualCustomerView, ShouldBeZeroValue)
@@ -596,7 +739,7 @@ func TestCustomerAcceptanceScenarios_ForDeletingCustomers(t *testing.T) {
 					})
 
 					Convey("And when she tries to confirm her email address", func() {
-						err = ac.confirmCustomerEmailAddress(customerID.String(), confirmationHash.String())
+						err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
 
 						Convey("Then she should receive an error", func() {
 							So(err, ShouldBeError)
@@ -605,7 +748,7 @@ func TestCustomerAcceptanceScenarios_ForDeletingCustomers(t *testing.T) {
 					})
 
 					Convey("And when she tries to change her email address", func() {
-						err = ac.changeCustomerEmailAddress(customerID.String(), aa.newEmailAddress)
+						err = ac.changeCustomerEmailAddress(atCtx, customerID.String(), aa.newEmailAddress, atAnyVersion, atMessageMeta)
 
 						Convey("Then she should receive an error", func() {
 							So(err, ShouldBeError)
@@ -614,7 +757,7 @@ func TestCustomerAcceptanceScenarios_ForDeletingCustomers(t *testing.T) {
 					})
 
 					Convey("And when she tries to change her name", func() {
-						err = ac.changeCustomerName(customerID.String(), aa.newGivenName, aa.newFamilyName)
+						err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, atMessageMeta)
 
 						Convey("Then she should receive an error", func() {
 							So(err, ShouldBeError)
@@ -626,12 +769,678 @@ func TestCustomerAcceptanceScenarios_ForDeletingCustomers(t *testing.T) {
 		})
 
 		Reset(func() {
-			err = atPurgeCustomerEventStream(customerID)
+			err = atPurgeCustomerEventStream(atCtx, customerID)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestCustomerAcceptanceScenarios_ForForgettingCustomers(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var customerID value.CustomerID
+		var otherCustomerID value.CustomerID
+		var actualCustomerView customer.View
+
+		aa := acceptanceTestArtifacts{
+			emailAddress: "lisa@simpson.net",
+			givenName:    "Lisa",
+			familyName:   "Simpson",
+		}
+
+		Convey("\nSCENARIO: A Customer wants her personal data to be forgotten", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, atMessageMeta)
+				So(err, ShouldBeNil)
+
+				Convey("When she asks to be forgotten", func() {
+					err = ac.forgetCustomer(atCtx, customerID.String(), atMessageMeta)
+					So(err, ShouldBeNil)
+
+					Convey("Then her account should be deleted", func() {
+						actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
+						So(err, ShouldBeError)
+						So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+						So(actualCustomerView, ShouldBeZeroValue)
+
+						Convey("And her events should be kept with redacted personal data", func() {
+							eventStream, err := atRetrieveFullCustomerEventStream(atCtx, customerID)
+							So(err, ShouldBeNil)
+							So(eventStream, ShouldHaveLength, 2)
+
+							registered, ok := eventStream[0].(domain.CustomerRegistered)
+							So(ok, ShouldBeTrue)
+							So(registered.EmailAddress().String(), ShouldEqual, es.RedactedPII)
+							So(registered.PersonName().GivenName(), ShouldEqual, es.RedactedPII)
+							So(registered.PersonName().FamilyName(), ShouldEqual, es.RedactedPII)
+						})
+
+						Convey("And when she asks to be forgotten again", func() {
+							err = ac.forgetCustomer(atCtx, customerID.String(), atMessageMeta)
+
+							Convey("Then it should succeed", func() {
+								So(err, ShouldBeNil)
+							})
+						})
+
+						Convey(fmt.Sprintf("And when another Customer registers with her email address [%s]", aa.emailAddress), func() {
+							otherCustomerID, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, atMessageMeta)
+
+							Convey("Then it should succeed", func() {
+								So(err, ShouldBeNil)
+
+								Reset(func() {
+									err = atPurgeCustomerEventStream(atCtx, otherCustomerID)
+									So(err, ShouldBeNil)
+								})
+							})
+						})
+					})
+				})
+			})
+		})
+
+		Reset(func() {
+			err = atPurgeCustomerEventStream(atCtx, customerID)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestCustomerAcceptanceScenarios_ForRetrievingCustomerViewsAt(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var customerID value.CustomerID
+		var expectedCustomerView customer.View
+		var actualCustomerView customer.View
+		var registeredAt string
+
+		aa := acceptanceTestArtifacts{
+			emailAddress:  "veronica@fisher.net",
+			givenName:     "Veronica",
+			familyName:    "Fisher",
+			newGivenName:  "Vee",
+			newFamilyName: "Fisher",
+		}
+
+		Convey("\nSCENARIO: A Customer's account is retrieved as it was in the past", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, _ = givenCustomerRegistered(aa)
+				registeredAt = time.Now().Format(time.RFC3339Nano)
+				expectedCustomerView = buildDefaultCustomerViewForAcceptanceTest(customerID, aa)
+
+				Convey(fmt.Sprintf("And given she changed her name to [%s %s]", aa.newGivenName, aa.newFamilyName), func() {
+					err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, atMessageMeta)
+					So(err, ShouldBeNil)
+
+					Convey("When her account is retrieved as of version 1", func() {
+						actualCustomerView, err = ac.customerViewAt(atCtx, customerID.String(), 1, "")
+
+						Convey(fmt.Sprintf("Then it should show her name as [%s %s]", aa.givenName, aa.familyName), func() {
+							So(err, ShouldBeNil)
+							So(actualCustomerView, ShouldResemble, expectedCustomerView)
+						})
+					})
+
+					Convey("When her account is retrieved as of the time right after she registered", func() {
+						actualCustomerView, err = ac.customerViewAt(atCtx, customerID.String(), 0, registeredAt)
+
+						Convey(fmt.Sprintf("Then it should show her name as [%s %s]", aa.givenName, aa.familyName), func() {
+							So(err, ShouldBeNil)
+							So(actualCustomerView, ShouldResemble, expectedCustomerView)
+						})
+					})
+
+					Convey("When her account is retrieved as of a version which does not exist yet", func() {
+						actualCustomerView, err = ac.customerViewAt(atCtx, customerID.String(), 99, "")
+
+						Convey(fmt.Sprintf("Then it should show her current name [%s %s]", aa.newGivenName, aa.newFamilyName), func() {
+							So(err, ShouldBeNil)
+							So(actualCustomerView.GivenName, ShouldEqual, aa.newGivenName)
+							So(actualCustomerView.Version, ShouldEqual, 2)
+						})
+					})
+
+					Convey("When her account is retrieved as of a time before she registered", func() {
+						actualCustomerView, err = ac.customerViewAt(atCtx, customerID.String(), 0, "2000-01-01T00:00:00Z")
+
+						Convey("Then it should not be found", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: A Customer's account is retrieved as it was in the past with invalid input", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, _ = givenCustomerRegistered(aa)
+
+				Convey("When neither a version nor a time is supplied", func() {
+					_, err = ac.customerViewAt(atCtx, customerID.String(), 0, "")
+
+					Convey("Then it should fail", func() {
+						So(err, ShouldBeError)
+						So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+					})
+				})
+
+				Convey("When both a version and a time are supplied", func() {
+					_, err = ac.customerViewAt(atCtx, customerID.String(), 1, time.Now().Format(time.RFC3339Nano))
+
+					Convey("Then it should fail", func() {
+						So(err, ShouldBeError)
+						So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+					})
+				})
+
+				Convey("When a malformed time is supplied", func() {
+					_, err = ac.customerViewAt(atCtx, customerID.String(), 0, "yesterday")
+
+					Convey("Then it should fail", func() {
+						So(err, ShouldBeError)
+						So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+					})
+				})
+			})
+		})
+
+		Reset(func() {
+			err = atPurgeCustomerEventStream(atCtx, customerID)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestCustomerAcceptanceScenarios_ForRetrievingCustomerViewsByEmailAddress(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var customerID value.CustomerID
+		var actualCustomerView customer.View
+
+		aa := acceptanceTestArtifacts{
+			emailAddress:    "kermit@alibhai.net",
+			givenName:       "Kermit",
+			familyName:      "Alibhai",
+			newEmailAddress: "kermit@alibhai.com",
+		}
+
+		Convey("\nSCENARIO: A support agent retrieves a Customer's account by email address", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, _ = givenCustomerRegistered(aa)
+
+				Convey(fmt.Sprintf("When the account is retrieved by [%s]", aa.emailAddress), func() {
+					actualCustomerView, err = ac.customerViewByEmailAddress(atCtx, aa.emailAddress)
+
+					Convey("Then it should be the Customer's account", func() {
+						So(err, ShouldBeNil)
+						So(actualCustomerView, ShouldResemble, buildDefaultCustomerViewForAcceptanceTest(customerID, aa))
+					})
+				})
+
+				Convey("When the account is retrieved by the email address in other case [Kermit@Alibhai.NET]", func() {
+					actualCustomerView, err = ac.customerViewByEmailAddress(atCtx, "Kermit@Alibhai.NET")
+
+					Convey("Then it should be the Customer's account, showing the email address as she entered it", func() {
+						So(err, ShouldBeNil)
+						So(actualCustomerView, ShouldResemble, buildDefaultCustomerViewForAcceptanceTest(customerID, aa))
+					})
+				})
+
+				Convey(fmt.Sprintf("And given she changed her email address to [%s]", aa.newEmailAddress), func() {
+					_ = givenCustomerEmailAddressWasChanged(customerID, aa, 2)
+
+					Convey(fmt.Sprintf("When the account is retrieved by [%s]", aa.newEmailAddress), func() {
+						actualCustomerView, err = ac.customerViewByEmailAddress(atCtx, aa.newEmailAddress)
+
+						Convey("Then it should be the Customer's account", func() {
+							So(err, ShouldBeNil)
+							So(actualCustomerView.ID, ShouldEqual, customerID.String())
+							So(actualCustomerView.EmailAddress, ShouldEqual, aa.newEmailAddress)
+						})
+					})
+
+					Convey(fmt.Sprintf("When the account is retrieved by the previous email address [%s]", aa.emailAddress), func() {
+						_, err = ac.customerViewByEmailAddress(atCtx, aa.emailAddress)
+
+						Convey("Then it should not be found", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+						})
+					})
+				})
+
+				Convey("And given the Customer was deleted", func() {
+					err = ac.deleteCustomer(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
+					So(err, ShouldBeNil)
+
+					Convey(fmt.Sprintf("When the account is retrieved by [%s]", aa.emailAddress), func() {
+						_, err = ac.customerViewByEmailAddress(atCtx, aa.emailAddress)
+
+						Convey("Then it should not be found", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: A support agent retrieves a Customer's account by an invalid email address", func() {
+			Convey("When an account is retrieved by [kermit@alibhai]", func() {
+				_, err = ac.customerViewByEmailAddress(atCtx, "kermit@alibhai")
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldBeError)
+					So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+				})
+			})
+		})
+
+		Reset(func() {
+			err = atPurgeCustomerEventStream(atCtx, customerID)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestCustomerAcceptanceScenarios_ForRetrievingCustomerHistories(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var customerID value.CustomerID
+		var confirmationHash value.ConfirmationHash
+		var history customer.History
+
+		aa := acceptanceTestArtifacts{
+			emailAddress:  "carl@gallagher.net",
+			givenName:     "Carl",
+			familyName:    "Gallagher",
+			newGivenName:  "Carlos",
+			newFamilyName: "Gallagher",
+		}
+
+		Convey("\nSCENARIO: An auditor retrieves the history of a Customer's account page by page", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, confirmationHash = givenCustomerRegistered(aa)
+
+				Convey("And given he confirmed his email address", func() {
+					givenCustomerEmailAddressWasConfirmed(customerID, aa, 2)
+
+					Convey(fmt.Sprintf("And given he changed his name to [%s %s]", aa.newGivenName, aa.newFamilyName), func() {
+						err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, atMessageMeta)
+						So(err, ShouldBeNil)
+
+						Convey("When the first page with two events is retrieved", func() {
+							history, err = ac.customerHistory(atCtx, customerID.String(), 0, 2)
+
+							Convey("Then it should contain his registration and confirmation", func() {
+								So(err, ShouldBeNil)
+								So(history.Events, ShouldHaveLength, 2)
+								So(history.Events[0].EventName, ShouldEqual, "CustomerRegistered")
+								So(history.Events[0].Version, ShouldEqual, 1)
+								So(history.Events[0].Payload["emailAddress"], ShouldEqual, aa.emailAddress)
+								So(history.Events[1].EventName, ShouldEqual, "CustomerEmailAddressConfirmed")
+								So(history.NextFromVersion, ShouldEqual, 3)
+
+								Convey("And it should not expose the confirmation hash", func() {
+									for _, entry := range history.Events {
+										for _, field := range entry.Payload {
+											So(field, ShouldNotEqual, confirmationHash.String())
+										}
+									}
+								})
+
+								Convey("And when the next page is retrieved", func() {
+									history, err = ac.customerHistory(atCtx, customerID.String(), history.NextFromVersion, 2)
+
+									Convey("Then it should contain his name change and be the last page", func() {
+										So(err, ShouldBeNil)
+										So(history.Events, ShouldHaveLength, 1)
+										So(history.Events[0].EventName, ShouldEqual, "CustomerNameChanged")
+										So(history.Events[0].Payload["givenName"], ShouldEqual, aa.newGivenName)
+										So(history.NextFromVersion, ShouldEqual, 0)
+									})
+								})
+							})
+						})
+
+						Convey("And given his account was deleted", func() {
+							err = ac.deleteCustomer(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
+							So(err, ShouldBeNil)
+
+							Convey("When the history is retrieved", func() {
+								history, err = ac.customerHistory(atCtx, customerID.String(), 0, 0)
+
+								Convey("Then it should still contain all events, including the deletion", func() {
+									So(err, ShouldBeNil)
+									So(history.Events, ShouldHaveLength, 4)
+									So(history.Events[3].EventName, ShouldEqual, "CustomerDeleted")
+								})
+							})
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: An auditor retrieves the history of a Customer who was never registered", func() {
+			Convey("When the history is retrieved", func() {
+				_, err = ac.customerHistory(atCtx, value.GenerateCustomerID().String(), 0, 0)
+
+				Convey("Then it should not be found", func() {
+					So(err, ShouldBeError)
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+				})
+			})
+		})
+
+		Reset(func() {
+			err = atPurgeCustomerEventStream(atCtx, customerID)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestCustomerAcceptanceScenarios_ForRetryingCommandsWithIdempotencyKeys(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var customerID value.CustomerID
+		var retriedCustomerID value.CustomerID
+		var actualCustomerView customer.View
+
+		aa := acceptanceTestArtifacts{
+			emailAddress:    "fiona@gallagher.net",
+			givenName:       "Fiona",
+			familyName:      "Gallagher",
+			newEmailAddress: "fiona@lishman.net",
+			newGivenName:    "Fiona",
+			newFamilyName:   "Lishman",
+		}
+
+		messageMeta := atMessageMeta.WithIdempotencyKey(uuid.New().String())
+
+		Convey("\nSCENARIO: The app of a prospective Customer retries her registration", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s] with an idempotency key", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, messageMeta)
+				So(err, ShouldBeNil)
+
+				Convey("When her app retries the registration with the same idempotency key", func() {
+					retriedCustomerID, err = ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, messageMeta)
+
+					Convey("Then she should receive the ID of the account which was registered first", func() {
+						So(err, ShouldBeNil)
+						So(retriedCustomerID.Equals(customerID), ShouldBeTrue)
+					})
+				})
+
+				Convey("When her app uses the same idempotency key for a registration with other data", func() {
+					_, err = ac.registerCustomer(atCtx, aa.newEmailAddress, aa.givenName, aa.familyName, messageMeta)
+
+					Convey("Then she should receive an error", func() {
+						So(err, ShouldBeError)
+						So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: The app of a Customer retries a name change after she changed her name again", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, _ = givenCustomerRegistered(aa)
+
+				Convey(fmt.Sprintf("And given she changed her name to [%s %s] with an idempotency key", aa.newGivenName, aa.newFamilyName), func() {
+					err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, messageMeta)
+					So(err, ShouldBeNil)
+
+					Convey(fmt.Sprintf("And given she changed her name back to [%s %s]", aa.givenName, aa.familyName), func() {
+						err = ac.changeCustomerName(atCtx, customerID.String(), aa.givenName, aa.familyName, atAnyVersion, atMessageMeta)
+						So(err, ShouldBeNil)
+
+						Convey("When her app retries the first name change with the same idempotency key", func() {
+							err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, messageMeta)
+							So(err, ShouldBeNil)
+
+							Convey(fmt.Sprintf("Then her name should still be [%s %s]", aa.givenName, aa.familyName), func() {
+								actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
+								So(err, ShouldBeNil)
+								So(actualCustomerView.GivenName, ShouldEqual, aa.givenName)
+								So(actualCustomerView.FamilyName, ShouldEqual, aa.familyName)
+							})
+						})
+					})
+				})
+			})
+		})
+
+		Reset(func() {
+			err = atPurgeCustomerEventStream(atCtx, customerID)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestCustomerAcceptanceScenarios_ForChangingCustomersWithExpectedVersions(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var customerID value.CustomerID
+		var seenCustomerView customer.View
+		var actualCustomerView customer.View
+
+		aa := acceptanceTestArtifacts{
+			emailAddress:    "fiona@gallagher.net",
+			givenName:       "Fiona",
+			familyName:      "Gallagher",
+			newEmailAddress: "fiona@lishman.net",
+			newGivenName:    "Fiona",
+			newFamilyName:   "Lishman",
+		}
+
+		Convey("\nSCENARIO: Two agents edit the same Customer", func() {
+			Convey(fmt.Sprintf("Given a Customer registered as [%s %s] with [%s]", aa.givenName, aa.familyName, aa.emailAddress), func() {
+				customerID, _ = givenCustomerRegistered(aa)
+
+				Convey("And given both agents have seen her account", func() {
+					seenCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
+					So(err, ShouldBeNil)
+
+					Convey(fmt.Sprintf("When the first agent changes her name to [%s %s] with the seen version", aa.newGivenName, aa.newFamilyName), func() {
+						err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, seenCustomerView.Version, atMessageMeta)
+
+						Convey("Then it should succeed", func() {
+							So(err, ShouldBeNil)
+
+							Convey(fmt.Sprintf("When the second agent changes her emailAddress to [%s] with the seen version", aa.newEmailAddress), func() {
+								err = ac.changeCustomerEmailAddress(atCtx, customerID.String(), aa.newEmailAddress, seenCustomerView.Version, atMessageMeta)
+
+								Convey("Then it should fail because her account has changed in the meantime", func() {
+									So(err, ShouldBeError)
+									So(errors.Is(err, shared.ErrVersionMismatch), ShouldBeTrue)
+
+									Convey(fmt.Sprintf("and her emailAddress should still be [%s]", aa.emailAddress), func() {
+										actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
+										So(err, ShouldBeNil)
+										So(actualCustomerView.EmailAddress, ShouldEqual, aa.emailAddress)
+									})
+								})
+							})
+
+							Convey("When the second agent deletes her account with the seen version", func() {
+								err = ac.deleteCustomer(atCtx, customerID.String(), seenCustomerView.Version, atMessageMeta)
+
+								Convey("Then it should fail because her account has changed in the meantime", func() {
+									So(err, ShouldBeError)
+									So(errors.Is(err, shared.ErrVersionMismatch), ShouldBeTrue)
+								})
+							})
+						})
+					})
+				})
+			})
+		})
+
+		Reset(func() {
+			err = atPurgeCustomerEventStream(atCtx, customerID)
 			So(err, ShouldBeNil)
 		})
 	})
 }
 
+func TestCustomerAcceptanceScenarios_ForListingCustomers(t *testing.T) {
+	ac := bootstrapAcceptanceTestCollaborators()
+
+	projectionCtx, stopProjection := context.WithCancel(atCtx)
+	defer stopProjection()
+
+	go func() { _ = ac.customerListSubscription.Run(projectionCtx) }()
+
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var page customer.ListPage
+		var customerIDs []value.CustomerID
+
+		registeredFrom := time.Now().Format(time.RFC3339Nano)
+
+		for _, aa := range []acceptanceTestArtifacts{
+			{emailAddress: "frank@gallagher.net", givenName: "Frank", familyName: "Gallagher"},
+			{emailAddress: "mickey@milkovich.net", givenName: "Mickey", familyName: "Milkovich"},
+			{emailAddress: "kevin@ball.net", givenName: "Kevin", familyName: "Ball"},
+		} {
+			customerID, err := ac.registerCustomer(atCtx, aa.emailAddress, aa.givenName, aa.familyName, atMessageMeta)
+			So(err, ShouldBeNil)
+			customerIDs = append(customerIDs, customerID)
+		}
+
+		eventStream, err := atRetrieveFullCustomerEventStream(atCtx, customerIDs[0])
+		So(err, ShouldBeNil)
+		confirmationHash := eventStream[0].(domain.CustomerRegistered).ConfirmationHash()
+		err = ac.confirmCustomerEmailAddress(atCtx, customerIDs[0].String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
+		So(err, ShouldBeNil)
+
+		err = ac.forgetCustomer(atCtx, customerIDs[2].String(), atMessageMeta)
+		So(err, ShouldBeNil)
+
+		listCustomers := func(pageSize uint, pageToken, orderBy, confirmationStatus, deletionStatus string) {
+			page, err = ac.listCustomers(atCtx, pageSize, pageToken, orderBy, confirmationStatus, deletionStatus, registeredFrom, "")
+		}
+
+		// the list is a projection, so it must catch up with the events first - the confirmation is the last
+		// event it has to handle, forgetting the Customer is also applied to the list directly
+		for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+			ac.customerListSubscription.WakeUp()
+			listCustomers(0, "", "", application.CustomerListStatusConfirmed, "")
+			So(err, ShouldBeNil)
+
+			if len(page.Customers) == 1 {
+				break
+			}
+		}
+
+		Convey("\nSCENARIO: A back-office user lists the active Customers page by page", func() {
+			Convey("When the first page with one Customer is listed", func() {
+				listCustomers(1, "", "", "", "")
+
+				Convey("Then it should contain the Customer who registered first", func() {
+					So(err, ShouldBeNil)
+					So(page.Customers, ShouldHaveLength, 1)
+					So(page.Customers[0].ID, ShouldEqual, customerIDs[0].String())
+					So(page.Customers[0].IsEmailAddressConfirmed, ShouldBeTrue)
+					So(page.Customers[0].RegisteredAt, ShouldNotBeEmpty)
+					So(page.NextPageToken, ShouldNotBeEmpty)
+
+					Convey("And when the next page is listed", func() {
+						listCustomers(1, page.NextPageToken, "", "", "")
+
+						Convey("Then it should contain the other active Customer and be the last page", func() {
+							So(err, ShouldBeNil)
+							So(page.Customers, ShouldHaveLength, 1)
+							So(page.Customers[0].ID, ShouldEqual, customerIDs[1].String())
+							So(page.NextPageToken, ShouldBeEmpty)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: A back-office user filters and sorts the Customers", func() {
+			Convey("When the unconfirmed Customers are listed", func() {
+				listCustomers(0, "", "", application.CustomerListStatusUnconfirmed, "")
+
+				Convey("Then only the unconfirmed active Customer should be listed", func() {
+					So(err, ShouldBeNil)
+					So(page.Customers, ShouldHaveLength, 1)
+					So(page.Customers[0].ID, ShouldEqual, customerIDs[1].String())
+				})
+			})
+
+			Convey("When the deleted Customers are listed", func() {
+				listCustomers(0, "", "", "", application.CustomerListStatusDeleted)
+
+				Convey("Then only the forgotten Customer should be listed, with redacted personal data", func() {
+					So(err, ShouldBeNil)
+					So(page.Customers, ShouldHaveLength, 1)
+					So(page.Customers[0].ID, ShouldEqual, customerIDs[2].String())
+					So(page.Customers[0].IsDeleted, ShouldBeTrue)
+					So(page.Customers[0].EmailAddress, ShouldEqual, es.RedactedPII)
+					So(page.Customers[0].FamilyName, ShouldEqual, es.RedactedPII)
+				})
+			})
+
+			Convey("When all Customers are listed by family name in descending order", func() {
+				listCustomers(0, "", "familyName desc", "", application.CustomerListStatusAny)
+
+				Convey("Then they should be listed in this order", func() {
+					So(err, ShouldBeNil)
+					So(page.Customers, ShouldHaveLength, 3)
+					So(page.Customers[0].FamilyName, ShouldEqual, es.RedactedPII) // "[" sorts after the letters
+					So(page.Customers[1].FamilyName, ShouldEqual, "Milkovich")
+					So(page.Customers[2].FamilyName, ShouldEqual, "Gallagher")
+				})
+			})
+		})
+
+		Convey("\nSCENARIO: A back-office user lists the Customers with invalid input", func() {
+			Convey("When the Customers are listed by an unknown field", func() {
+				listCustomers(0, "", "shoeSize", "", "")
+
+				Convey("Then it should fail", func() {
+					So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+				})
+			})
+
+			Convey("When the next page is listed with another order than the first page", func() {
+				listCustomers(1, "", "", "", "")
+				So(err, ShouldBeNil)
+
+				listCustomers(1, page.NextPageToken, "emailAddress", "", "")
+
+				Convey("Then it should fail", func() {
+					So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+				})
+			})
+		})
+
+		Reset(func() {
+			for _, customerID := range customerIDs {
+				err = atPurgeCustomerEventStream(atCtx, customerID)
+				So(err, ShouldBeNil)
+			}
+		})
+	})
+}
+
 func TestCustomerAcceptanceScenarios_WhenCustomerWasNeverRegistered(t *testing.T) {
 	ac := bootstrapAcceptanceTestCollaborators()
 
@@ -650,7 +1459,17 @@ func TestCustomerAcceptanceScenarios_WhenCustomerWasNeverRegistered(t *testing.T
 
 		Convey("\nSCENARIO: A hacker tries to play around with a non existing Customer account by guessing IDs", func() {
 			Convey("When he tries to retrieve data for a non existing account", func() {
-				actualCustomerView, err = ac.customerViewByID(customerID.String())
+				actualCustomerView, err = ac.customerViewByID(atCtx, customerID.String())
+
+				Convey("Then he should receive an error", func() {
+					So(err, ShouldBeError)
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+					So(actualCustomerView, ShouldBeZeroValue)
+				})
+			})
+
+			Convey("And when he tries to retrieve past data for a non existing account", func() {
+				actualCustomerView, err = ac.customerViewAt(atCtx, customerID.String(), 1, "")
 
 				Convey("Then he should receive an error", func() {
 					So(err, ShouldBeError)
@@ -660,7 +1479,7 @@ func TestCustomerAcceptanceScenarios_WhenCustomerWasNeverRegistered(t *testing.T
 			})
 
 			Convey("And when he tries to confirm an email address", func() {
-				err = ac.confirmCustomerEmailAddress(customerID.String(), confirmationHash.String())
+				err = ac.confirmCustomerEmailAddress(atCtx, customerID.String(), confirmationHash.String(), atAnyVersion, atMessageMeta)
 
 				Convey("Then he should receive an error", func() {
 					So(err, ShouldBeError)
@@ -669,7 +1488,7 @@ func TestCustomerAcceptanceScenarios_WhenCustomerWasNeverRegistered(t *testing.T
 			})
 
 			Convey("And when he tries to change an email address", func() {
-				err = ac.changeCustomerEmailAddress(customerID.String(), aa.newEmailAddress)
+				err = ac.changeCustomerEmailAddress(atCtx, customerID.String(), aa.newEmailAddress, atAnyVersion, atMessageMeta)
 
 				Convey("Then he should receive an error", func() {
 					So(err, ShouldBeError)
@@ -678,7 +1497,16 @@ func TestCustomerAcceptanceScenarios_WhenCustomerWasNeverRegistered(t *testing.T
 			})
 
 			Convey("And when he tries to change a name", func() {
-				err = ac.changeCustomerName(customerID.String(), aa.newGivenName, aa.newFamilyName)
+				err = ac.changeCustomerName(atCtx, customerID.String(), aa.newGivenName, aa.newFamilyName, atAnyVersion, atMessageMeta)
+
+				Convey("Then he should receive an error", func() {
+					So(err, ShouldBeError)
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+				})
+			})
+
+			Convey("And when he tries to forget an account", func() {
+				err = ac.forgetCustomer(atCtx, customerID.String(), atMessageMeta)
 
 				Convey("Then he should receive an error", func() {
 					So(err, ShouldBeError)
@@ -687,7 +1515,7 @@ func TestCustomerAcceptanceScenarios_WhenCustomerWasNeverRegistered(t *testing.T
 			})
 
 			Convey("And when he tries to delete an account", func() {
-				err = ac.deleteCustomer(customerID.String())
+				err = ac.deleteCustomer(atCtx, customerID.String(), atAnyVersion, atMessageMeta)
 
 				Convey("Then he should receive an error", func() {
 					So(err, ShouldBeError)
@@ -717,7 +1545,7 @@ func TestCustomerAcceptanceScenarios_InvalidClientInput(t *testing.T) {
 				customerID, confirmationHash = givenCustomerRegistered(aa)
 
 				Convey("When she tries to confirm her email address with an empty id", func() {
-					err = ac.confirmCustomerEmailAddress("", confirmationHash.String())
+					err = ac.confirmCustomerEmailAddress(atCtx, "", confirmationHash.String(), atAnyVersion, atMessageMeta)
 
 					Convey("Then she should receive an error", func() {
 						So(err, ShouldBeError)
@@ -726,7 +1554,7 @@ func TestCustomerAcceptanceScenarios_InvalidClientInput(t *testing.T) {
 				})
 
 				Convey("When she tries to change her email address with an empty id", func() {
-					err = ac.changeCustomerEmailAddress("", aa.emailAddress)
+					err = ac.changeCustomerEmailAddress(atCtx, "", aa.emailAddress, atAnyVersion, atMessageMeta)
 
 					Convey("Then she should receive an error", func() {
 						So(err, ShouldBeError)
@@ -735,7 +1563,7 @@ func TestCustomerAcceptanceScenarios_InvalidClientInput(t *testing.T) {
 				})
 
 				Convey("When she tries to change her name with an empty id", func() {
-					err = ac.changeCustomerName("", aa.givenName, aa.familyName)
+					err = ac.changeCustomerName(atCtx, "", aa.givenName, aa.familyName, atAnyVersion, atMessageMeta)
 
 					Convey("Then she should receive an error", func() {
 						So(err, ShouldBeError)
@@ -744,7 +1572,7 @@ func TestCustomerAcceptanceScenarios_InvalidClientInput(t *testing.T) {
 				})
 
 				Convey("When she tries to delete her account with an empty id", func() {
-					err = ac.deleteCustomer("")
+					err = ac.deleteCustomer(atCtx, "", atAnyVersion, atMessageMeta)
 
 					Convey("Then she should receive an error", func() {
 						So(err, ShouldBeError)
@@ -753,7 +1581,7 @@ func TestCustomerAcceptanceScenarios_InvalidClientInput(t *testing.T) {
 				})
 
 				Convey("When she tries to retrieve her account with an empty id", func() {
-					_, err = ac.customerViewByID("")
+					_, err = ac.customerViewByID(atCtx, "")
 
 					Convey("Then she should receive an error", func() {
 						So(err, ShouldBeError)
@@ -764,7 +1592,7 @@ func TestCustomerAcceptanceScenarios_InvalidClientInput(t *testing.T) {
 		})
 
 		Reset(func() {
-			err = atPurgeCustomerEventStream(customerID)
+			err = atPurgeCustomerEventStream(atCtx, customerID)
 			So(err, ShouldBeNil)
 		})
 	})
@@ -776,7 +1604,7 @@ func givenCustomerRegistered(
 
 	customerID := value.GenerateCustomerID()
 	emailAddress := value.RebuildEmailAddress(aa.emailAddress)
-	confirmationHash := value.GenerateConfirmationHash(emailAddress.String())
+	confirmationHash := value.GenerateConfirmationHash()
 	personName := value.RebuildPersonName(aa.givenName, aa.familyName)
 
 	registered := domain.BuildCustomerRegistered(
@@ -784,10 +1612,11 @@ func givenCustomerRegistered(
 		emailAddress,
 		confirmationHash,
 		personName,
+		es.MessageMeta{},
 		1,
 	)
 
-	err := atStartCustomerEventStream(registered)
+	err := atStartCustomerEventStream(atCtx, registered)
 	So(err, ShouldBeNil)
 
 	return customerID, confirmationHash
@@ -804,10 +1633,11 @@ func givenCustomerEmailAddressWasConfirmed(
 	event := domain.BuildCustomerEmailAddressConfirmed(
 		customerID,
 		emailAddress,
+		es.MessageMeta{},
 		streamVersion,
 	)
 
-	err := atAppendToCustomerEventStream(es.RecordedEvents{event}, customerID)
+	err := atAppendToCustomerEventStream(atCtx, es.RecordedEvents{event}, customerID)
 	So(err, ShouldBeNil)
 }
 
@@ -819,17 +1649,18 @@ func givenCustomerEmailAddressWasChanged(
 
 	emailAddress := value.RebuildEmailAddress(aa.newEmailAddress)
 	previousEmailAddress := value.RebuildEmailAddress(aa.emailAddress)
-	confirmationHash := value.GenerateConfirmationHash(emailAddress.String())
+	confirmationHash := value.GenerateConfirmationHash()
 
 	event := domain.BuildCustomerEmailAddressChanged(
 		customerID,
 		emailAddress,
 		confirmationHash,
 		previousEmailAddress,
+		es.MessageMeta{},
 		streamVersion,
 	)
 
-	err := atAppendToCustomerEventStream(es.RecordedEvents{event}, customerID)
+	err := atAppendToCustomerEventStream(atCtx, es.RecordedEvents{event}, customerID)
 	So(err, ShouldBeNil)
 
 	return confirmationHash
@@ -838,23 +1669,50 @@ func givenCustomerEmailAddressWasChanged(
 func bootstrapAcceptanceTestCollaborators() acceptanceTestCollaborators {
 	logger := shared.NewNilLogger()
 	config := cmd.MustBuildConfigFromEnv(logger)
-	postgresDBConn := cmd.MustInitPostgresDB(config, logger)
-	diContainer := cmd.MustBuildDIContainer(config, logger, cmd.UsePostgresDBConn(postgresDBConn))
+	useEventStore, _ := cmd.MustInitEventStore(config, logger)
+	diContainer := cmd.MustBuildDIContainer(config, logger, useEventStore)
 	eventStore := diContainer.GetCustomerEventStore()
+	atRetrieveFullCustomerEventStream = eventStore.RetrieveFullEventStream
 	atStartCustomerEventStream = eventStore.StartEventStream
 	atAppendToCustomerEventStream = eventStore.AppendToEventStream
 	atPurgeCustomerEventStream = eventStore.PurgeEventStream
+	atMaxFailedConfirmations = config.EmailConfirmation.MaxFailedAttempts
 
 	return acceptanceTestCollaborators{
 		registerCustomer:            diContainer.GetCustomerCommandHandler().RegisterCustomer,
 		confirmCustomerEmailAddress: diContainer.GetCustomerCommandHandler().ConfirmCustomerEmailAddress,
+		regenerateConfirmation:      diContainer.GetCustomerCommandHandler().RegenerateCustomerEmailConfirmation,
 		changeCustomerEmailAddress:  diContainer.GetCustomerCommandHandler().ChangeCustomerEmailAddress,
 		changeCustomerName:          diContainer.GetCustomerCommandHandler().ChangeCustomerName,
 		deleteCustomer:              diContainer.GetCustomerCommandHandler().DeleteCustomer,
+		forgetCustomer:              diContainer.GetCustomerCommandHandler().ForgetCustomer,
 		customerViewByID:            diContainer.GetCustomerQueryHandler().CustomerViewByID,
+		customerViewAt:              diContainer.GetCustomerQueryHandler().CustomerViewAt,
+		customerViewByEmailAddress:  diContainer.GetCustomerQueryHandler().CustomerViewByEmailAddress,
+		customerHistory:             diContainer.GetCustomerQueryHandler().CustomerHistory,
+		listCustomers:               diContainer.GetCustomerQueryHandler().ListCustomers,
+		customerListSubscription:    buildCustomerListSubscriptionForAcceptanceTest(diContainer),
 	}
 }
 
+// buildCustomerListSubscriptionForAcceptanceTest skips gaps almost immediately, instead of the production gapTimeout,
+// because all the purged event streams of the other tests leave gaps and no transactions run concurrently here.
+func buildCustomerListSubscriptionForAcceptanceTest(diContainer *cmd.DIContainer) *es.CatchUpSubscription {
+	checkpoint, err := diContainer.GetCustomerListStore().CustomerListCheckpoint(atCtx)
+	if err != nil {
+		panic(err)
+	}
+
+	return es.NewCatchUpSubscription(
+		diContainer.GetCustomerEventStore().ReadGlobalEvents,
+		diContainer.GetCustomerListProjection().Project,
+		checkpoint,
+		100,
+		10*time.Millisecond,
+		10*time.Millisecond,
+	)
+}
+
 func buildDefaultCustomerViewForAcceptanceTest(
 	customerID value.CustomerID,
 	aa acceptanceTestArtifacts,
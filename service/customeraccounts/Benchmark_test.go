@@ -1,13 +1,14 @@
 package customeraccounts_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/AntonStoeckl/go-iddd/service/cmd"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
-	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/postgres"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
 type benchmarkTestArtifacts struct {
@@ -25,8 +26,8 @@ func BenchmarkCustomerCommand(b *testing.B) {
 
 	logger := shared.NewNilLogger()
 	config := cmd.MustBuildConfigFromEnv(logger)
-	postgresDBConn := cmd.MustInitPostgresDB(config, logger)
-	diContainer := cmd.MustBuildDIContainer(config, logger, cmd.UsePostgresDBConn(postgresDBConn))
+	useEventStore, _ := cmd.MustInitEventStore(config, logger)
+	diContainer := cmd.MustBuildDIContainer(config, logger, useEventStore)
 	commandHandler := diContainer.GetCustomerCommandHandler()
 	ba := buildArtifactsForBenchmarkTest()
 	prepareForBenchmark(b, commandHandler, &ba)
@@ -34,11 +35,11 @@ func BenchmarkCustomerCommand(b *testing.B) {
 	b.Run("ChangeName", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
 			if n%2 == 0 {
-				if err = commandHandler.ChangeCustomerName(ba.customerID.String(), ba.newGivenName, ba.newFamilyName); err != nil {
+				if err = commandHandler.ChangeCustomerName(atCtx, ba.customerID.String(), ba.newGivenName, ba.newFamilyName, 0, es.MessageMeta{}); err != nil {
 					b.FailNow()
 				}
 			} else {
-				if err = commandHandler.ChangeCustomerName(ba.customerID.String(), ba.givenName, ba.familyName); err != nil {
+				if err = commandHandler.ChangeCustomerName(atCtx, ba.customerID.String(), ba.givenName, ba.familyName, 0, es.MessageMeta{}); err != nil {
 					b.FailNow()
 				}
 			}
@@ -47,7 +48,7 @@ func BenchmarkCustomerCommand(b *testing.B) {
 
 	cleanUpAfterBenchmark(
 		b,
-		diContainer.GetCustomerEventStore(),
+		diContainer.GetCustomerEventStore().PurgeEventStream,
 		commandHandler,
 		ba.customerID,
 	)
@@ -56,8 +57,8 @@ func BenchmarkCustomerCommand(b *testing.B) {
 func BenchmarkCustomerQuery(b *testing.B) {
 	logger := shared.NewNilLogger()
 	config := cmd.MustBuildConfigFromEnv(logger)
-	postgresDBConn := cmd.MustInitPostgresDB(config, logger)
-	diContainer := cmd.MustBuildDIContainer(config, logger, cmd.UsePostgresDBConn(postgresDBConn))
+	useEventStore, _ := cmd.MustInitEventStore(config, logger)
+	diContainer := cmd.MustBuildDIContainer(config, logger, useEventStore)
 	commandHandler := diContainer.GetCustomerCommandHandler()
 	queryHandler := diContainer.GetCustomerQueryHandler()
 	ba := buildArtifactsForBenchmarkTest()
@@ -65,7 +66,7 @@ func BenchmarkCustomerQuery(b *testing.B) {
 
 	b.Run("CustomerViewByID", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
-			if _, err := queryHandler.CustomerViewByID(ba.customerID.String()); err != nil {
+			if _, err := queryHandler.CustomerViewByID(atCtx, ba.customerID.String()); err != nil {
 				b.FailNow()
 			}
 		}
@@ -73,12 +74,60 @@ func BenchmarkCustomerQuery(b *testing.B) {
 
 	cleanUpAfterBenchmark(
 		b,
-		diContainer.GetCustomerEventStore(),
+		diContainer.GetCustomerEventStore().PurgeEventStream,
 		commandHandler,
 		ba.customerID,
 	)
 }
 
+func BenchmarkCustomerQueryWithGrowingEventStreams(b *testing.B) {
+	logger := shared.NewNilLogger()
+	config := cmd.MustBuildConfigFromEnv(logger)
+	useEventStore, _ := cmd.MustInitEventStore(config, logger)
+
+	configWithoutSnapshots := *config
+	configWithoutSnapshots.EventStore.SnapshotInterval = 0
+
+	for _, snapshotsEnabled := range []bool{false, true} {
+		currentConfig := config
+		if !snapshotsEnabled {
+			currentConfig = &configWithoutSnapshots
+		}
+
+		diContainer := cmd.MustBuildDIContainer(currentConfig, logger, useEventStore)
+		commandHandler := diContainer.GetCustomerCommandHandler()
+		queryHandler := diContainer.GetCustomerQueryHandler()
+
+		for _, streamLength := range []int{10, 100, 1000} {
+			ba := buildArtifactsForBenchmarkTest()
+			prepareGrowingStreamForBenchmark(
+				b,
+				commandHandler,
+				diContainer.GetCustomerEventStore().RetrieveEventStream,
+				&ba,
+				streamLength,
+			)
+
+			name := fmt.Sprintf("CustomerViewByID_snapshots:%t_events:%d", snapshotsEnabled, streamLength)
+
+			b.Run(name, func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					if _, err := queryHandler.CustomerViewByID(atCtx, ba.customerID.String()); err != nil {
+						b.FailNow()
+					}
+				}
+			})
+
+			cleanUpAfterBenchmark(
+				b,
+				diContainer.GetCustomerEventStore().PurgeEventStream,
+				commandHandler,
+				ba.customerID,
+			)
+		}
+	}
+}
+
 func buildArtifactsForBenchmarkTest() benchmarkTestArtifacts {
 	var ba benchmarkTestArtifacts
 
@@ -100,35 +149,73 @@ func prepareForBenchmark(
 
 	var err error
 
-	if ba.customerID, err = commandHandler.RegisterCustomer(ba.emailAddress, ba.givenName, ba.familyName); err != nil {
+	if ba.customerID, err = commandHandler.RegisterCustomer(atCtx, ba.emailAddress, ba.givenName, ba.familyName, es.MessageMeta{}); err != nil {
 		b.FailNow()
 	}
 
 	for n := 0; n < 100; n++ {
 		if n%2 == 0 {
-			if err = commandHandler.ChangeCustomerEmailAddress(ba.customerID.String(), ba.newEmailAddress); err != nil {
+			if err = commandHandler.ChangeCustomerEmailAddress(atCtx, ba.customerID.String(), ba.newEmailAddress, 0, es.MessageMeta{}); err != nil {
+				b.FailNow()
+			}
+		} else {
+			if err = commandHandler.ChangeCustomerEmailAddress(atCtx, ba.customerID.String(), ba.emailAddress, 0, es.MessageMeta{}); err != nil {
+				b.FailNow()
+			}
+		}
+	}
+}
+
+// prepareGrowingStreamForBenchmark grows the stream with CustomerNameChanged events,
+// which always succeed, unlike failed confirmations which run into the lockout.
+func prepareGrowingStreamForBenchmark(
+	b *testing.B,
+	commandHandler *application.CustomerCommandHandler,
+	retrieveEventStream application.ForRetrievingCustomerEventStreams,
+	ba *benchmarkTestArtifacts,
+	streamLength int,
+) {
+
+	var err error
+
+	if ba.customerID, err = commandHandler.RegisterCustomer(atCtx, ba.emailAddress, ba.givenName, ba.familyName, es.MessageMeta{}); err != nil {
+		b.FailNow()
+	}
+
+	for n := 1; n < streamLength; n++ {
+		if n%2 == 1 {
+			if err = commandHandler.ChangeCustomerName(atCtx, ba.customerID.String(), ba.newGivenName, ba.newFamilyName, 0, es.MessageMeta{}); err != nil {
 				b.FailNow()
 			}
 		} else {
-			if err = commandHandler.ChangeCustomerEmailAddress(ba.customerID.String(), ba.emailAddress); err != nil {
+			if err = commandHandler.ChangeCustomerName(atCtx, ba.customerID.String(), ba.givenName, ba.familyName, 0, es.MessageMeta{}); err != nil {
 				b.FailNow()
 			}
 		}
 	}
+
+	eventStream, err := retrieveEventStream(atCtx, ba.customerID)
+	if err != nil || len(eventStream) == 0 {
+		b.FailNow()
+	}
+
+	if eventStream[len(eventStream)-1].Meta().StreamVersion() != uint(streamLength) {
+		b.FailNow()
+	}
 }
 
 func cleanUpAfterBenchmark(
 	b *testing.B,
-	eventstore *postgres.CustomerEventStore,
+	purgeEventStream application.ForPurgingCustomerEventStreams,
 	commandHandler *application.CustomerCommandHandler,
 	id value.CustomerID,
 ) {
 
-	if err := commandHandler.DeleteCustomer(id.String()); err != nil {
+	if err := commandHandler.DeleteCustomer(atCtx, id.String(), 0, es.MessageMeta{}); err != nil {
 		b.FailNow()
 	}
 
-	if err := eventstore.PurgeEventStream(id); err != nil {
+	if err := purgeEventStream(atCtx, id); err != nil {
 		b.FailNow()
 	}
 }
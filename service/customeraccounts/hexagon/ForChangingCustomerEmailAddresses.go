@@ -1,3 +1,14 @@
 package hexagon
 
-type ForChangingCustomerEmailAddresses func(customerID, emailAddress string) error
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type ForChangingCustomerEmailAddresses func(
+	ctx context.Context,
+	customerID, emailAddress string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
+) error
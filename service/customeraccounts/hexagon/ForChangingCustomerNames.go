@@ -1,3 +1,14 @@
 package hexagon
 
-type ForChangingCustomerNames func(customerID, givenName, familyName string) error
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type ForChangingCustomerNames func(
+	ctx context.Context,
+	customerID, givenName, familyName string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
+) error
@@ -1,3 +1,14 @@
 package hexagon
 
-type ForConfirmingCustomerEmailAddresses func(customerID, confirmationHash string) error
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type ForConfirmingCustomerEmailAddresses func(
+	ctx context.Context,
+	customerID, confirmationHash string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
+) error
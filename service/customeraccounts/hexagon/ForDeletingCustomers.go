@@ -1,3 +1,14 @@
 package hexagon
 
-type ForDeletingCustomers func(customerID string) error
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type ForDeletingCustomers func(
+	ctx context.Context,
+	customerID string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
+) error
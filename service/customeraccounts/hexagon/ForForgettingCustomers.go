@@ -0,0 +1,9 @@
+package hexagon
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type ForForgettingCustomers func(ctx context.Context, customerID string, messageMeta es.MessageMeta) error
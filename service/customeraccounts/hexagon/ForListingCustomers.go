@@ -0,0 +1,19 @@
+package hexagon
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+)
+
+// ForListingCustomers expects orderBy like "familyName desc", the statuses and RFC3339 times are optional filters.
+type ForListingCustomers func(
+	ctx context.Context,
+	pageSize uint,
+	pageToken string,
+	orderBy string,
+	confirmationStatus string,
+	deletionStatus string,
+	registeredFrom string,
+	registeredTo string,
+) (customer.ListPage, error)
@@ -0,0 +1,14 @@
+package hexagon
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type ForRegeneratingCustomerEmailConfirmations func(
+	ctx context.Context,
+	customerID string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
+) error
@@ -1,5 +1,14 @@
 package hexagon
 
-import "github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+import (
+	"context"
 
-type ForRegisteringCustomers func(emailAddress, givenName, familyName string) (value.CustomerID, error)
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type ForRegisteringCustomers func(
+	ctx context.Context,
+	emailAddress, givenName, familyName string,
+	messageMeta es.MessageMeta,
+) (value.CustomerID, error)
@@ -0,0 +1,16 @@
+package hexagon
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+)
+
+// ForRetrievingCustomerHistories returns a page of at most pageSize events, starting at fromVersion.
+// The next page starts at the NextFromVersion of the returned History.
+type ForRetrievingCustomerHistories func(
+	ctx context.Context,
+	customerID string,
+	fromVersion uint,
+	pageSize uint,
+) (customer.History, error)
@@ -1,7 +1,9 @@
 package hexagon
 
 import (
+	"context"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
 )
 
-type ForRetrievingCustomerViews func(customerID string) (customer.View, error)
+type ForRetrievingCustomerViews func(ctx context.Context, customerID string) (customer.View, error)
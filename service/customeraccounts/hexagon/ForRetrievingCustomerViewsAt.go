@@ -0,0 +1,15 @@
+package hexagon
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+)
+
+// ForRetrievingCustomerViewsAt expects exactly one of asOfVersion (> 0) or asOfTime (RFC3339) to be set.
+type ForRetrievingCustomerViewsAt func(
+	ctx context.Context,
+	customerID string,
+	asOfVersion uint,
+	asOfTime string,
+) (customer.View, error)
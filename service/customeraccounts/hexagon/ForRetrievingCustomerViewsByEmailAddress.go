@@ -0,0 +1,9 @@
+package hexagon
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+)
+
+type ForRetrievingCustomerViewsByEmailAddress func(ctx context.Context, emailAddress string) (customer.View, error)
@@ -0,0 +1,10 @@
+package hexagon
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// ForSendingConfirmationEmails ignores all events which do not require the Customer to confirm an email address.
+type ForSendingConfirmationEmails func(ctx context.Context, event es.DomainEvent) error
@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+// ConfirmationEmailSender sends the confirmation hash to the email address of a Customer,
+// after the Customer was registered, has changed the email address or requested a new confirmation hash.
+// Events are handled some time after they were recorded, so no email is sent if the Customer was deleted
+// or the email address was confirmed or changed in the meantime.
+// A regenerated confirmation hash makes the previous emails useless, but they are sent anyway.
+type ConfirmationEmailSender struct {
+	retrieveCustomerEventStream ForRetrievingCustomerEventStreams
+	sendEmail                   ForSendingEmails
+	subjectTemplate             *template.Template
+	bodyTemplate                *template.Template
+}
+
+type confirmationEmailData struct {
+	CustomerID             string
+	EmailAddress           string
+	ConfirmationHash       string
+	GivenName              string
+	FamilyName             string
+	EmailAddressWasChanged bool
+	WasRegenerated         bool
+}
+
+func NewConfirmationEmailSender(
+	retrieveCustomerEventStream ForRetrievingCustomerEventStreams,
+	sendEmail ForSendingEmails,
+	locale string,
+) (*ConfirmationEmailSender, error) {
+
+	templates, ok := confirmationEmailTemplates[locale]
+	if !ok {
+		err := errors.Newf("locale [%s] is not supported for confirmation emails", locale)
+		return nil, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, "NewConfirmationEmailSender")
+	}
+
+	sender := &ConfirmationEmailSender{
+		retrieveCustomerEventStream: retrieveCustomerEventStream,
+		sendEmail:                   sendEmail,
+		subjectTemplate:             template.Must(template.New("subject").Parse(templates.subject)),
+		bodyTemplate:                template.Must(template.New("body").Parse(templates.body)),
+	}
+
+	return sender, nil
+}
+
+func (s *ConfirmationEmailSender) SendConfirmationEmail(ctx context.Context, event es.DomainEvent) error {
+	var data confirmationEmailData
+	var customerID value.CustomerID
+
+	wrapWithMsg := "confirmationEmailSender.SendConfirmationEmail"
+
+	switch actualEvent := event.(type) {
+	case domain.CustomerRegistered:
+		customerID = actualEvent.CustomerID()
+		data.EmailAddress = actualEvent.EmailAddress().String()
+		data.ConfirmationHash = actualEvent.ConfirmationHash().String()
+	case domain.CustomerEmailAddressChanged:
+		customerID = actualEvent.CustomerID()
+		data.EmailAddress = actualEvent.EmailAddress().String()
+		data.ConfirmationHash = actualEvent.ConfirmationHash().String()
+		data.EmailAddressWasChanged = true
+	case domain.CustomerEmailConfirmationRegenerated:
+		customerID = actualEvent.CustomerID()
+		data.EmailAddress = actualEvent.EmailAddress().String()
+		data.ConfirmationHash = actualEvent.ConfirmationHash().String()
+		data.WasRegenerated = true
+	default:
+		return nil
+	}
+
+	eventStream, err := s.retrieveCustomerEventStream(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return nil // the stream was purged
+		}
+
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	customerView := customer.BuildViewFrom(eventStream)
+
+	if customerView.IsDeleted ||
+		customerView.IsEmailAddressConfirmed ||
+		customerView.EmailAddress != data.EmailAddress ||
+		data.EmailAddress == es.RedactedPII {
+
+		return nil
+	}
+
+	data.CustomerID = customerView.ID
+	data.GivenName = customerView.GivenName
+	data.FamilyName = customerView.FamilyName
+
+	email, err := s.render(data)
+	if err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	if err = s.sendEmail(ctx, email); err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	return nil
+}
+
+func (s *ConfirmationEmailSender) render(data confirmationEmailData) (Email, error) {
+	subject := &strings.Builder{}
+	if err := s.subjectTemplate.Execute(subject, data); err != nil {
+		return Email{}, err
+	}
+
+	body := &strings.Builder{}
+	if err := s.bodyTemplate.Execute(body, data); err != nil {
+		return Email{}, err
+	}
+
+	return Email{To: data.EmailAddress, Subject: subject.String(), Body: body.String()}, nil
+}
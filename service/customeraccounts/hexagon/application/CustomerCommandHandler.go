@@ -1,38 +1,64 @@
 package application
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
 )
 
-const maxCustomerCommandHandlerRetries = uint8(10)
+const (
+	maxCustomerCommandHandlerRetries = uint8(10)
+	maxIdempotencyKeyLength          = 255
+)
 
 type CustomerCommandHandler struct {
 	retrieveCustomerEventStream ForRetrievingCustomerEventStreams
 	startCustomerEventStream    ForStartingCustomerEventStreams
 	appendToCustomerEventStream ForAppendingToCustomerEventStreams
+	shredCustomerEncryptionKey  ForShreddingCustomerEncryptionKeys
+	reserveIdempotencyKey       ForReservingIdempotencyKeys
+	recordIdempotentResult      ForRecordingIdempotentResults
+	releaseIdempotencyKey       ForReleasingIdempotencyKeys
+	emailConfirmationPolicy     customer.EmailConfirmationPolicy
 }
 
 func NewCustomerCommandHandler(
 	retrieveCustomerEventStream ForRetrievingCustomerEventStreams,
 	startCustomerEventStream ForStartingCustomerEventStreams,
 	appendToCustomerEventStream ForAppendingToCustomerEventStreams,
+	shredCustomerEncryptionKey ForShreddingCustomerEncryptionKeys,
+	reserveIdempotencyKey ForReservingIdempotencyKeys,
+	recordIdempotentResult ForRecordingIdempotentResults,
+	releaseIdempotencyKey ForReleasingIdempotencyKeys,
+	emailConfirmationPolicy customer.EmailConfirmationPolicy,
 ) *CustomerCommandHandler {
 
 	return &CustomerCommandHandler{
 		retrieveCustomerEventStream: retrieveCustomerEventStream,
 		startCustomerEventStream:    startCustomerEventStream,
 		appendToCustomerEventStream: appendToCustomerEventStream,
+		shredCustomerEncryptionKey:  shredCustomerEncryptionKey,
+		reserveIdempotencyKey:       reserveIdempotencyKey,
+		recordIdempotentResult:      recordIdempotentResult,
+		releaseIdempotencyKey:       releaseIdempotencyKey,
+		emailConfirmationPolicy:     emailConfirmationPolicy,
 	}
 }
 
 func (h *CustomerCommandHandler) RegisterCustomer(
+	ctx context.Context,
 	emailAddress string,
 	givenName string,
 	familyName string,
+	messageMeta es.MessageMeta,
 ) (value.CustomerID, error) {
 
 	var err error
@@ -52,30 +78,45 @@ func (h *CustomerCommandHandler) RegisterCustomer(
 	command = domain.BuildRegisterCustomer(
 		value.GenerateCustomerID(),
 		emailAddressValue,
-		value.GenerateConfirmationHash(emailAddressValue.String()),
+		value.GenerateConfirmationHash(),
 		personNameValue,
+		messageMeta,
 	)
 
 	doRegister := func() error {
 		customerRegistered := customer.Register(command)
 
-		if err = h.startCustomerEventStream(customerRegistered); err != nil {
+		if err = h.startCustomerEventStream(ctx, customerRegistered); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	if err = shared.RetryOnConcurrencyConflict(doRegister, maxCustomerCommandHandlerRetries); err != nil {
+	doRegisterWithRetries := func() (string, error) {
+		if err := shared.RetryOnConcurrencyConflict(ctx, doRegister, maxCustomerCommandHandlerRetries); err != nil {
+			return "", err
+		}
+
+		return command.CustomerID().String(), nil
+	}
+
+	fingerprint := idempotencyFingerprint("RegisterCustomer", emailAddress, givenName, familyName)
+
+	customerID, err := h.executeIdempotently(ctx, messageMeta, fingerprint, doRegisterWithRetries)
+	if err != nil {
 		return value.CustomerID{}, errors.Wrap(err, wrapWithMsg)
 	}
 
-	return command.CustomerID(), nil
+	return value.RebuildCustomerID(customerID), nil
 }
 
 func (h *CustomerCommandHandler) ConfirmCustomerEmailAddress(
+	ctx context.Context,
 	customerID string,
 	confirmationHash string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
 ) error {
 
 	var err error
@@ -92,20 +133,24 @@ func (h *CustomerCommandHandler) ConfirmCustomerEmailAddress(
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
-	command = domain.BuildConfirmCustomerEmailAddress(customerIDValue, confirmationHashValue)
+	command = domain.BuildConfirmCustomerEmailAddress(customerIDValue, confirmationHashValue, messageMeta)
 
 	doConfirmEmailAddress := func() error {
-		eventStream, err := h.retrieveCustomerEventStream(command.CustomerID())
+		eventStream, err := h.retrieveCustomerEventStream(ctx, command.CustomerID())
 		if err != nil {
 			return err
 		}
 
-		recordedEvents, err := customer.ConfirmEmailAddress(eventStream, command)
+		if err := assertExpectedVersion(eventStream, expectedVersion); err != nil {
+			return err
+		}
+
+		recordedEvents, err := customer.ConfirmEmailAddress(eventStream, command, h.emailConfirmationPolicy)
 		if err != nil {
 			return err
 		}
 
-		if err := h.appendToCustomerEventStream(recordedEvents, command.CustomerID()); err != nil {
+		if err := h.appendToCustomerEventStream(ctx, recordedEvents, command.CustomerID()); err != nil {
 			return err
 		}
 
@@ -118,7 +163,15 @@ func (h *CustomerCommandHandler) ConfirmCustomerEmailAddress(
 		return nil
 	}
 
-	if err := shared.RetryOnConcurrencyConflict(doConfirmEmailAddress, maxCustomerCommandHandlerRetries); err != nil {
+	fingerprint := idempotencyFingerprint(
+		"ConfirmCustomerEmailAddress",
+		customerID,
+		confirmationHash,
+		strconv.FormatUint(uint64(expectedVersion), 10),
+	)
+
+	_, err = h.executeIdempotently(ctx, messageMeta, fingerprint, withRetries(ctx, doConfirmEmailAddress))
+	if err != nil {
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
@@ -126,8 +179,11 @@ func (h *CustomerCommandHandler) ConfirmCustomerEmailAddress(
 }
 
 func (h *CustomerCommandHandler) ChangeCustomerEmailAddress(
+	ctx context.Context,
 	customerID string,
 	emailAddress string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
 ) error {
 
 	var err error
@@ -144,27 +200,92 @@ func (h *CustomerCommandHandler) ChangeCustomerEmailAddress(
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
-	command = domain.BuildChangeCustomerEmailAddress(customerIDValue, emailAddressValue)
+	command = domain.BuildChangeCustomerEmailAddress(customerIDValue, emailAddressValue, messageMeta)
 
 	doChangeEmailAddress := func() error {
-		eventStream, err := h.retrieveCustomerEventStream(command.CustomerID())
+		eventStream, err := h.retrieveCustomerEventStream(ctx, command.CustomerID())
 		if err != nil {
 			return err
 		}
 
+		if err := assertExpectedVersion(eventStream, expectedVersion); err != nil {
+			return err
+		}
+
 		recordedEvents, err := customer.ChangeEmailAddress(eventStream, command)
 		if err != nil {
 			return err
 		}
 
-		if err := h.appendToCustomerEventStream(recordedEvents, command.CustomerID()); err != nil {
+		if err := h.appendToCustomerEventStream(ctx, recordedEvents, command.CustomerID()); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	fingerprint := idempotencyFingerprint(
+		"ChangeCustomerEmailAddress",
+		customerID,
+		emailAddress,
+		strconv.FormatUint(uint64(expectedVersion), 10),
+	)
+
+	if _, err := h.executeIdempotently(ctx, messageMeta, fingerprint, withRetries(ctx, doChangeEmailAddress)); err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	return nil
+}
+
+func (h *CustomerCommandHandler) RegenerateCustomerEmailConfirmation(
+	ctx context.Context,
+	customerID string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
+) error {
+
+	var err error
+	var command domain.RegenerateCustomerEmailConfirmation
+	wrapWithMsg := "customerCommandHandler.RegenerateCustomerEmailConfirmation"
+
+	customerIDValue, err := value.BuildCustomerID(customerID)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	command = domain.BuildRegenerateCustomerEmailConfirmation(customerIDValue, messageMeta)
+
+	doRegenerateEmailConfirmation := func() error {
+		eventStream, err := h.retrieveCustomerEventStream(ctx, command.CustomerID())
+		if err != nil {
+			return err
+		}
+
+		if err := assertExpectedVersion(eventStream, expectedVersion); err != nil {
+			return err
+		}
+
+		recordedEvents, err := customer.RegenerateEmailConfirmation(eventStream, command)
+		if err != nil {
+			return err
+		}
+
+		if err := h.appendToCustomerEventStream(ctx, recordedEvents, command.CustomerID()); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	if err := shared.RetryOnConcurrencyConflict(doChangeEmailAddress, maxCustomerCommandHandlerRetries); err != nil {
+	fingerprint := idempotencyFingerprint(
+		"RegenerateCustomerEmailConfirmation",
+		customerID,
+		strconv.FormatUint(uint64(expectedVersion), 10),
+	)
+
+	_, err = h.executeIdempotently(ctx, messageMeta, fingerprint, withRetries(ctx, doRegenerateEmailConfirmation))
+	if err != nil {
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
@@ -172,9 +293,12 @@ func (h *CustomerCommandHandler) ChangeCustomerEmailAddress(
 }
 
 func (h *CustomerCommandHandler) ChangeCustomerName(
+	ctx context.Context,
 	customerID string,
 	givenName string,
 	familyName string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
 ) error {
 
 	var err error
@@ -191,34 +315,52 @@ func (h *CustomerCommandHandler) ChangeCustomerName(
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
-	command = domain.BuildChangeCustomerName(customerIDValue, personNameValue)
+	command = domain.BuildChangeCustomerName(customerIDValue, personNameValue, messageMeta)
 
 	doChangeName := func() error {
-		eventStream, err := h.retrieveCustomerEventStream(command.CustomerID())
+		eventStream, err := h.retrieveCustomerEventStream(ctx, command.CustomerID())
 		if err != nil {
 			return err
 		}
 
+		if err := assertExpectedVersion(eventStream, expectedVersion); err != nil {
+			return err
+		}
+
 		recordedEvents, err := customer.ChangeName(eventStream, command)
 		if err != nil {
 			return err
 		}
 
-		if err := h.appendToCustomerEventStream(recordedEvents, command.CustomerID()); err != nil {
+		if err := h.appendToCustomerEventStream(ctx, recordedEvents, command.CustomerID()); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	if err := shared.RetryOnConcurrencyConflict(doChangeName, maxCustomerCommandHandlerRetries); err != nil {
+	fingerprint := idempotencyFingerprint(
+		"ChangeCustomerName",
+		customerID,
+		givenName,
+		familyName,
+		strconv.FormatUint(uint64(expectedVersion), 10),
+	)
+
+	if _, err := h.executeIdempotently(ctx, messageMeta, fingerprint, withRetries(ctx, doChangeName)); err != nil {
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
 	return nil
 }
 
-func (h *CustomerCommandHandler) DeleteCustomer(customerID string) error {
+func (h *CustomerCommandHandler) DeleteCustomer(
+	ctx context.Context,
+	customerID string,
+	expectedVersion uint,
+	messageMeta es.MessageMeta,
+) error {
+
 	var err error
 	var command domain.DeleteCustomer
 	wrapWithMsg := "customerCommandHandler.DeleteCustomer"
@@ -228,26 +370,171 @@ func (h *CustomerCommandHandler) DeleteCustomer(customerID string) error {
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
-	command = domain.BuildDeleteCustomer(customerIDValue)
+	command = domain.BuildDeleteCustomer(customerIDValue, messageMeta)
 
 	doDelete := func() error {
-		eventStream, err := h.retrieveCustomerEventStream(command.CustomerID())
+		eventStream, err := h.retrieveCustomerEventStream(ctx, command.CustomerID())
 		if err != nil {
 			return err
 		}
 
+		if err := assertExpectedVersion(eventStream, expectedVersion); err != nil {
+			return err
+		}
+
 		recordedEvents := customer.Delete(eventStream, command)
 
-		if err := h.appendToCustomerEventStream(recordedEvents, command.CustomerID()); err != nil {
+		if err := h.appendToCustomerEventStream(ctx, recordedEvents, command.CustomerID()); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	if err := shared.RetryOnConcurrencyConflict(doDelete, maxCustomerCommandHandlerRetries); err != nil {
+	fingerprint := idempotencyFingerprint(
+		"DeleteCustomer",
+		customerID,
+		strconv.FormatUint(uint64(expectedVersion), 10),
+	)
+
+	if _, err := h.executeIdempotently(ctx, messageMeta, fingerprint, withRetries(ctx, doDelete)); err != nil {
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
 	return nil
 }
+
+// ForgetCustomer deletes the Customer (if not done yet) and shreds the key which encrypts the Customer's PII,
+// so the events are kept but the PII in them can't be read anymore.
+func (h *CustomerCommandHandler) ForgetCustomer(
+	ctx context.Context,
+	customerID string,
+	messageMeta es.MessageMeta,
+) error {
+
+	var err error
+	var command domain.DeleteCustomer
+	wrapWithMsg := "customerCommandHandler.ForgetCustomer"
+
+	customerIDValue, err := value.BuildCustomerID(customerID)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	command = domain.BuildDeleteCustomer(customerIDValue, messageMeta)
+
+	doDelete := func() error {
+		eventStream, err := h.retrieveCustomerEventStream(ctx, command.CustomerID())
+		if err != nil {
+			return err
+		}
+
+		recordedEvents := customer.Delete(eventStream, command)
+
+		if err := h.appendToCustomerEventStream(ctx, recordedEvents, command.CustomerID()); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	doForget := func() (string, error) {
+		if err := shared.RetryOnConcurrencyConflict(ctx, doDelete, maxCustomerCommandHandlerRetries); err != nil {
+			return "", err
+		}
+
+		return "", h.shredCustomerEncryptionKey(ctx, command.CustomerID())
+	}
+
+	fingerprint := idempotencyFingerprint("ForgetCustomer", customerID)
+
+	if _, err := h.executeIdempotently(ctx, messageMeta, fingerprint, doForget); err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	return nil
+}
+
+/***** local functions for expected versions *****/
+
+// assertExpectedVersion checks the optimistic lock of clients which send the version of the Customer they have seen,
+// an expectedVersion of 0 means that they don't care. Once the stream has moved on, retrying does not help.
+func assertExpectedVersion(eventStream es.EventStream, expectedVersion uint) error {
+	if expectedVersion == 0 || len(eventStream) == 0 {
+		return nil
+	}
+
+	currentVersion := eventStream[len(eventStream)-1].Meta().StreamVersion()
+
+	if currentVersion != expectedVersion {
+		err := errors.Newf("expected version %d but the Customer is at version %d", expectedVersion, currentVersion)
+		return errors.Mark(err, shared.ErrVersionMismatch)
+	}
+
+	return nil
+}
+
+/***** local methods for idempotent commands *****/
+
+// executeIdempotently executes a command only once per idempotency key (if the request has one),
+// replays of the same command within the TTL of the key get the recorded result.
+// Failures are not recorded, so that a retry executes the command again.
+func (h *CustomerCommandHandler) executeIdempotently(
+	ctx context.Context,
+	messageMeta es.MessageMeta,
+	fingerprint string,
+	execute func() (string, error),
+) (string, error) {
+
+	idempotencyKey := messageMeta.IdempotencyKey()
+
+	if idempotencyKey == "" {
+		return execute()
+	}
+
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		err := errors.Newf("idempotency key must not be longer than %d characters", maxIdempotencyKeyLength)
+		return "", errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+
+	result, isReplay, err := h.reserveIdempotencyKey(ctx, idempotencyKey, fingerprint)
+	if err != nil {
+		return "", err
+	}
+
+	if isReplay {
+		return result, nil
+	}
+
+	if result, err = execute(); err != nil {
+		// the key must be released even if ctx was cancelled, otherwise retries would be blocked until it expires
+		if releaseErr := h.releaseIdempotencyKey(context.Background(), idempotencyKey); releaseErr != nil {
+			return "", errors.WithSecondaryError(err, releaseErr)
+		}
+
+		return "", err
+	}
+
+	if err = h.recordIdempotentResult(ctx, idempotencyKey, result); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func withRetries(ctx context.Context, do func() error) func() (string, error) {
+	return func() (string, error) {
+		return "", shared.RetryOnConcurrencyConflict(ctx, do, maxCustomerCommandHandlerRetries)
+	}
+}
+
+// idempotencyFingerprint identifies a command with its input, so that an idempotency key can't be reused for another.
+func idempotencyFingerprint(commandName string, input ...string) string {
+	hash := sha256.New()
+
+	for _, part := range append([]string{commandName}, input...) {
+		_, _ = hash.Write([]byte(strconv.Itoa(len(part)) + ":" + part))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
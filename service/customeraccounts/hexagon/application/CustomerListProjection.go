@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+// CustomerListProjection keeps the Customer list up to date, it handles the events of an es.CatchUpSubscription.
+// Instead of applying each event to the list, it saves the current View of the Customer,
+// so handling an event again after a restart does no harm.
+type CustomerListProjection struct {
+	retrieveCustomerEventStream ForRetrievingCustomerEventStreams
+	saveCustomerListView        ForSavingCustomerListViews
+}
+
+func NewCustomerListProjection(
+	retrieveCustomerEventStream ForRetrievingCustomerEventStreams,
+	saveCustomerListView ForSavingCustomerListViews,
+) *CustomerListProjection {
+
+	return &CustomerListProjection{
+		retrieveCustomerEventStream: retrieveCustomerEventStream,
+		saveCustomerListView:        saveCustomerListView,
+	}
+}
+
+func (p *CustomerListProjection) Project(ctx context.Context, globalEvent es.GlobalEvent) error {
+	wrapWithMsg := "customerListProjection.Project"
+
+	customerEvent, ok := globalEvent.Event().(interface{ CustomerID() value.CustomerID })
+	if !ok {
+		return nil
+	}
+
+	eventStream, err := p.retrieveCustomerEventStream(ctx, customerEvent.CustomerID())
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return nil // the stream was purged
+		}
+
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	listView := customer.ListView{View: customer.BuildViewFrom(eventStream)}
+
+	// events are delivered in global order, so CustomerRegistered is always the first event of a Customer
+	if customerRegistered, ok := globalEvent.Event().(domain.CustomerRegistered); ok {
+		listView.RegisteredAt = customerRegistered.Meta().OccurredAt()
+	}
+
+	if err = p.saveCustomerListView(ctx, listView, globalEvent.GlobalPosition()); err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	return nil
+}
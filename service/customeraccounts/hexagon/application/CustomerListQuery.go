@@ -0,0 +1,181 @@
+package application
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	CustomerListOrderByRegisteredAt = "registeredAt"
+	CustomerListOrderByEmailAddress = "emailAddress"
+	CustomerListOrderByFamilyName   = "familyName"
+
+	CustomerListStatusAny         = "any"
+	CustomerListStatusConfirmed   = "confirmed"
+	CustomerListStatusUnconfirmed = "unconfirmed"
+	CustomerListStatusActive      = "active"
+	CustomerListStatusDeleted     = "deleted"
+
+	defaultCustomerListPageSize = 20
+	maxCustomerListPageSize     = 100
+)
+
+// CustomerListQuery selects a page of the Customer list. The Customers are sorted by OrderBy and then by ID,
+// so that the page can continue after the Cursor of the last Customer of the previous page (keyset pagination).
+type CustomerListQuery struct {
+	Limit                   uint
+	OrderBy                 string // one of the CustomerListOrderBy* constants
+	Descending              bool
+	After                   *CustomerListCursor
+	IsEmailAddressConfirmed *bool
+	IsDeleted               *bool
+	RegisteredFrom          time.Time // inclusive, the zero value means unbounded
+	RegisteredTo            time.Time // exclusive, the zero value means unbounded
+}
+
+// CustomerListCursor is the position of a Customer in the sort order: the value of the OrderBy field and the ID.
+type CustomerListCursor struct {
+	SortKey    string `json:"k"`
+	CustomerID string `json:"id"`
+	OrderBy    string `json:"o"` // to reject tokens of a list with another sort order
+}
+
+func buildCustomerListQuery(
+	pageSize uint,
+	pageToken string,
+	orderBy string,
+	confirmationStatus string,
+	deletionStatus string,
+	registeredFrom string,
+	registeredTo string,
+) (CustomerListQuery, error) {
+
+	var err error
+	query := CustomerListQuery{Limit: pageSize}
+
+	switch {
+	case pageSize == 0:
+		query.Limit = defaultCustomerListPageSize
+	case pageSize > maxCustomerListPageSize:
+		query.Limit = maxCustomerListPageSize
+	}
+
+	if query.OrderBy, query.Descending, err = parseCustomerListOrderBy(orderBy); err != nil {
+		return CustomerListQuery{}, err
+	}
+
+	if pageToken != "" {
+		if query.After, err = decodeCustomerListPageToken(pageToken, orderBy); err != nil {
+			return CustomerListQuery{}, err
+		}
+	}
+
+	switch confirmationStatus {
+	case "", CustomerListStatusAny:
+	case CustomerListStatusConfirmed:
+		query.IsEmailAddressConfirmed = boolPointer(true)
+	case CustomerListStatusUnconfirmed:
+		query.IsEmailAddressConfirmed = boolPointer(false)
+	default:
+		err = errors.Newf("confirmationStatus must be one of any, confirmed, unconfirmed, got [%s]", confirmationStatus)
+		return CustomerListQuery{}, errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+
+	switch deletionStatus {
+	case "", CustomerListStatusActive:
+		query.IsDeleted = boolPointer(false)
+	case CustomerListStatusDeleted:
+		query.IsDeleted = boolPointer(true)
+	case CustomerListStatusAny:
+	default:
+		err = errors.Newf("deletionStatus must be one of active, deleted, any, got [%s]", deletionStatus)
+		return CustomerListQuery{}, errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+
+	if query.RegisteredFrom, err = parseRegisteredAtFilter(registeredFrom); err != nil {
+		return CustomerListQuery{}, err
+	}
+
+	if query.RegisteredTo, err = parseRegisteredAtFilter(registeredTo); err != nil {
+		return CustomerListQuery{}, err
+	}
+
+	return query, nil
+}
+
+// parseCustomerListOrderBy accepts a field with an optional " desc" suffix, e.g. "familyName desc".
+func parseCustomerListOrderBy(orderBy string) (string, bool, error) {
+	field := strings.TrimSuffix(orderBy, " desc")
+	isDescending := field != orderBy
+
+	switch field {
+	case "":
+		return CustomerListOrderByRegisteredAt, isDescending, nil
+	case CustomerListOrderByRegisteredAt, CustomerListOrderByEmailAddress, CustomerListOrderByFamilyName:
+		return field, isDescending, nil
+	default:
+		err := errors.Newf("orderBy must be one of registeredAt, emailAddress, familyName (with desc), got [%s]", orderBy)
+		return "", false, errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+}
+
+func parseRegisteredAtFilter(registeredAt string) (time.Time, error) {
+	if registeredAt == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, registeredAt)
+	if err != nil {
+		return time.Time{}, errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+
+	return parsed, nil
+}
+
+func buildCustomerListPageToken(lastCustomer customer.ListView, orderBy string, query CustomerListQuery) string {
+	cursor := CustomerListCursor{CustomerID: lastCustomer.ID, OrderBy: orderBy}
+
+	switch query.OrderBy {
+	case CustomerListOrderByRegisteredAt:
+		cursor.SortKey = lastCustomer.RegisteredAt
+	case CustomerListOrderByEmailAddress:
+		cursor.SortKey = lastCustomer.EmailAddress
+	case CustomerListOrderByFamilyName:
+		cursor.SortKey = lastCustomer.FamilyName
+	}
+
+	token, _ := jsoniter.ConfigFastest.Marshal(cursor) // err intentionally ignored - it can't happen with these data types
+
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func decodeCustomerListPageToken(pageToken string, orderBy string) (*CustomerListCursor, error) {
+	cursor := &CustomerListCursor{}
+
+	token, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err == nil {
+		err = jsoniter.ConfigFastest.Unmarshal(token, cursor)
+	}
+
+	if err != nil || cursor.CustomerID == "" {
+		err = errors.New("pageToken is invalid")
+		return nil, errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+
+	if cursor.OrderBy != orderBy {
+		err = errors.New("pageToken belongs to a list with another orderBy")
+		return nil, errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+
+	return cursor, nil
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
@@ -1,23 +1,47 @@
 package application
 
 import (
+	"context"
+	"time"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
 )
 
+const (
+	defaultCustomerHistoryPageSize = 50
+	maxCustomerHistoryPageSize     = 100
+)
+
 type CustomerQueryHandler struct {
-	retrieveCustomerEventStream ForRetrievingCustomerEventStreams
+	retrieveCustomerEventStream      ForRetrievingCustomerEventStreams
+	retrieveFullCustomerEventStream  ForRetrievingFullCustomerEventStreams
+	retrieveCustomerListViews        ForRetrievingCustomerListViews
+	retrieveCustomerIDByEmailAddress ForRetrievingCustomerIDsByEmailAddress
+	retrieveCustomerEventStreamRange ForRetrievingCustomerEventStreamRanges
 }
 
-func NewCustomerQueryHandler(retrieveCustomerEventStream ForRetrievingCustomerEventStreams) *CustomerQueryHandler {
+func NewCustomerQueryHandler(
+	retrieveCustomerEventStream ForRetrievingCustomerEventStreams,
+	retrieveFullCustomerEventStream ForRetrievingFullCustomerEventStreams,
+	retrieveCustomerListViews ForRetrievingCustomerListViews,
+	retrieveCustomerIDByEmailAddress ForRetrievingCustomerIDsByEmailAddress,
+	retrieveCustomerEventStreamRange ForRetrievingCustomerEventStreamRanges,
+) *CustomerQueryHandler {
+
 	return &CustomerQueryHandler{
-		retrieveCustomerEventStream: retrieveCustomerEventStream,
+		retrieveCustomerEventStream:      retrieveCustomerEventStream,
+		retrieveFullCustomerEventStream:  retrieveFullCustomerEventStream,
+		retrieveCustomerListViews:        retrieveCustomerListViews,
+		retrieveCustomerIDByEmailAddress: retrieveCustomerIDByEmailAddress,
+		retrieveCustomerEventStreamRange: retrieveCustomerEventStreamRange,
 	}
 }
 
-func (h *CustomerQueryHandler) CustomerViewByID(customerID string) (customer.View, error) {
+func (h *CustomerQueryHandler) CustomerViewByID(ctx context.Context, customerID string) (customer.View, error) {
 	var err error
 	var customerIDValue value.CustomerID
 	wrapWithMsg := "customerQueryHandler.CustomerViewByID"
@@ -26,7 +50,7 @@ func (h *CustomerQueryHandler) CustomerViewByID(customerID string) (customer.Vie
 		return customer.View{}, errors.Wrap(err, wrapWithMsg)
 	}
 
-	eventStream, err := h.retrieveCustomerEventStream(customerIDValue)
+	eventStream, err := h.retrieveCustomerEventStream(ctx, customerIDValue)
 	if err != nil {
 		return customer.View{}, errors.Wrap(err, wrapWithMsg)
 	}
@@ -41,3 +65,201 @@ func (h *CustomerQueryHandler) CustomerViewByID(customerID string) (customer.Vie
 
 	return customerView, nil
 }
+
+func (h *CustomerQueryHandler) CustomerViewByEmailAddress(
+	ctx context.Context,
+	emailAddress string,
+) (customer.View, error) {
+
+	var err error
+	var emailAddressValue value.EmailAddress
+	var customerIDValue value.CustomerID
+	wrapWithMsg := "customerQueryHandler.CustomerViewByEmailAddress"
+
+	if emailAddressValue, err = value.BuildEmailAddress(emailAddress); err != nil {
+		return customer.View{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if customerIDValue, err = h.retrieveCustomerIDByEmailAddress(ctx, emailAddressValue); err != nil {
+		return customer.View{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	customerView, err := h.CustomerViewByID(ctx, customerIDValue.String())
+	if err != nil {
+		return customer.View{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	// the Customer could have changed the email address after it was looked up
+	if value.RebuildEmailAddress(customerView.EmailAddress).Canonical() != emailAddressValue.Canonical() {
+		err := errors.New("customer not found")
+
+		return customer.View{}, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	return customerView, nil
+}
+
+func (h *CustomerQueryHandler) CustomerViewAt(
+	ctx context.Context,
+	customerID string,
+	asOfVersion uint,
+	asOfTime string,
+) (customer.View, error) {
+
+	var err error
+	var customerIDValue value.CustomerID
+	var asOf time.Time
+	wrapWithMsg := "customerQueryHandler.CustomerViewAt"
+
+	if customerIDValue, err = value.BuildCustomerID(customerID); err != nil {
+		return customer.View{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	switch {
+	case asOfVersion > 0 && asOfTime != "":
+		err := errors.New("either version or occurredAt must be supplied, not both")
+
+		return customer.View{}, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	case asOfVersion == 0 && asOfTime == "":
+		err := errors.New("either version or occurredAt must be supplied")
+
+		return customer.View{}, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	case asOfTime != "":
+		if asOf, err = time.Parse(time.RFC3339Nano, asOfTime); err != nil {
+			return customer.View{}, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+		}
+	}
+
+	eventStream, err := h.retrieveFullCustomerEventStream(ctx, customerIDValue)
+	if err != nil {
+		return customer.View{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	var eventStreamAt es.EventStream
+
+	for _, event := range eventStream {
+		if asOfVersion > 0 && event.Meta().StreamVersion() > asOfVersion {
+			break
+		}
+
+		if asOfTime != "" {
+			occurredAt, err := time.Parse(time.RFC3339Nano, event.Meta().OccurredAt())
+			if err != nil {
+				return customer.View{}, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+			}
+
+			if occurredAt.After(asOf) {
+				break
+			}
+		}
+
+		eventStreamAt = append(eventStreamAt, event)
+	}
+
+	if len(eventStreamAt) == 0 {
+		err := errors.New("customer not found at the requested version or time")
+
+		return customer.View{}, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	customerView := customer.BuildViewFrom(eventStreamAt)
+
+	if customerView.IsDeleted {
+		err := errors.New("customer not found")
+
+		return customer.View{}, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	return customerView, nil
+}
+
+// CustomerHistory also returns the events of deleted Customers, the personal data of forgotten ones is redacted.
+func (h *CustomerQueryHandler) CustomerHistory(
+	ctx context.Context,
+	customerID string,
+	fromVersion uint,
+	pageSize uint,
+) (customer.History, error) {
+
+	var err error
+	var customerIDValue value.CustomerID
+	wrapWithMsg := "customerQueryHandler.CustomerHistory"
+
+	if customerIDValue, err = value.BuildCustomerID(customerID); err != nil {
+		return customer.History{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if fromVersion == 0 {
+		fromVersion = 1
+	}
+
+	switch {
+	case pageSize == 0:
+		pageSize = defaultCustomerHistoryPageSize
+	case pageSize > maxCustomerHistoryPageSize:
+		pageSize = maxCustomerHistoryPageSize
+	}
+
+	// one more event than requested tells whether there is a next page
+	eventStream, err := h.retrieveCustomerEventStreamRange(ctx, customerIDValue, fromVersion, pageSize+1)
+	if err != nil {
+		return customer.History{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	history := customer.History{}
+
+	if uint(len(eventStream)) > pageSize {
+		eventStream = eventStream[:pageSize]
+		history.NextFromVersion = eventStream[pageSize-1].Meta().StreamVersion() + 1
+	}
+
+	history.Events = customer.BuildHistoryFrom(eventStream)
+
+	return history, nil
+}
+
+// ListCustomers reads from the Customer list projection, so it can lag a little behind the event streams.
+func (h *CustomerQueryHandler) ListCustomers(
+	ctx context.Context,
+	pageSize uint,
+	pageToken string,
+	orderBy string,
+	confirmationStatus string,
+	deletionStatus string,
+	registeredFrom string,
+	registeredTo string,
+) (customer.ListPage, error) {
+
+	wrapWithMsg := "customerQueryHandler.ListCustomers"
+
+	query, err := buildCustomerListQuery(
+		pageSize,
+		pageToken,
+		orderBy,
+		confirmationStatus,
+		deletionStatus,
+		registeredFrom,
+		registeredTo,
+	)
+
+	if err != nil {
+		return customer.ListPage{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	pageSize = query.Limit
+	query.Limit++ // one more to know if there is a next page
+
+	customerViews, err := h.retrieveCustomerListViews(ctx, query)
+	if err != nil {
+		return customer.ListPage{}, errors.Wrap(err, wrapWithMsg)
+	}
+
+	page := customer.ListPage{Customers: customerViews}
+
+	if uint(len(customerViews)) > pageSize {
+		page.Customers = customerViews[:pageSize]
+		page.NextPageToken = buildCustomerListPageToken(page.Customers[pageSize-1], orderBy, query)
+	}
+
+	return page, nil
+}
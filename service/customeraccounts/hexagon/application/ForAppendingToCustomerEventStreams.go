@@ -1,8 +1,14 @@
 package application
 
 import (
+	"context"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
-type ForAppendingToCustomerEventStreams func(recordedEvents es.RecordedEvents, id value.CustomerID) error
+type ForAppendingToCustomerEventStreams func(
+	ctx context.Context,
+	recordedEvents es.RecordedEvents,
+	id value.CustomerID,
+) error
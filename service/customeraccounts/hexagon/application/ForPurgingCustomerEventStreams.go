@@ -1,5 +1,11 @@
 package application
 
-import "github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+import (
+	"context"
 
-type ForPurgingCustomerEventStreams func(id value.CustomerID) error
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+)
+
+// ForPurgingCustomerEventStreams physically deletes a stream, it is only meant for cleaning up after tests.
+// To erase a Customer's personal data, use CustomerCommandHandler.ForgetCustomer instead.
+type ForPurgingCustomerEventStreams func(ctx context.Context, id value.CustomerID) error
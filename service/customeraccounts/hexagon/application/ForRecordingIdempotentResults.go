@@ -0,0 +1,5 @@
+package application
+
+import "context"
+
+type ForRecordingIdempotentResults func(ctx context.Context, idempotencyKey string, result string) error
@@ -0,0 +1,5 @@
+package application
+
+import "context"
+
+type ForReleasingIdempotencyKeys func(ctx context.Context, idempotencyKey string) error
@@ -0,0 +1,10 @@
+package application
+
+import "context"
+
+// ForReservingIdempotencyKeys returns the recorded result if the key was already used for a command with the same
+// fingerprint (isReplay). Otherwise it reserves the key, which then must be recorded or released.
+type ForReservingIdempotencyKeys func(
+	ctx context.Context,
+	idempotencyKey, fingerprint string,
+) (result string, isReplay bool, err error)
@@ -0,0 +1,16 @@
+package application
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// ForRetrievingCustomerEventStreamRanges must return at most maxEvents events starting at fromVersion (no snapshots).
+type ForRetrievingCustomerEventStreamRanges func(
+	ctx context.Context,
+	id value.CustomerID,
+	fromVersion uint,
+	maxEvents uint,
+) (es.EventStream, error)
@@ -1,8 +1,10 @@
 package application
 
 import (
+	"context"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
-type ForRetrievingCustomerEventStreams func(id value.CustomerID) (es.EventStream, error)
+type ForRetrievingCustomerEventStreams func(ctx context.Context, id value.CustomerID) (es.EventStream, error)
@@ -0,0 +1,14 @@
+package application
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+)
+
+// ForRetrievingCustomerIDsByEmailAddress looks up the unique email addresses,
+// which only contain the current email addresses of Customers that are not deleted.
+type ForRetrievingCustomerIDsByEmailAddress func(
+	ctx context.Context,
+	emailAddress value.EmailAddress,
+) (value.CustomerID, error)
@@ -0,0 +1,9 @@
+package application
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+)
+
+type ForRetrievingCustomerListViews func(ctx context.Context, query CustomerListQuery) ([]customer.ListView, error)
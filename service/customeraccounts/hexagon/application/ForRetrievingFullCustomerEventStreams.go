@@ -0,0 +1,12 @@
+package application
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// ForRetrievingFullCustomerEventStreams must return all events of a stream, starting at version 1 (no snapshots),
+// so that past states of the stream can be rebuilt.
+type ForRetrievingFullCustomerEventStreams func(ctx context.Context, id value.CustomerID) (es.EventStream, error)
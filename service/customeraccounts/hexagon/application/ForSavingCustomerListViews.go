@@ -0,0 +1,12 @@
+package application
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+)
+
+// ForSavingCustomerListViews must not replace a view with an older version, and it stores the globalPosition
+// of the projected event as checkpoint, so that the projection can continue there after a restart.
+// RegisteredAt is only stored when the view is inserted.
+type ForSavingCustomerListViews func(ctx context.Context, view customer.ListView, globalPosition uint64) error
@@ -0,0 +1,14 @@
+package application
+
+import (
+	"context"
+)
+
+// Email is a plain text email, the adapters take care of the sender address and the encoding.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type ForSendingEmails func(ctx context.Context, email Email) error
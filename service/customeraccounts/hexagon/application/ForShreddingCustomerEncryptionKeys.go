@@ -0,0 +1,9 @@
+package application
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+)
+
+type ForShreddingCustomerEncryptionKeys func(ctx context.Context, id value.CustomerID) error
@@ -0,0 +1,41 @@
+package application
+
+// Customers have no preferred language yet, so all confirmation emails are rendered in the configured locale.
+var confirmationEmailTemplates = map[string]struct{ subject, body string }{
+	"en": {
+		subject: `Please confirm your email address`,
+		body: `Hello {{.GivenName}} {{.FamilyName}},
+
+{{if .EmailAddressWasChanged -}}
+you have changed your email address to {{.EmailAddress}}.
+{{- else if .WasRegenerated -}}
+you have requested a new confirmation for {{.EmailAddress}}, the previous one is not valid anymore.
+{{- else -}}
+thank you for your registration with {{.EmailAddress}}.
+{{- end}}
+
+Please confirm your email address with the following data:
+
+Customer ID:       {{.CustomerID}}
+Confirmation hash: {{.ConfirmationHash}}
+`,
+	},
+	"de": {
+		subject: `Bitte bestätige deine E-Mail-Adresse`,
+		body: `Hallo {{.GivenName}} {{.FamilyName}},
+
+{{if .EmailAddressWasChanged -}}
+du hast deine E-Mail-Adresse zu {{.EmailAddress}} geändert.
+{{- else if .WasRegenerated -}}
+du hast eine neue Bestätigung für {{.EmailAddress}} angefordert, die vorherige ist nicht mehr gültig.
+{{- else -}}
+vielen Dank für deine Registrierung mit {{.EmailAddress}}.
+{{- end}}
+
+Bitte bestätige deine E-Mail-Adresse mit den folgenden Daten:
+
+Kundennummer:       {{.CustomerID}}
+Bestätigungs-Hash:  {{.ConfirmationHash}}
+`,
+	},
+}
@@ -2,23 +2,27 @@ package domain
 
 import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
 type ChangeCustomerEmailAddress struct {
 	customerID       value.CustomerID
 	emailAddress     value.EmailAddress
 	confirmationHash value.ConfirmationHash
+	messageMeta      es.MessageMeta
 }
 
 func BuildChangeCustomerEmailAddress(
 	customerID value.CustomerID,
 	emailAddress value.EmailAddress,
+	messageMeta es.MessageMeta,
 ) ChangeCustomerEmailAddress {
 
 	changeEmailAddress := ChangeCustomerEmailAddress{
 		customerID:       customerID,
 		emailAddress:     emailAddress,
-		confirmationHash: value.GenerateConfirmationHash(emailAddress.String()),
+		confirmationHash: value.GenerateConfirmationHash(),
+		messageMeta:      messageMeta,
 	}
 
 	return changeEmailAddress
@@ -35,3 +39,7 @@ func (command ChangeCustomerEmailAddress) EmailAddress() value.EmailAddress {
 func (command ChangeCustomerEmailAddress) ConfirmationHash() value.ConfirmationHash {
 	return command.confirmationHash
 }
+
+func (command ChangeCustomerEmailAddress) MessageMeta() es.MessageMeta {
+	return command.messageMeta
+}
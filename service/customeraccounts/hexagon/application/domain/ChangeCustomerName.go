@@ -2,21 +2,25 @@ package domain
 
 import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
 type ChangeCustomerName struct {
-	customerID value.CustomerID
-	personName value.PersonName
+	customerID  value.CustomerID
+	personName  value.PersonName
+	messageMeta es.MessageMeta
 }
 
 func BuildChangeCustomerName(
 	customerID value.CustomerID,
 	personName value.PersonName,
+	messageMeta es.MessageMeta,
 ) ChangeCustomerName {
 
 	changeEmailAddress := ChangeCustomerName{
-		customerID: customerID,
-		personName: personName,
+		customerID:  customerID,
+		personName:  personName,
+		messageMeta: messageMeta,
 	}
 
 	return changeEmailAddress
@@ -29,3 +33,7 @@ func (command ChangeCustomerName) CustomerID() value.CustomerID {
 func (command ChangeCustomerName) PersonName() value.PersonName {
 	return command.personName
 }
+
+func (command ChangeCustomerName) MessageMeta() es.MessageMeta {
+	return command.messageMeta
+}
@@ -2,21 +2,25 @@ package domain
 
 import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
 type ConfirmCustomerEmailAddress struct {
 	customerID       value.CustomerID
 	confirmationHash value.ConfirmationHash
+	messageMeta      es.MessageMeta
 }
 
 func BuildConfirmCustomerEmailAddress(
 	customerID value.CustomerID,
 	confirmationHash value.ConfirmationHash,
+	messageMeta es.MessageMeta,
 ) ConfirmCustomerEmailAddress {
 
 	confirmEmailAddress := ConfirmCustomerEmailAddress{
 		customerID:       customerID,
 		confirmationHash: confirmationHash,
+		messageMeta:      messageMeta,
 	}
 
 	return confirmEmailAddress
@@ -29,3 +33,7 @@ func (command ConfirmCustomerEmailAddress) CustomerID() value.CustomerID {
 func (command ConfirmCustomerEmailAddress) ConfirmationHash() value.ConfirmationHash {
 	return command.confirmationHash
 }
+
+func (command ConfirmCustomerEmailAddress) MessageMeta() es.MessageMeta {
+	return command.messageMeta
+}
@@ -18,6 +18,7 @@ func BuildCustomerEmailAddressChanged(
 	emailAddress value.EmailAddress,
 	confirmationHash value.ConfirmationHash,
 	previousEmailAddress value.EmailAddress,
+	messageMeta es.MessageMeta,
 	streamVersion uint,
 ) CustomerEmailAddressChanged {
 
@@ -28,7 +29,8 @@ func BuildCustomerEmailAddressChanged(
 		previousEmailAddress: previousEmailAddress,
 	}
 
-	event.meta = es.BuildEventMeta(event, streamVersion)
+	event.meta = es.BuildEventMeta(event, messageMeta, streamVersion)
+	event.confirmationHash = confirmationHash.WithIssuedAt(event.meta.OccurredAt())
 
 	return event
 }
@@ -44,7 +46,7 @@ func RebuildCustomerEmailAddressChanged(
 	event := CustomerEmailAddressChanged{
 		customerID:           value.RebuildCustomerID(customerID),
 		emailAddress:         value.RebuildEmailAddress(emailAddress),
-		confirmationHash:     value.RebuildConfirmationHash(confirmationHash),
+		confirmationHash:     value.RebuildConfirmationHash(confirmationHash).WithIssuedAt(meta.OccurredAt()),
 		previousEmailAddress: value.RebuildEmailAddress(previousEmailAddress),
 		meta:                 meta,
 	}
@@ -7,11 +7,24 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// The reason codes of CustomerEmailAddressConfirmationFailed are stored with the event,
+// so that its FailureReason can be rebuilt as the same sentinel error.
+const (
+	ConfirmationFailedWithWrongHash   = "wrong_hash"
+	ConfirmationFailedWithExpiredHash = "expired_hash"
+)
+
+var (
+	ErrWrongConfirmationHash   = errors.New("wrong confirmation hash supplied")
+	ErrConfirmationHashExpired = errors.New("confirmation hash has expired")
+)
+
 type CustomerEmailAddressConfirmationFailed struct {
 	customerID       value.CustomerID
 	emailAddress     value.EmailAddress
 	confirmationHash value.ConfirmationHash
 	reason           error
+	reasonCode       string
 	meta             es.EventMeta
 }
 
@@ -20,6 +33,7 @@ func BuildCustomerEmailAddressConfirmationFailed(
 	emailAddress value.EmailAddress,
 	confirmationHash value.ConfirmationHash,
 	reason error,
+	messageMeta es.MessageMeta,
 	streamVersion uint,
 ) CustomerEmailAddressConfirmationFailed {
 
@@ -28,9 +42,14 @@ func BuildCustomerEmailAddressConfirmationFailed(
 		emailAddress:     emailAddress,
 		confirmationHash: confirmationHash,
 		reason:           reason,
+		reasonCode:       ConfirmationFailedWithWrongHash,
+	}
+
+	if errors.Is(reason, ErrConfirmationHashExpired) {
+		event.reasonCode = ConfirmationFailedWithExpiredHash
 	}
 
-	event.meta = es.BuildEventMeta(event, streamVersion)
+	event.meta = es.BuildEventMeta(event, messageMeta, streamVersion)
 
 	return event
 }
@@ -39,15 +58,21 @@ func RebuildCustomerEmailAddressConfirmationFailed(
 	customerID string,
 	emailAddress string,
 	confirmationHash string,
-	reason string,
+	reasonCode string,
 	meta es.EventMeta,
 ) CustomerEmailAddressConfirmationFailed {
 
+	reason := ErrWrongConfirmationHash
+	if reasonCode == ConfirmationFailedWithExpiredHash {
+		reason = ErrConfirmationHashExpired
+	}
+
 	event := CustomerEmailAddressConfirmationFailed{
 		customerID:       value.RebuildCustomerID(customerID),
 		emailAddress:     value.RebuildEmailAddress(emailAddress),
 		confirmationHash: value.RebuildConfirmationHash(confirmationHash),
-		reason:           errors.Mark(errors.New(reason), shared.ErrDomainConstraintsViolation),
+		reason:           errors.Mark(reason, shared.ErrDomainConstraintsViolation),
+		reasonCode:       reasonCode,
 		meta:             meta,
 	}
 
@@ -66,6 +91,10 @@ func (event CustomerEmailAddressConfirmationFailed) ConfirmationHash() value.Con
 	return event.confirmationHash
 }
 
+func (event CustomerEmailAddressConfirmationFailed) ReasonCode() string {
+	return event.reasonCode
+}
+
 func (event CustomerEmailAddressConfirmationFailed) Meta() es.EventMeta {
 	return event.meta
 }
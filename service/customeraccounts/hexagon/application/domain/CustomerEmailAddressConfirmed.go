@@ -14,6 +14,7 @@ type CustomerEmailAddressConfirmed struct {
 func BuildCustomerEmailAddressConfirmed(
 	customerID value.CustomerID,
 	emailAddress value.EmailAddress,
+	messageMeta es.MessageMeta,
 	streamVersion uint,
 ) CustomerEmailAddressConfirmed {
 
@@ -22,7 +23,7 @@ func BuildCustomerEmailAddressConfirmed(
 		emailAddress: emailAddress,
 	}
 
-	event.meta = es.BuildEventMeta(event, streamVersion)
+	event.meta = es.BuildEventMeta(event, messageMeta, streamVersion)
 
 	return event
 }
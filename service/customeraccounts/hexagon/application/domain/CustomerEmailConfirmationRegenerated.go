@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// CustomerEmailConfirmationRegenerated replaces the confirmation hash, so the previous one can't be used anymore.
+type CustomerEmailConfirmationRegenerated struct {
+	customerID       value.CustomerID
+	emailAddress     value.EmailAddress
+	confirmationHash value.ConfirmationHash
+	meta             es.EventMeta
+}
+
+func BuildCustomerEmailConfirmationRegenerated(
+	customerID value.CustomerID,
+	emailAddress value.EmailAddress,
+	confirmationHash value.ConfirmationHash,
+	messageMeta es.MessageMeta,
+	streamVersion uint,
+) CustomerEmailConfirmationRegenerated {
+
+	event := CustomerEmailConfirmationRegenerated{
+		customerID:       customerID,
+		emailAddress:     emailAddress,
+		confirmationHash: confirmationHash,
+	}
+
+	event.meta = es.BuildEventMeta(event, messageMeta, streamVersion)
+	event.confirmationHash = confirmationHash.WithIssuedAt(event.meta.OccurredAt())
+
+	return event
+}
+
+func RebuildCustomerEmailConfirmationRegenerated(
+	customerID string,
+	emailAddress string,
+	confirmationHash string,
+	meta es.EventMeta,
+) CustomerEmailConfirmationRegenerated {
+
+	event := CustomerEmailConfirmationRegenerated{
+		customerID:       value.RebuildCustomerID(customerID),
+		emailAddress:     value.RebuildEmailAddress(emailAddress),
+		confirmationHash: value.RebuildConfirmationHash(confirmationHash).WithIssuedAt(meta.OccurredAt()),
+		meta:             meta,
+	}
+
+	return event
+}
+
+func (event CustomerEmailConfirmationRegenerated) CustomerID() value.CustomerID {
+	return event.customerID
+}
+
+func (event CustomerEmailConfirmationRegenerated) EmailAddress() value.EmailAddress {
+	return event.emailAddress
+}
+
+func (event CustomerEmailConfirmationRegenerated) ConfirmationHash() value.ConfirmationHash {
+	return event.confirmationHash
+}
+
+func (event CustomerEmailConfirmationRegenerated) Meta() es.EventMeta {
+	return event.meta
+}
+
+func (event CustomerEmailConfirmationRegenerated) IsFailureEvent() bool {
+	return false
+}
+
+func (event CustomerEmailConfirmationRegenerated) FailureReason() error {
+	return nil
+}
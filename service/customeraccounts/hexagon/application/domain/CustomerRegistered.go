@@ -18,6 +18,7 @@ func BuildCustomerRegistered(
 	emailAddress value.EmailAddress,
 	confirmationHash value.ConfirmationHash,
 	personName value.PersonName,
+	messageMeta es.MessageMeta,
 	streamVersion uint,
 ) CustomerRegistered {
 
@@ -28,7 +29,8 @@ func BuildCustomerRegistered(
 		personName:       personName,
 	}
 
-	event.meta = es.BuildEventMeta(event, streamVersion)
+	event.meta = es.BuildEventMeta(event, messageMeta, streamVersion)
+	event.confirmationHash = confirmationHash.WithIssuedAt(event.meta.OccurredAt())
 
 	return event
 }
@@ -45,7 +47,7 @@ func RebuildCustomerRegistered(
 	event := CustomerRegistered{
 		customerID:       value.RebuildCustomerID(customerID),
 		emailAddress:     value.RebuildEmailAddress(emailAddress),
-		confirmationHash: value.RebuildConfirmationHash(confirmationHash),
+		confirmationHash: value.RebuildConfirmationHash(confirmationHash).WithIssuedAt(meta.OccurredAt()),
 		personName:       value.RebuildPersonName(givenName, familyName),
 		meta:             meta,
 	}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// CustomerSnapshot is not a real domain event, it captures the state of a Customer at a stream version.
+// It implements es.DomainEvent, so it can be the first element of an es.EventStream,
+// which saves replaying all the events up to that stream version.
+type CustomerSnapshot struct {
+	customerID                   value.CustomerID
+	personName                   value.PersonName
+	emailAddress                 value.EmailAddress
+	emailAddressConfirmationHash value.ConfirmationHash
+	failedConfirmations          []time.Time
+	isEmailAddressConfirmed      bool
+	isDeleted                    bool
+	meta                         es.EventMeta
+}
+
+func BuildCustomerSnapshot(
+	customerID value.CustomerID,
+	personName value.PersonName,
+	emailAddress value.EmailAddress,
+	emailAddressConfirmationHash value.ConfirmationHash,
+	failedConfirmations []time.Time,
+	isEmailAddressConfirmed bool,
+	isDeleted bool,
+	streamVersion uint,
+) CustomerSnapshot {
+
+	snapshot := CustomerSnapshot{
+		customerID:                   customerID,
+		personName:                   personName,
+		emailAddress:                 emailAddress,
+		emailAddressConfirmationHash: emailAddressConfirmationHash,
+		failedConfirmations:          failedConfirmations,
+		isEmailAddressConfirmed:      isEmailAddressConfirmed,
+		isDeleted:                    isDeleted,
+	}
+
+	snapshot.meta = es.BuildEventMeta(snapshot, es.MessageMeta{}, streamVersion)
+
+	return snapshot
+}
+
+func RebuildCustomerSnapshot(
+	customerID string,
+	givenName string,
+	familyName string,
+	emailAddress string,
+	emailAddressConfirmationHash string,
+	emailAddressConfirmationHashIssuedAt string,
+	failedConfirmationsAt []string,
+	isEmailAddressConfirmed bool,
+	isDeleted bool,
+	meta es.EventMeta,
+) CustomerSnapshot {
+
+	confirmationHash := value.RebuildConfirmationHash(emailAddressConfirmationHash).WithIssuedAt(
+		emailAddressConfirmationHashIssuedAt,
+	)
+
+	var failedConfirmations []time.Time
+
+	for _, failedAt := range failedConfirmationsAt {
+		if occurredAt, err := time.Parse(time.RFC3339Nano, failedAt); err == nil {
+			failedConfirmations = append(failedConfirmations, occurredAt)
+		}
+	}
+
+	snapshot := CustomerSnapshot{
+		customerID:                   value.RebuildCustomerID(customerID),
+		personName:                   value.RebuildPersonName(givenName, familyName),
+		emailAddress:                 value.RebuildEmailAddress(emailAddress),
+		emailAddressConfirmationHash: confirmationHash,
+		failedConfirmations:          failedConfirmations,
+		isEmailAddressConfirmed:      isEmailAddressConfirmed,
+		isDeleted:                    isDeleted,
+		meta:                         meta,
+	}
+
+	return snapshot
+}
+
+func (snapshot CustomerSnapshot) CustomerID() value.CustomerID {
+	return snapshot.customerID
+}
+
+func (snapshot CustomerSnapshot) PersonName() value.PersonName {
+	return snapshot.personName
+}
+
+func (snapshot CustomerSnapshot) EmailAddress() value.EmailAddress {
+	return snapshot.emailAddress
+}
+
+func (snapshot CustomerSnapshot) EmailAddressConfirmationHash() value.ConfirmationHash {
+	return snapshot.emailAddressConfirmationHash
+}
+
+// FailedConfirmations are the times of the failed confirmations since the confirmation hash was issued.
+func (snapshot CustomerSnapshot) FailedConfirmations() []time.Time {
+	return snapshot.failedConfirmations
+}
+
+func (snapshot CustomerSnapshot) IsEmailAddressConfirmed() bool {
+	return snapshot.isEmailAddressConfirmed
+}
+
+func (snapshot CustomerSnapshot) IsDeleted() bool {
+	return snapshot.isDeleted
+}
+
+func (snapshot CustomerSnapshot) Meta() es.EventMeta {
+	return snapshot.meta
+}
+
+func (snapshot CustomerSnapshot) IsFailureEvent() bool {
+	return false
+}
+
+func (snapshot CustomerSnapshot) FailureReason() error {
+	return nil
+}
@@ -2,18 +2,22 @@ package domain
 
 import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
 type DeleteCustomer struct {
-	customerID value.CustomerID
+	customerID  value.CustomerID
+	messageMeta es.MessageMeta
 }
 
 func BuildDeleteCustomer(
 	customerID value.CustomerID,
+	messageMeta es.MessageMeta,
 ) DeleteCustomer {
 
 	deleteCustomer := DeleteCustomer{
-		customerID: customerID,
+		customerID:  customerID,
+		messageMeta: messageMeta,
 	}
 
 	return deleteCustomer
@@ -22,3 +26,7 @@ func BuildDeleteCustomer(
 func (command DeleteCustomer) CustomerID() value.CustomerID {
 	return command.customerID
 }
+
+func (command DeleteCustomer) MessageMeta() es.MessageMeta {
+	return command.messageMeta
+}
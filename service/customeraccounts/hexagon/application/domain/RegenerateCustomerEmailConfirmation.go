@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+type RegenerateCustomerEmailConfirmation struct {
+	customerID       value.CustomerID
+	confirmationHash value.ConfirmationHash
+	messageMeta      es.MessageMeta
+}
+
+func BuildRegenerateCustomerEmailConfirmation(
+	customerID value.CustomerID,
+	messageMeta es.MessageMeta,
+) RegenerateCustomerEmailConfirmation {
+
+	regenerateEmailConfirmation := RegenerateCustomerEmailConfirmation{
+		customerID:       customerID,
+		confirmationHash: value.GenerateConfirmationHash(),
+		messageMeta:      messageMeta,
+	}
+
+	return regenerateEmailConfirmation
+}
+
+func (command RegenerateCustomerEmailConfirmation) CustomerID() value.CustomerID {
+	return command.customerID
+}
+
+func (command RegenerateCustomerEmailConfirmation) ConfirmationHash() value.ConfirmationHash {
+	return command.confirmationHash
+}
+
+func (command RegenerateCustomerEmailConfirmation) MessageMeta() es.MessageMeta {
+	return command.messageMeta
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 )
 
 type RegisterCustomer struct {
@@ -9,6 +10,7 @@ type RegisterCustomer struct {
 	emailAddress     value.EmailAddress
 	confirmationHash value.ConfirmationHash
 	personName       value.PersonName
+	messageMeta      es.MessageMeta
 }
 
 func BuildRegisterCustomer(
@@ -16,6 +18,7 @@ func BuildRegisterCustomer(
 	emailAddress value.EmailAddress,
 	confirmationHash value.ConfirmationHash,
 	personName value.PersonName,
+	messageMeta es.MessageMeta,
 ) RegisterCustomer {
 
 	register := RegisterCustomer{
@@ -23,6 +26,7 @@ func BuildRegisterCustomer(
 		emailAddress:     emailAddress,
 		confirmationHash: confirmationHash,
 		personName:       personName,
+		messageMeta:      messageMeta,
 	}
 
 	return register
@@ -43,3 +47,7 @@ func (command RegisterCustomer) ConfirmationHash() value.ConfirmationHash {
 func (command RegisterCustomer) PersonName() value.PersonName {
 	return command.personName
 }
+
+func (command RegisterCustomer) MessageMeta() es.MessageMeta {
+	return command.messageMeta
+}
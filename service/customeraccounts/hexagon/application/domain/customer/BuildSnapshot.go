@@ -0,0 +1,23 @@
+package customer
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+func BuildSnapshot(eventStream es.EventStream) es.DomainEvent {
+	customer := buildCurrentStateFrom(eventStream)
+
+	snapshot := domain.BuildCustomerSnapshot(
+		customer.id,
+		customer.personName,
+		customer.emailAddress,
+		customer.emailAddressConfirmationHash,
+		customer.failedConfirmations,
+		customer.isEmailAddressConfirmed,
+		customer.isDeleted,
+		customer.currentStreamVersion,
+	)
+
+	return snapshot
+}
@@ -0,0 +1,68 @@
+package customer_test
+
+import (
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildSnapshot(t *testing.T) {
+	Convey("Prepare test artifacts", t, func() {
+		customerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
+		confirmationHash := value.GenerateConfirmationHash()
+		personName := value.RebuildPersonName("Kevin", "Ball")
+		changedPersonName := value.RebuildPersonName("Latoya", "Ball")
+
+		customerWasRegistered := domain.BuildCustomerRegistered(
+			customerID,
+			emailAddress,
+			confirmationHash,
+			personName,
+			es.MessageMeta{},
+			1,
+		)
+
+		customerEmailAddressWasConfirmed := domain.BuildCustomerEmailAddressConfirmed(
+			customerID,
+			emailAddress,
+			es.MessageMeta{},
+			2,
+		)
+
+		customerNameWasChanged := domain.BuildCustomerNameChanged(
+			customerID,
+			changedPersonName,
+			es.MessageMeta{},
+			3,
+		)
+
+		Convey("Given CustomerRegistered, CustomerEmailAddressConfirmed and CustomerNameChanged", func() {
+			eventStream := es.EventStream{customerWasRegistered, customerEmailAddressWasConfirmed, customerNameWasChanged}
+
+			Convey("When a snapshot is built", func() {
+				snapshot := customer.BuildSnapshot(eventStream)
+
+				Convey("Then it should capture the current state", func() {
+					customerSnapshot, ok := snapshot.(domain.CustomerSnapshot)
+					So(ok, ShouldBeTrue)
+					So(customerSnapshot.CustomerID().Equals(customerID), ShouldBeTrue)
+					So(customerSnapshot.PersonName().Equals(changedPersonName), ShouldBeTrue)
+					So(customerSnapshot.EmailAddress().Equals(emailAddress), ShouldBeTrue)
+					So(customerSnapshot.EmailAddressConfirmationHash().Equals(confirmationHash), ShouldBeTrue)
+					So(customerSnapshot.IsEmailAddressConfirmed(), ShouldBeTrue)
+					So(customerSnapshot.IsDeleted(), ShouldBeFalse)
+					So(customerSnapshot.Meta().StreamVersion(), ShouldEqual, 3)
+
+					Convey("And the View built from the snapshot should equal the View built from all events", func() {
+						So(customer.BuildViewFrom(es.EventStream{snapshot}), ShouldResemble, customer.BuildViewFrom(eventStream))
+					})
+				})
+			})
+		})
+	})
+}
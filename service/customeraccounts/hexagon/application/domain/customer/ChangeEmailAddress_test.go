@@ -17,9 +17,11 @@ func TestChangeEmailAddress(t *testing.T) {
 		var err error
 		var recordedEvents es.RecordedEvents
 
+		messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
+
 		customerID := value.GenerateCustomerID()
 		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
-		confirmationHash := value.GenerateConfirmationHash(emailAddress.String())
+		confirmationHash := value.GenerateConfirmationHash()
 		personName := value.RebuildPersonName("Kevin", "Ball")
 		changedEmailAddress := value.RebuildEmailAddress("latoya@ball.net")
 
@@ -28,18 +30,21 @@ func TestChangeEmailAddress(t *testing.T) {
 			emailAddress,
 			confirmationHash,
 			personName,
+			es.MessageMeta{},
 			1,
 		)
 
 		customerEmailAddressWasConfirmed := domain.BuildCustomerEmailAddressConfirmed(
 			customerID,
 			emailAddress,
+			es.MessageMeta{},
 			2,
 		)
 
 		changeEmailAddress := domain.BuildChangeCustomerEmailAddress(
 			customerID,
 			changedEmailAddress,
+			messageMeta,
 		)
 
 		changedConfirmationHash := changeEmailAddress.ConfirmationHash()
@@ -47,6 +52,7 @@ func TestChangeEmailAddress(t *testing.T) {
 		confirmEmailAddress := domain.BuildConfirmCustomerEmailAddress(
 			customerID,
 			changedConfirmationHash,
+			messageMeta,
 		)
 
 		Convey("\nSCENARIO 1: Change a Customer's emailAddress", func() {
@@ -69,6 +75,7 @@ func TestChangeEmailAddress(t *testing.T) {
 						So(emailAddressChanged.IsFailureEvent(), ShouldBeFalse)
 						So(emailAddressChanged.FailureReason(), ShouldBeNil)
 						So(emailAddressChanged.Meta().StreamVersion(), ShouldEqual, 2)
+						So(emailAddressChanged.Meta().MessageMeta(), ShouldResemble, messageMeta)
 					})
 				})
 			})
@@ -82,6 +89,7 @@ func TestChangeEmailAddress(t *testing.T) {
 					changeEmailAddress = domain.BuildChangeCustomerEmailAddress(
 						customerID,
 						emailAddress,
+						messageMeta,
 					)
 
 					recordedEvents, err = customer.ChangeEmailAddress(eventStream, changeEmailAddress)
@@ -104,6 +112,7 @@ func TestChangeEmailAddress(t *testing.T) {
 						changedEmailAddress,
 						changedConfirmationHash,
 						emailAddress,
+						es.MessageMeta{},
 						2,
 					)
 
@@ -134,13 +143,14 @@ func TestChangeEmailAddress(t *testing.T) {
 							changedEmailAddress,
 							changedConfirmationHash,
 							emailAddress,
+							es.MessageMeta{},
 							3,
 						)
 
 						eventStream = append(eventStream, emailAddressChanged)
 
 						Convey("When ConfirmCustomerEmailAddress", func() {
-							recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress)
+							recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, customer.EmailConfirmationPolicy{})
 							So(err, ShouldBeNil)
 
 							Convey("Then CustomerEmailAddressConfirmed", func() {
@@ -152,6 +162,7 @@ func TestChangeEmailAddress(t *testing.T) {
 								So(emailAddressConfirmed.IsFailureEvent(), ShouldBeFalse)
 								So(emailAddressConfirmed.FailureReason(), ShouldBeNil)
 								So(emailAddressConfirmed.Meta().StreamVersion(), ShouldEqual, 4)
+								So(emailAddressConfirmed.Meta().MessageMeta(), ShouldResemble, messageMeta)
 							})
 						})
 					})
@@ -166,7 +177,7 @@ func TestChangeEmailAddress(t *testing.T) {
 				Convey("Given CustomerDeleted", func() {
 					eventStream = append(
 						eventStream,
-						domain.BuildCustomerDeleted(customerID, emailAddress, 2),
+						domain.BuildCustomerDeleted(customerID, emailAddress, es.MessageMeta{}, 2),
 					)
 
 					Convey("When ChangeCustomerEmailAddress", func() {
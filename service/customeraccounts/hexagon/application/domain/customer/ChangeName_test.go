@@ -17,9 +17,11 @@ func TestChangeName(t *testing.T) {
 		var err error
 		var recordedEvents es.RecordedEvents
 
+		messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
+
 		customerID := value.GenerateCustomerID()
 		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
-		confirmationHash := value.GenerateConfirmationHash(emailAddress.String())
+		confirmationHash := value.GenerateConfirmationHash()
 		personName := value.RebuildPersonName("Kevin", "Ball")
 		changedPersonName := value.RebuildPersonName("Latoya", "Ball")
 
@@ -28,12 +30,14 @@ func TestChangeName(t *testing.T) {
 			emailAddress,
 			confirmationHash,
 			personName,
+			es.MessageMeta{},
 			1,
 		)
 
 		changeName := domain.BuildChangeCustomerName(
 			customerID,
 			changedPersonName,
+			messageMeta,
 		)
 
 		Convey("\nSCENARIO 1: Change a Customer's name", func() {
@@ -54,6 +58,7 @@ func TestChangeName(t *testing.T) {
 						So(nameChanged.IsFailureEvent(), ShouldBeFalse)
 						So(nameChanged.FailureReason(), ShouldBeNil)
 						So(nameChanged.Meta().StreamVersion(), ShouldEqual, 2)
+						So(nameChanged.Meta().MessageMeta(), ShouldResemble, messageMeta)
 					})
 				})
 			})
@@ -67,6 +72,7 @@ func TestChangeName(t *testing.T) {
 					changeName = domain.BuildChangeCustomerName(
 						customerID,
 						personName,
+						messageMeta,
 					)
 
 					recordedEvents, err = customer.ChangeName(eventStream, changeName)
@@ -87,6 +93,7 @@ func TestChangeName(t *testing.T) {
 					nameChanged := domain.BuildCustomerNameChanged(
 						customerID,
 						changedPersonName,
+						es.MessageMeta{},
 						2,
 					)
 
@@ -111,7 +118,7 @@ func TestChangeName(t *testing.T) {
 				Convey("Given CustomerDeleted", func() {
 					eventStream = append(
 						eventStream,
-						domain.BuildCustomerDeleted(customerID, emailAddress, 2),
+						domain.BuildCustomerDeleted(customerID, emailAddress, es.MessageMeta{}, 2),
 					)
 
 					Convey("When ChangeCustomerName", func() {
@@ -6,19 +6,29 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-func ConfirmEmailAddress(eventStream es.EventStream, command domain.ConfirmCustomerEmailAddress) (es.RecordedEvents, error) {
+func ConfirmEmailAddress(
+	eventStream es.EventStream,
+	command domain.ConfirmCustomerEmailAddress,
+	policy EmailConfirmationPolicy,
+) (es.RecordedEvents, error) {
+
 	customer := buildCurrentStateFrom(eventStream)
 
 	if err := assertNotDeleted(customer); err != nil {
 		return nil, errors.Wrap(err, "confirmEmailAddress")
 	}
 
+	if err := assertConfirmationNotLockedOut(customer.failedConfirmations, policy); err != nil {
+		return nil, errors.Wrap(err, "confirmEmailAddress")
+	}
+
 	if err := assertMatchingConfirmationHash(customer.emailAddressConfirmationHash, command.ConfirmationHash()); err != nil {
 		event := domain.BuildCustomerEmailAddressConfirmationFailed(
 			customer.id,
 			customer.emailAddress,
 			command.ConfirmationHash(),
 			err,
+			command.MessageMeta(),
 			customer.currentStreamVersion+1,
 		)
 
@@ -29,9 +39,23 @@ func ConfirmEmailAddress(eventStream es.EventStream, command domain.ConfirmCusto
 		return nil, nil
 	}
 
+	if err := assertConfirmationHashNotExpired(customer.emailAddressConfirmationHash, policy); err != nil {
+		event := domain.BuildCustomerEmailAddressConfirmationFailed(
+			customer.id,
+			customer.emailAddress,
+			command.ConfirmationHash(),
+			err,
+			command.MessageMeta(),
+			customer.currentStreamVersion+1,
+		)
+
+		return es.RecordedEvents{event}, nil
+	}
+
 	event := domain.BuildCustomerEmailAddressConfirmed(
 		customer.id,
 		customer.emailAddress,
+		command.MessageMeta(),
 		customer.currentStreamVersion+1,
 	)
 
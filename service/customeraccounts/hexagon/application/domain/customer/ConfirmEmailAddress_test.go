@@ -2,6 +2,7 @@ package customer_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
@@ -17,34 +18,63 @@ func TestConfirmEmailAddress(t *testing.T) {
 		var err error
 		var recordedEvents es.RecordedEvents
 
+		messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
+
 		customerID := value.GenerateCustomerID()
 		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
-		confirmationHash := value.GenerateConfirmationHash(emailAddress.String())
+		confirmationHash := value.GenerateConfirmationHash()
 		invalidConfirmationHash := value.RebuildConfirmationHash("invalid_hash")
 		personName := value.RebuildPersonName("Kevin", "Ball")
+		policy := customer.EmailConfirmationPolicy{ConfirmationHashTTL: time.Hour}
+		twoHoursAgo := time.Now().Add(-2 * time.Hour).Format(time.RFC3339Nano)
+		lockoutPolicy := customer.EmailConfirmationPolicy{
+			MaxFailedAttempts: 3,
+			FailureWindow:     15 * time.Minute,
+			LockoutCooldown:   15 * time.Minute,
+		}
+
+		confirmationFailedWithReasonMinutesAgo := func(reasonCode string, minutes int, streamVersion uint) es.DomainEvent {
+			occurredAt := time.Now().Add(-time.Duration(minutes) * time.Minute).Format(time.RFC3339Nano)
+
+			return domain.RebuildCustomerEmailAddressConfirmationFailed(
+				customerID.String(),
+				emailAddress.String(),
+				invalidConfirmationHash.String(),
+				reasonCode,
+				es.RebuildEventMeta("some-event-id", "CustomerEmailAddressConfirmationFailed", occurredAt, es.MessageMeta{}, streamVersion),
+			)
+		}
+
+		confirmationFailedMinutesAgo := func(minutes int, streamVersion uint) es.DomainEvent {
+			return confirmationFailedWithReasonMinutesAgo(domain.ConfirmationFailedWithWrongHash, minutes, streamVersion)
+		}
 
 		customerWasRegistered := domain.BuildCustomerRegistered(
 			customerID,
 			emailAddress,
 			confirmationHash,
 			personName,
+			es.MessageMeta{},
 			1,
 		)
 
 		customerEmailAddressWasConfirmed := domain.BuildCustomerEmailAddressConfirmed(
 			customerID,
 			emailAddress,
+			es.MessageMeta{},
 			2,
 		)
 
 		confirmEmailAddress := domain.BuildConfirmCustomerEmailAddress(
 			customerID,
 			confirmationHash,
+			messageMeta,
 		)
 
 		confirmEmailAddressWithInvalidHash := domain.BuildConfirmCustomerEmailAddress(
 			customerID,
 			invalidConfirmationHash,
+			messageMeta,
 		)
 
 		Convey("\nSCENARIO 1: Confirm a Customer's emailAddress with the right confirmationHash", func() {
@@ -52,7 +82,7 @@ func TestConfirmEmailAddress(t *testing.T) {
 				eventStream := es.EventStream{customerWasRegistered}
 
 				Convey("When ConfirmCustomerEmailAddress", func() {
-					recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress)
+					recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, policy)
 					So(err, ShouldBeNil)
 
 					Convey("Then CustomerEmailAddressConfirmed", func() {
@@ -62,6 +92,7 @@ func TestConfirmEmailAddress(t *testing.T) {
 						So(emailAddressConfirmed.CustomerID().Equals(customerID), ShouldBeTrue)
 						So(emailAddressConfirmed.EmailAddress().Equals(emailAddress), ShouldBeTrue)
 						So(emailAddressConfirmed.Meta().StreamVersion(), ShouldEqual, 2)
+						So(emailAddressConfirmed.Meta().MessageMeta(), ShouldResemble, messageMeta)
 					})
 				})
 			})
@@ -72,7 +103,7 @@ func TestConfirmEmailAddress(t *testing.T) {
 				eventStream := es.EventStream{customerWasRegistered}
 
 				Convey("When ConfirmCustomerEmailAddress", func() {
-					recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddressWithInvalidHash)
+					recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddressWithInvalidHash, policy)
 					So(err, ShouldBeNil)
 
 					Convey("Then CustomerEmailAddressConfirmationFailed", func() {
@@ -85,6 +116,7 @@ func TestConfirmEmailAddress(t *testing.T) {
 						So(emailAddressConfirmationFailed.IsFailureEvent(), ShouldBeTrue)
 						So(emailAddressConfirmationFailed.FailureReason(), ShouldBeError)
 						So(emailAddressConfirmationFailed.Meta().StreamVersion(), ShouldEqual, 2)
+						So(emailAddressConfirmationFailed.Meta().MessageMeta(), ShouldResemble, messageMeta)
 					})
 				})
 			})
@@ -98,7 +130,7 @@ func TestConfirmEmailAddress(t *testing.T) {
 					eventStream = append(eventStream, customerEmailAddressWasConfirmed)
 
 					Convey("When ConfirmCustomerEmailAddress", func() {
-						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress)
+						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, policy)
 						So(err, ShouldBeNil)
 
 						Convey("Then no event", func() {
@@ -117,7 +149,7 @@ func TestConfirmEmailAddress(t *testing.T) {
 					eventStream = append(eventStream, customerEmailAddressWasConfirmed)
 
 					Convey("When ConfirmCustomerEmailAddress", func() {
-						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddressWithInvalidHash)
+						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddressWithInvalidHash, policy)
 						So(err, ShouldBeNil)
 
 						Convey("Then CustomerEmailAddressConfirmationFailed", func() {
@@ -130,6 +162,7 @@ func TestConfirmEmailAddress(t *testing.T) {
 							So(emailAddressConfirmationFailed.IsFailureEvent(), ShouldBeTrue)
 							So(emailAddressConfirmationFailed.FailureReason(), ShouldBeError)
 							So(emailAddressConfirmationFailed.Meta().StreamVersion(), ShouldEqual, 3)
+							So(emailAddressConfirmationFailed.Meta().MessageMeta(), ShouldResemble, messageMeta)
 						})
 					})
 				})
@@ -143,11 +176,11 @@ func TestConfirmEmailAddress(t *testing.T) {
 				Convey("Given CustomerDeleted", func() {
 					eventStream = append(
 						eventStream,
-						domain.BuildCustomerDeleted(customerID, emailAddress, 2),
+						domain.BuildCustomerDeleted(customerID, emailAddress, es.MessageMeta{}, 2),
 					)
 
 					Convey("When ConfirmCustomerEmailAddress", func() {
-						_, err := customer.ConfirmEmailAddress(eventStream, confirmEmailAddress)
+						_, err := customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, policy)
 
 						Convey("Then it should report an error", func() {
 							So(err, ShouldBeError)
@@ -157,5 +190,235 @@ func TestConfirmEmailAddress(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("\nSCENARIO 6: Confirm a Customer's emailAddress with an expired confirmationHash", func() {
+			Convey("Given CustomerRegistered two hours ago", func() {
+				eventStream := es.EventStream{
+					domain.RebuildCustomerRegistered(
+						customerID.String(),
+						emailAddress.String(),
+						confirmationHash.String(),
+						personName.GivenName(),
+						personName.FamilyName(),
+						es.RebuildEventMeta("some-event-id", "CustomerRegistered", twoHoursAgo, es.MessageMeta{}, 1),
+					),
+				}
+
+				Convey("When ConfirmCustomerEmailAddress with a confirmationHash TTL of one hour", func() {
+					recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, policy)
+					So(err, ShouldBeNil)
+
+					Convey("Then CustomerEmailAddressConfirmationFailed because the confirmationHash has expired", func() {
+						So(recordedEvents, ShouldHaveLength, 1)
+						emailAddressConfirmationFailed, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmationFailed)
+						So(ok, ShouldBeTrue)
+						So(emailAddressConfirmationFailed.CustomerID().Equals(customerID), ShouldBeTrue)
+						So(emailAddressConfirmationFailed.ConfirmationHash().Equals(confirmationHash), ShouldBeTrue)
+						So(errors.Is(emailAddressConfirmationFailed.FailureReason(), domain.ErrConfirmationHashExpired), ShouldBeTrue)
+						So(errors.Is(emailAddressConfirmationFailed.FailureReason(), shared.ErrDomainConstraintsViolation), ShouldBeTrue)
+						So(emailAddressConfirmationFailed.Meta().StreamVersion(), ShouldEqual, 2)
+					})
+				})
+
+				Convey("When ConfirmCustomerEmailAddress without a confirmationHash TTL", func() {
+					recordedEvents, err = customer.ConfirmEmailAddress(
+						eventStream,
+						confirmEmailAddress,
+						customer.EmailConfirmationPolicy{},
+					)
+					So(err, ShouldBeNil)
+
+					Convey("Then CustomerEmailAddressConfirmed", func() {
+						So(recordedEvents, ShouldHaveLength, 1)
+						_, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmed)
+						So(ok, ShouldBeTrue)
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO 7: Confirm a Customer's emailAddress after a snapshot which has no confirmationHash issuedAt", func() {
+			Convey("Given a CustomerSnapshot which was taken two hours ago", func() {
+				eventStream := es.EventStream{
+					domain.RebuildCustomerSnapshot(
+						customerID.String(),
+						personName.GivenName(),
+						personName.FamilyName(),
+						emailAddress.String(),
+						confirmationHash.String(),
+						"",
+						nil,
+						false,
+						false,
+						es.RebuildEventMeta("some-event-id", "CustomerSnapshot", twoHoursAgo, es.MessageMeta{}, 1),
+					),
+				}
+
+				Convey("When ConfirmCustomerEmailAddress with a confirmationHash TTL of one hour", func() {
+					recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, policy)
+					So(err, ShouldBeNil)
+
+					Convey("Then CustomerEmailAddressConfirmationFailed because the confirmationHash has expired", func() {
+						So(recordedEvents, ShouldHaveLength, 1)
+						emailAddressConfirmationFailed, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmationFailed)
+						So(ok, ShouldBeTrue)
+						So(errors.Is(emailAddressConfirmationFailed.FailureReason(), domain.ErrConfirmationHashExpired), ShouldBeTrue)
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO 8: Confirm a Customer's emailAddress after too many failed attempts", func() {
+			Convey("Given CustomerRegistered", func() {
+				eventStream := es.EventStream{customerWasRegistered}
+
+				Convey("and 3 CustomerEmailAddressConfirmationFailed within 15 minutes", func() {
+					eventStream = append(
+						eventStream,
+						confirmationFailedMinutesAgo(12, 2),
+						confirmationFailedMinutesAgo(8, 3),
+						confirmationFailedMinutesAgo(1, 4),
+					)
+
+					Convey("When ConfirmCustomerEmailAddress with the right confirmationHash", func() {
+						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, lockoutPolicy)
+
+						Convey("Then it should report an error", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, customer.ErrConfirmationLockedOut), ShouldBeTrue)
+							So(errors.Is(err, shared.ErrTooManyAttempts), ShouldBeTrue)
+							So(recordedEvents, ShouldBeEmpty)
+						})
+					})
+
+					Convey("and CustomerEmailConfirmationRegenerated", func() {
+						regenerateEmailConfirmation := domain.BuildRegenerateCustomerEmailConfirmation(customerID, messageMeta)
+						eventStream = append(
+							eventStream,
+							domain.BuildCustomerEmailConfirmationRegenerated(
+								customerID,
+								emailAddress,
+								regenerateEmailConfirmation.ConfirmationHash(),
+								es.MessageMeta{},
+								5,
+							),
+						)
+
+						Convey("When ConfirmCustomerEmailAddress with the new confirmationHash", func() {
+							confirmEmailAddress = domain.BuildConfirmCustomerEmailAddress(
+								customerID,
+								regenerateEmailConfirmation.ConfirmationHash(),
+								messageMeta,
+							)
+
+							recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, lockoutPolicy)
+							So(err, ShouldBeNil)
+
+							Convey("Then CustomerEmailAddressConfirmed", func() {
+								So(recordedEvents, ShouldHaveLength, 1)
+								_, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmed)
+								So(ok, ShouldBeTrue)
+							})
+						})
+					})
+				})
+
+				Convey("and 3 CustomerEmailAddressConfirmationFailed within 15 minutes, but the cooldown has passed", func() {
+					eventStream = append(
+						eventStream,
+						confirmationFailedMinutesAgo(40, 2),
+						confirmationFailedMinutesAgo(35, 3),
+						confirmationFailedMinutesAgo(30, 4),
+					)
+
+					Convey("When ConfirmCustomerEmailAddress with the right confirmationHash", func() {
+						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, lockoutPolicy)
+						So(err, ShouldBeNil)
+
+						Convey("Then CustomerEmailAddressConfirmed", func() {
+							So(recordedEvents, ShouldHaveLength, 1)
+							_, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmed)
+							So(ok, ShouldBeTrue)
+						})
+					})
+				})
+
+				Convey("and 3 CustomerEmailAddressConfirmationFailed, but not within 15 minutes", func() {
+					eventStream = append(
+						eventStream,
+						confirmationFailedMinutesAgo(25, 2),
+						confirmationFailedMinutesAgo(9, 3),
+						confirmationFailedMinutesAgo(1, 4),
+					)
+
+					Convey("When ConfirmCustomerEmailAddress with a wrong confirmationHash", func() {
+						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddressWithInvalidHash, lockoutPolicy)
+						So(err, ShouldBeNil)
+
+						Convey("Then CustomerEmailAddressConfirmationFailed", func() {
+							So(recordedEvents, ShouldHaveLength, 1)
+							_, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmationFailed)
+							So(ok, ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO 9: Confirm a Customer's emailAddress after too many failed attempts and a snapshot", func() {
+			Convey("Given CustomerRegistered", func() {
+				eventStream := es.EventStream{customerWasRegistered}
+
+				Convey("and 3 CustomerEmailAddressConfirmationFailed within 15 minutes", func() {
+					eventStream = append(
+						eventStream,
+						confirmationFailedMinutesAgo(12, 2),
+						confirmationFailedMinutesAgo(8, 3),
+						confirmationFailedMinutesAgo(1, 4),
+					)
+
+					Convey("and a CustomerSnapshot which was built from those events", func() {
+						eventStream = es.EventStream{customer.BuildSnapshot(eventStream)}
+
+						Convey("When ConfirmCustomerEmailAddress with the right confirmationHash", func() {
+							recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, lockoutPolicy)
+
+							Convey("Then it should report an error", func() {
+								So(err, ShouldBeError)
+								So(errors.Is(err, customer.ErrConfirmationLockedOut), ShouldBeTrue)
+								So(errors.Is(err, shared.ErrTooManyAttempts), ShouldBeTrue)
+								So(recordedEvents, ShouldBeEmpty)
+							})
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO 10: Confirm a Customer's emailAddress after failed attempts with an expired confirmationHash", func() {
+			Convey("Given CustomerRegistered", func() {
+				eventStream := es.EventStream{customerWasRegistered}
+
+				Convey("and 3 CustomerEmailAddressConfirmationFailed within 15 minutes because the confirmationHash had expired", func() {
+					eventStream = append(
+						eventStream,
+						confirmationFailedWithReasonMinutesAgo(domain.ConfirmationFailedWithExpiredHash, 12, 2),
+						confirmationFailedWithReasonMinutesAgo(domain.ConfirmationFailedWithExpiredHash, 8, 3),
+						confirmationFailedWithReasonMinutesAgo(domain.ConfirmationFailedWithExpiredHash, 1, 4),
+					)
+
+					Convey("When ConfirmCustomerEmailAddress with the right confirmationHash", func() {
+						recordedEvents, err = customer.ConfirmEmailAddress(eventStream, confirmEmailAddress, lockoutPolicy)
+						So(err, ShouldBeNil)
+
+						Convey("Then CustomerEmailAddressConfirmed, because expired confirmationHashes don't count towards the lockout", func() {
+							So(recordedEvents, ShouldHaveLength, 1)
+							_, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmed)
+							So(ok, ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
 	})
 }
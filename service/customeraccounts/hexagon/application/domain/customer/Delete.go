@@ -15,6 +15,7 @@ func Delete(eventStream es.EventStream, command domain.DeleteCustomer) es.Record
 	event := domain.BuildCustomerDeleted(
 		command.CustomerID(),
 		customer.emailAddress,
+		command.MessageMeta(),
 		customer.currentStreamVersion+1,
 	)
 
@@ -12,9 +12,10 @@ import (
 
 func TestDelete(t *testing.T) {
 	Convey("Prepare test artifacts", t, func() {
+		messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
 		customerID := value.GenerateCustomerID()
 		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
-		confirmationHash := value.GenerateConfirmationHash(emailAddress.String())
+		confirmationHash := value.GenerateConfirmationHash()
 		personName := value.RebuildPersonName("Kevin", "Ball")
 
 		customerWasRegistered := domain.BuildCustomerRegistered(
@@ -22,10 +23,11 @@ func TestDelete(t *testing.T) {
 			emailAddress,
 			confirmationHash,
 			personName,
+			es.MessageMeta{},
 			1,
 		)
 
-		deleteCmd := domain.BuildDeleteCustomer(customerID)
+		deleteCmd := domain.BuildDeleteCustomer(customerID, messageMeta)
 
 		Convey("\nSCENARIO 1: Delete a Customer's account", func() {
 			Convey("Given CustomerRegistered", func() {
@@ -44,6 +46,7 @@ func TestDelete(t *testing.T) {
 						So(customerDeleted.IsFailureEvent(), ShouldBeFalse)
 						So(customerDeleted.FailureReason(), ShouldBeNil)
 						So(customerDeleted.Meta().StreamVersion(), ShouldEqual, uint(2))
+						So(customerDeleted.Meta().MessageMeta(), ShouldResemble, messageMeta)
 					})
 				})
 			})
@@ -54,7 +57,7 @@ func TestDelete(t *testing.T) {
 				eventStream := es.EventStream{customerWasRegistered}
 
 				Convey("and CustomerDeleted", func() {
-					customerDeleted := domain.BuildCustomerDeleted(customerID, emailAddress, 2)
+					customerDeleted := domain.BuildCustomerDeleted(customerID, emailAddress, es.MessageMeta{}, 2)
 					eventStream = append(eventStream, customerDeleted)
 
 					Convey("When DeleteCustomer", func() {
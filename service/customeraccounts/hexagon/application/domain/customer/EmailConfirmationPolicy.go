@@ -0,0 +1,15 @@
+package customer
+
+import (
+	"time"
+)
+
+// EmailConfirmationPolicy is configured by the operator of the service, the zero value has no restrictions.
+// After MaxFailedAttempts failed confirmations within FailureWindow, further attempts are locked out
+// for LockoutCooldown or until a new confirmation hash is issued.
+type EmailConfirmationPolicy struct {
+	ConfirmationHashTTL time.Duration
+	MaxFailedAttempts   uint
+	FailureWindow       time.Duration
+	LockoutCooldown     time.Duration
+}
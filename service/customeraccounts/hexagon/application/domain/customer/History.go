@@ -0,0 +1,59 @@
+package customer
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// HistoryEntry is an event of a Customer as shown in the audit history.
+// The Payload only contains the fields which are listed for each event type below, never the confirmation hashes.
+type HistoryEntry struct {
+	EventName  string
+	OccurredAt string
+	Version    uint
+	Payload    map[string]string
+}
+
+type History struct {
+	Events          []HistoryEntry
+	NextFromVersion uint // 0 on the last page
+}
+
+func BuildHistoryFrom(eventStream es.EventStream) []HistoryEntry {
+	entries := make([]HistoryEntry, 0, len(eventStream))
+
+	for _, event := range eventStream {
+		entry := HistoryEntry{
+			EventName:  event.Meta().EventName(),
+			OccurredAt: event.Meta().OccurredAt(),
+			Version:    event.Meta().StreamVersion(),
+			Payload:    make(map[string]string),
+		}
+
+		switch actualEvent := event.(type) {
+		case domain.CustomerRegistered:
+			entry.Payload["emailAddress"] = actualEvent.EmailAddress().String()
+			entry.Payload["givenName"] = actualEvent.PersonName().GivenName()
+			entry.Payload["familyName"] = actualEvent.PersonName().FamilyName()
+		case domain.CustomerEmailAddressConfirmed:
+			entry.Payload["emailAddress"] = actualEvent.EmailAddress().String()
+		case domain.CustomerEmailAddressConfirmationFailed:
+			entry.Payload["emailAddress"] = actualEvent.EmailAddress().String()
+			entry.Payload["reason"] = actualEvent.FailureReason().Error()
+		case domain.CustomerEmailAddressChanged:
+			entry.Payload["emailAddress"] = actualEvent.EmailAddress().String()
+			entry.Payload["previousEmailAddress"] = actualEvent.PreviousEmailAddress().String()
+		case domain.CustomerEmailConfirmationRegenerated:
+			entry.Payload["emailAddress"] = actualEvent.EmailAddress().String()
+		case domain.CustomerNameChanged:
+			entry.Payload["givenName"] = actualEvent.PersonName().GivenName()
+			entry.Payload["familyName"] = actualEvent.PersonName().FamilyName()
+		case domain.CustomerDeleted:
+			entry.Payload["emailAddress"] = actualEvent.EmailAddress().String()
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
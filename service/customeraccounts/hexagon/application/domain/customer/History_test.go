@@ -0,0 +1,98 @@
+package customer_test
+
+import (
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildHistoryFrom(t *testing.T) {
+	Convey("Prepare test artifacts", t, func() {
+		customerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
+		changedEmailAddress := value.RebuildEmailAddress("kev@ball.com")
+		confirmationHash := value.GenerateConfirmationHash()
+		changedConfirmationHash := value.GenerateConfirmationHash()
+		personName := value.RebuildPersonName("Kevin", "Ball")
+
+		customerWasRegistered := domain.BuildCustomerRegistered(
+			customerID,
+			emailAddress,
+			confirmationHash,
+			personName,
+			es.MessageMeta{},
+			1,
+		)
+
+		customerEmailAddressConfirmationHasFailed := domain.BuildCustomerEmailAddressConfirmationFailed(
+			customerID,
+			emailAddress,
+			value.GenerateConfirmationHash(),
+			errors.Mark(errors.New("wrong confirmation hash supplied"), shared.ErrDomainConstraintsViolation),
+			es.MessageMeta{},
+			2,
+		)
+
+		customerEmailAddressWasChanged := domain.BuildCustomerEmailAddressChanged(
+			customerID,
+			changedEmailAddress,
+			changedConfirmationHash,
+			emailAddress,
+			es.MessageMeta{},
+			3,
+		)
+
+		Convey("Given CustomerRegistered, CustomerEmailAddressConfirmationFailed and CustomerEmailAddressChanged", func() {
+			eventStream := es.EventStream{
+				customerWasRegistered,
+				customerEmailAddressConfirmationHasFailed,
+				customerEmailAddressWasChanged,
+			}
+
+			Convey("When the history is built", func() {
+				history := customer.BuildHistoryFrom(eventStream)
+
+				Convey("Then it should contain the events in order", func() {
+					So(history, ShouldHaveLength, 3)
+
+					for idx, entry := range history {
+						So(entry.EventName, ShouldEqual, eventStream[idx].Meta().EventName())
+						So(entry.OccurredAt, ShouldEqual, eventStream[idx].Meta().OccurredAt())
+						So(entry.Version, ShouldEqual, idx+1)
+					}
+
+					So(history[0].Payload, ShouldResemble, map[string]string{
+						"emailAddress": emailAddress.String(),
+						"givenName":    personName.GivenName(),
+						"familyName":   personName.FamilyName(),
+					})
+
+					So(history[1].Payload, ShouldResemble, map[string]string{
+						"emailAddress": emailAddress.String(),
+						"reason":       "wrong confirmation hash supplied",
+					})
+
+					So(history[2].Payload, ShouldResemble, map[string]string{
+						"emailAddress":         changedEmailAddress.String(),
+						"previousEmailAddress": emailAddress.String(),
+					})
+				})
+
+				Convey("And it should never contain a confirmation hash", func() {
+					for _, entry := range history {
+						for _, field := range entry.Payload {
+							So(field, ShouldNotEqual, confirmationHash.String())
+							So(field, ShouldNotEqual, changedConfirmationHash.String())
+						}
+					}
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,12 @@
+package customer
+
+// ListView is a Customer in the Customer list, which is read from a projection instead of the event stream.
+type ListView struct {
+	View
+	RegisteredAt string
+}
+
+type ListPage struct {
+	Customers     []ListView
+	NextPageToken string // empty on the last page
+}
@@ -0,0 +1,33 @@
+package customer
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+func RegenerateEmailConfirmation(
+	eventStream es.EventStream,
+	command domain.RegenerateCustomerEmailConfirmation,
+) (es.RecordedEvents, error) {
+
+	customer := buildCurrentStateFrom(eventStream)
+
+	if err := assertNotDeleted(customer); err != nil {
+		return nil, errors.Wrap(err, "regenerateEmailConfirmation")
+	}
+
+	if err := assertEmailAddressNotConfirmed(customer); err != nil {
+		return nil, errors.Wrap(err, "regenerateEmailConfirmation")
+	}
+
+	event := domain.BuildCustomerEmailConfirmationRegenerated(
+		customer.id,
+		customer.emailAddress,
+		command.ConfirmationHash(),
+		command.MessageMeta(),
+		customer.currentStreamVersion+1,
+	)
+
+	return es.RecordedEvents{event}, nil
+}
@@ -0,0 +1,127 @@
+package customer_test
+
+import (
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegenerateEmailConfirmation(t *testing.T) {
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+		var recordedEvents es.RecordedEvents
+
+		messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
+
+		customerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
+		confirmationHash := value.GenerateConfirmationHash()
+		personName := value.RebuildPersonName("Kevin", "Ball")
+
+		customerWasRegistered := domain.BuildCustomerRegistered(
+			customerID,
+			emailAddress,
+			confirmationHash,
+			personName,
+			es.MessageMeta{},
+			1,
+		)
+
+		regenerateEmailConfirmation := domain.BuildRegenerateCustomerEmailConfirmation(customerID, messageMeta)
+		regeneratedConfirmationHash := regenerateEmailConfirmation.ConfirmationHash()
+
+		Convey("\nSCENARIO 1: Regenerate the confirmation hash of a Customer's unconfirmed emailAddress", func() {
+			Convey("Given CustomerRegistered", func() {
+				eventStream := es.EventStream{customerWasRegistered}
+
+				Convey("When RegenerateCustomerEmailConfirmation", func() {
+					recordedEvents, err = customer.RegenerateEmailConfirmation(eventStream, regenerateEmailConfirmation)
+					So(err, ShouldBeNil)
+
+					Convey("Then CustomerEmailConfirmationRegenerated", func() {
+						So(recordedEvents, ShouldHaveLength, 1)
+						regenerated, ok := recordedEvents[0].(domain.CustomerEmailConfirmationRegenerated)
+						So(ok, ShouldBeTrue)
+						So(regenerated.CustomerID().Equals(customerID), ShouldBeTrue)
+						So(regenerated.EmailAddress().Equals(emailAddress), ShouldBeTrue)
+						So(regenerated.ConfirmationHash().Equals(regeneratedConfirmationHash), ShouldBeTrue)
+						So(regenerated.ConfirmationHash().Equals(confirmationHash), ShouldBeFalse)
+						So(regenerated.IsFailureEvent(), ShouldBeFalse)
+						So(regenerated.FailureReason(), ShouldBeNil)
+						So(regenerated.Meta().StreamVersion(), ShouldEqual, 2)
+						So(regenerated.Meta().MessageMeta(), ShouldResemble, messageMeta)
+
+						Convey("and the previous confirmation hash is no longer accepted", func() {
+							eventStream = append(eventStream, regenerated)
+							confirmEmailAddress := domain.BuildConfirmCustomerEmailAddress(
+								customerID,
+								confirmationHash,
+								messageMeta,
+							)
+
+							recordedEvents, err = customer.ConfirmEmailAddress(
+								eventStream,
+								confirmEmailAddress,
+								customer.EmailConfirmationPolicy{},
+							)
+							So(err, ShouldBeNil)
+							So(recordedEvents, ShouldHaveLength, 1)
+							_, ok := recordedEvents[0].(domain.CustomerEmailAddressConfirmationFailed)
+							So(ok, ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO 2: Try to regenerate the confirmation hash of a Customer's confirmed emailAddress", func() {
+			Convey("Given CustomerRegistered", func() {
+				eventStream := es.EventStream{customerWasRegistered}
+
+				Convey("and CustomerEmailAddressConfirmed", func() {
+					eventStream = append(
+						eventStream,
+						domain.BuildCustomerEmailAddressConfirmed(customerID, emailAddress, es.MessageMeta{}, 2),
+					)
+
+					Convey("When RegenerateCustomerEmailConfirmation", func() {
+						_, err = customer.RegenerateEmailConfirmation(eventStream, regenerateEmailConfirmation)
+
+						Convey("Then it should report an error", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, shared.ErrDomainConstraintsViolation), ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+
+		Convey("\nSCENARIO 3: Try to regenerate the confirmation hash when the account was deleted", func() {
+			Convey("Given CustomerRegistered", func() {
+				eventStream := es.EventStream{customerWasRegistered}
+
+				Convey("Given CustomerDeleted", func() {
+					eventStream = append(
+						eventStream,
+						domain.BuildCustomerDeleted(customerID, emailAddress, es.MessageMeta{}, 2),
+					)
+
+					Convey("When RegenerateCustomerEmailConfirmation", func() {
+						_, err = customer.RegenerateEmailConfirmation(eventStream, regenerateEmailConfirmation)
+
+						Convey("Then it should report an error", func() {
+							So(err, ShouldBeError)
+							So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+						})
+					})
+				})
+			})
+		})
+	})
+}
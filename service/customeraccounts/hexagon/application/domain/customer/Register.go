@@ -10,6 +10,7 @@ func Register(with domain.RegisterCustomer) domain.CustomerRegistered {
 		with.EmailAddress(),
 		with.ConfirmationHash(),
 		with.PersonName(),
+		with.MessageMeta(),
 		1,
 	)
 
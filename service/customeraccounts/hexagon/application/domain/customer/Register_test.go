@@ -6,6 +6,7 @@ import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -13,6 +14,8 @@ func TestRegister(t *testing.T) {
 	Convey("Prepare test artifacts", t, func() {
 		var err error
 
+		messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
+
 		emailAddress, err := value.BuildEmailAddress("kevin@ball.com")
 		So(err, ShouldBeNil)
 
@@ -22,8 +25,9 @@ func TestRegister(t *testing.T) {
 		register := domain.BuildRegisterCustomer(
 			value.GenerateCustomerID(),
 			emailAddress,
-			value.GenerateConfirmationHash("kevin@ball.com"),
+			value.GenerateConfirmationHash(),
 			personName,
+			messageMeta,
 		)
 
 		Convey("\nSCENARIO: Register a Customer", func() {
@@ -38,6 +42,7 @@ func TestRegister(t *testing.T) {
 					So(registered.IsFailureEvent(), ShouldBeFalse)
 					So(registered.FailureReason(), ShouldBeNil)
 					So(registered.Meta().StreamVersion(), ShouldEqual, uint(1))
+					So(registered.Meta().MessageMeta(), ShouldResemble, messageMeta)
 				})
 			})
 		})
@@ -0,0 +1,18 @@
+package customer
+
+import (
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+func assertConfirmationHashNotExpired(current value.ConfirmationHash, policy EmailConfirmationPolicy) error {
+	if current.IsExpired(policy.ConfirmationHashTTL, time.Now()) {
+		return errors.Mark(domain.ErrConfirmationHashExpired, shared.ErrDomainConstraintsViolation)
+	}
+
+	return nil
+}
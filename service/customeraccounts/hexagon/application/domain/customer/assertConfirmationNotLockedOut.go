@@ -0,0 +1,35 @@
+package customer
+
+import (
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+var ErrConfirmationLockedOut = errors.New("too many failed confirmation attempts, try again later")
+
+// assertConfirmationNotLockedOut expects the failed confirmations in the order they occurred.
+// Attempts during a lockout are not recorded, so they don't extend it.
+func assertConfirmationNotLockedOut(failedConfirmations []time.Time, policy EmailConfirmationPolicy) error {
+	if policy.MaxFailedAttempts == 0 || uint(len(failedConfirmations)) < policy.MaxFailedAttempts {
+		return nil
+	}
+
+	var lockedOutUntil time.Time
+	maxFailed := int(policy.MaxFailedAttempts)
+
+	for i := maxFailed - 1; i < len(failedConfirmations); i++ {
+		firstInWindow := failedConfirmations[i-maxFailed+1]
+
+		if failedConfirmations[i].Sub(firstInWindow) <= policy.FailureWindow {
+			lockedOutUntil = failedConfirmations[i].Add(policy.LockoutCooldown)
+		}
+	}
+
+	if time.Now().Before(lockedOutUntil) {
+		return errors.Mark(ErrConfirmationLockedOut, shared.ErrTooManyAttempts)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package customer
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+func assertEmailAddressNotConfirmed(currentState currentState) error {
+	if currentState.isEmailAddressConfirmed {
+		return errors.Mark(errors.New("email address is already confirmed"), shared.ErrDomainConstraintsViolation)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package customer
 
 import (
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/cockroachdb/errors"
@@ -8,7 +9,7 @@ import (
 
 func assertMatchingConfirmationHash(current value.ConfirmationHash, supplied value.ConfirmationHash) error {
 	if !current.Equals(supplied) {
-		return errors.Mark(errors.New("wrong confirmation hash supplied"), shared.ErrDomainConstraintsViolation)
+		return errors.Mark(domain.ErrWrongConfirmationHash, shared.ErrDomainConstraintsViolation)
 	}
 
 	return nil
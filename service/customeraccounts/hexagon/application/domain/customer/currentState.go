@@ -1,6 +1,8 @@
 package customer
 
 import (
+	"time"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
@@ -11,6 +13,7 @@ type currentState struct {
 	personName                   value.PersonName
 	emailAddress                 value.EmailAddress
 	emailAddressConfirmationHash value.ConfirmationHash
+	failedConfirmations          []time.Time // with wrong hashes, since the confirmation hash was issued
 	isEmailAddressConfirmed      bool
 	isDeleted                    bool
 	currentStreamVersion         uint
@@ -28,14 +31,42 @@ func buildCurrentStateFrom(eventStream es.EventStream) currentState {
 			customer.emailAddressConfirmationHash = actualEvent.ConfirmationHash()
 		case domain.CustomerEmailAddressConfirmed:
 			customer.isEmailAddressConfirmed = true
+			customer.failedConfirmations = nil
+		case domain.CustomerEmailAddressConfirmationFailed:
+			occurredAt, err := time.Parse(time.RFC3339Nano, actualEvent.Meta().OccurredAt())
+
+			// confirming with an expired hash is no guess, so it doesn't count towards the lockout
+			if err == nil && actualEvent.ReasonCode() != domain.ConfirmationFailedWithExpiredHash {
+				customer.failedConfirmations = append(customer.failedConfirmations, occurredAt)
+			}
 		case domain.CustomerEmailAddressChanged:
 			customer.emailAddress = actualEvent.EmailAddress()
 			customer.emailAddressConfirmationHash = actualEvent.ConfirmationHash()
+			customer.failedConfirmations = nil
 			customer.isEmailAddressConfirmed = false
+		case domain.CustomerEmailConfirmationRegenerated:
+			customer.emailAddressConfirmationHash = actualEvent.ConfirmationHash()
+			customer.failedConfirmations = nil
 		case domain.CustomerNameChanged:
 			customer.personName = actualEvent.PersonName()
 		case domain.CustomerDeleted:
 			customer.isDeleted = true
+		case domain.CustomerSnapshot:
+			customer.id = actualEvent.CustomerID()
+			customer.personName = actualEvent.PersonName()
+			customer.emailAddress = actualEvent.EmailAddress()
+			customer.emailAddressConfirmationHash = actualEvent.EmailAddressConfirmationHash()
+			customer.failedConfirmations = append([]time.Time(nil), actualEvent.FailedConfirmations()...)
+
+			// snapshots taken before confirmation hashes could expire count as the time when the hash was issued
+			if customer.emailAddressConfirmationHash.IssuedAt().IsZero() {
+				customer.emailAddressConfirmationHash = customer.emailAddressConfirmationHash.WithIssuedAt(
+					actualEvent.Meta().OccurredAt(),
+				)
+			}
+
+			customer.isEmailAddressConfirmed = actualEvent.IsEmailAddressConfirmed()
+			customer.isDeleted = actualEvent.IsDeleted()
 		}
 
 		customer.currentStreamVersion = event.Meta().StreamVersion()
@@ -1,26 +1,33 @@
 package value
 
 import (
-	"crypto/sha256"
-	"fmt"
-	"math/rand"
-	"strconv"
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/cockroachdb/errors"
 )
 
+const confirmationHashLength = 32 // random bytes, the hash is their hex encoding
+
+// ConfirmationHash is issued by the event which records it, so issuedAt is the time when that event occurred.
+// It is zero for hashes supplied by Customers and for hashes of events which were not yet recorded.
 type ConfirmationHash struct {
-	value string
+	value    string
+	issuedAt time.Time
 }
 
-func GenerateConfirmationHash(using string) ConfirmationHash {
-	randomInt := rand.New(rand.NewSource(time.Now().UnixNano())).Int()
-	sha256Sum := sha256.Sum256([]byte(using + strconv.Itoa(randomInt)))
-	value := fmt.Sprintf("%x", sha256Sum)
+// GenerateConfirmationHash panics if the random source fails, as GenerateCustomerID (via uuid.New) does.
+// The hash must not be guessable, otherwise the lockout after failed confirmations would not protect anything.
+func GenerateConfirmationHash() ConfirmationHash {
+	randomBytes := make([]byte, confirmationHashLength)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		panic(errors.Wrap(err, "generateConfirmationHash"))
+	}
 
-	return ConfirmationHash{value: value}
+	return ConfirmationHash{value: hex.EncodeToString(randomBytes)}
 }
 
 func BuildConfirmationHash(input string) (ConfirmationHash, error) {
@@ -44,6 +51,30 @@ func (confirmationHash ConfirmationHash) String() string {
 	return confirmationHash.value
 }
 
+func (confirmationHash ConfirmationHash) IssuedAt() time.Time {
+	return confirmationHash.issuedAt
+}
+
+// WithIssuedAt takes the OccurredAt of the issuing event, which is formatted as RFC3339 with nanoseconds.
+func (confirmationHash ConfirmationHash) WithIssuedAt(occurredAt string) ConfirmationHash {
+	issuedAt, err := time.Parse(time.RFC3339Nano, occurredAt)
+	if err != nil {
+		issuedAt = time.Time{}
+	}
+
+	return ConfirmationHash{value: confirmationHash.value, issuedAt: issuedAt}
+}
+
+// IsExpired is never true if the ttl is zero or if it's unknown when the hash was issued.
+func (confirmationHash ConfirmationHash) IsExpired(ttl time.Duration, now time.Time) bool {
+	if ttl == 0 || confirmationHash.issuedAt.IsZero() {
+		return false
+	}
+
+	return now.After(confirmationHash.issuedAt.Add(ttl))
+}
+
+// Equals ignores when the hashes were issued.
 func (confirmationHash ConfirmationHash) Equals(other ConfirmationHash) bool {
 	return confirmationHash.value == other.value
 }
@@ -0,0 +1,61 @@
+package value_test
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConfirmationHash_IsExpired(t *testing.T) {
+	Convey("Given a ConfirmationHash which was issued two hours ago", t, func() {
+		issuedAt := time.Now().Add(-2 * time.Hour)
+		confirmationHash := value.RebuildConfirmationHash("some-hash").WithIssuedAt(issuedAt.Format(time.RFC3339Nano))
+
+		Convey("Then it should know when it was issued", func() {
+			So(confirmationHash.IssuedAt().Equal(issuedAt), ShouldBeTrue)
+		})
+
+		Convey("Then it should be expired after a TTL of one hour", func() {
+			So(confirmationHash.IsExpired(time.Hour, time.Now()), ShouldBeTrue)
+		})
+
+		Convey("Then it should not be expired after a TTL of three hours", func() {
+			So(confirmationHash.IsExpired(3*time.Hour, time.Now()), ShouldBeFalse)
+		})
+
+		Convey("Then it should never expire without a TTL", func() {
+			So(confirmationHash.IsExpired(0, time.Now()), ShouldBeFalse)
+		})
+
+		Convey("Then it should still equal the same hash without issuedAt", func() {
+			So(confirmationHash.Equals(value.RebuildConfirmationHash("some-hash")), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a ConfirmationHash without issuedAt", t, func() {
+		confirmationHash := value.RebuildConfirmationHash("some-hash").WithIssuedAt("not a time")
+
+		Convey("Then it should never expire", func() {
+			So(confirmationHash.IssuedAt().IsZero(), ShouldBeTrue)
+			So(confirmationHash.IsExpired(time.Nanosecond, time.Now()), ShouldBeFalse)
+		})
+	})
+}
+
+func TestGenerateConfirmationHash(t *testing.T) {
+	Convey("When two ConfirmationHashes are generated", t, func() {
+		confirmationHash := value.GenerateConfirmationHash()
+		otherConfirmationHash := value.GenerateConfirmationHash()
+
+		Convey("Then they should be 32 random bytes encoded as hex", func() {
+			So(confirmationHash.String(), ShouldHaveLength, 64)
+			So(confirmationHash.String(), ShouldNotEqual, otherConfirmationHash.String())
+
+			_, err := hex.DecodeString(confirmationHash.String())
+			So(err, ShouldBeNil)
+		})
+	})
+}
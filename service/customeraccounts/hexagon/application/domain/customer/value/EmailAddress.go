@@ -2,40 +2,84 @@ package value
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/cockroachdb/errors"
+	"golang.org/x/net/idna"
 )
 
 var (
-	emailAddressRegExp = regexp.MustCompile(`^[^\s]+@[^\s]+\.[\w]{2,}$`)
+	emailAddressRegExp = regexp.MustCompile(`^[^\s]+@[^\s]+\.[\w-]{2,}$`)
 )
 
+// EmailAddress keeps the input (trimmed) to show it as entered, while uniqueness checks and lookups
+// must use the Canonical form: lowercased and with an internationalized domain name converted to punycode.
+// The local part (before the @) is always lowercased, although RFC 5321 allows case-sensitive ones, because most
+// mail providers treat it case-insensitively. It is no option, because the stored unique email addresses would
+// not match the canonical form anymore once it was switched.
 type EmailAddress struct {
-	value string
+	value     string
+	canonical string
 }
 
 func BuildEmailAddress(input string) (EmailAddress, error) {
-	if matched := emailAddressRegExp.MatchString(input); !matched {
+	wrapWithMsg := "BuildEmailAddress"
+	input = strings.TrimSpace(input)
+
+	canonical, err := canonicalizeEmailAddress(input)
+	if err != nil {
+		return EmailAddress{}, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	}
+
+	if matched := emailAddressRegExp.MatchString(canonical); !matched {
 		err := errors.New("input has invalid format")
-		err = shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, "BuildEmailAddress")
+		err = shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
 
 		return EmailAddress{}, err
 	}
 
-	emailAddress := EmailAddress{value: input}
+	emailAddress := EmailAddress{value: input, canonical: canonical}
 
 	return emailAddress, nil
 }
 
+// RebuildEmailAddress falls back to a lowercased canonical form for input which BuildEmailAddress would reject,
+// e.g. redacted email addresses of forgotten Customers.
 func RebuildEmailAddress(input string) EmailAddress {
-	return EmailAddress{value: input}
+	canonical, err := canonicalizeEmailAddress(input)
+	if err != nil {
+		canonical = strings.ToLower(input)
+	}
+
+	return EmailAddress{value: input, canonical: canonical}
 }
 
 func (emailAddress EmailAddress) String() string {
 	return emailAddress.value
 }
 
+func (emailAddress EmailAddress) Canonical() string {
+	return emailAddress.canonical
+}
+
+// Equals compares the email addresses as entered, so that changing only the case is still a change.
 func (emailAddress EmailAddress) Equals(other EmailAddress) bool {
 	return emailAddress.value == other.value
 }
+
+func canonicalizeEmailAddress(input string) (string, error) {
+	at := strings.LastIndex(input, "@")
+	if at < 1 {
+		return "", errors.New("input has invalid format")
+	}
+
+	localPart, domain := strings.ToLower(input[:at]), input[at+1:]
+
+	domain, err := idna.Lookup.ToASCII(strings.ToLower(domain))
+	if err != nil {
+		return "", errors.Wrap(err, "input has an invalid domain")
+	}
+
+	return localPart + "@" + domain, nil
+}
@@ -0,0 +1,55 @@
+package value_test
+
+import (
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildEmailAddress(t *testing.T) {
+	Convey("When an EmailAddress is built from valid input", t, func() {
+		for input, expected := range map[string]struct{ value, canonical string }{
+			"fiona@gallagher.net":      {"fiona@gallagher.net", "fiona@gallagher.net"},
+			" Fiona@Gallagher.NET\n":   {"Fiona@Gallagher.NET", "fiona@gallagher.net"},
+			"ian@gallagher-müller.de":  {"ian@gallagher-müller.de", "ian@xn--gallagher-mller-9vb.de"},
+			"Lip@GALLAGHER.xn--p1ai":   {"Lip@GALLAGHER.xn--p1ai", "lip@gallagher.xn--p1ai"},
+			"\"debbie@home\"@ball.com": {"\"debbie@home\"@ball.com", "\"debbie@home\"@ball.com"},
+		} {
+			emailAddress, err := value.BuildEmailAddress(input)
+
+			Convey("Then it should keep ["+expected.value+"] and be unique as ["+expected.canonical+"]", func() {
+				So(err, ShouldBeNil)
+				So(emailAddress.String(), ShouldEqual, expected.value)
+				So(emailAddress.Canonical(), ShouldEqual, expected.canonical)
+			})
+		}
+	})
+
+	Convey("When an EmailAddress is built from invalid input", t, func() {
+		for _, input := range []string{"", "fiona", "@gallagher.net", "fiona@gallagher", "fiona@gal lagher.net", "fiona@-gallagher.net"} {
+			_, err := value.BuildEmailAddress(input)
+
+			Convey("Then it should fail for ["+input+"]", func() {
+				So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+			})
+		}
+	})
+}
+
+func TestEmailAddress_Equals(t *testing.T) {
+	Convey("Given an EmailAddress", t, func() {
+		emailAddress := value.RebuildEmailAddress("Fiona@Gallagher.net")
+
+		Convey("When it is compared with an EmailAddress which only differs in case", func() {
+			otherEmailAddress := value.RebuildEmailAddress("fiona@gallagher.net")
+
+			Convey("Then it should not be equal, but have the same canonical form", func() {
+				So(emailAddress.Equals(otherEmailAddress), ShouldBeFalse)
+				So(emailAddress.Canonical(), ShouldEqual, otherEmailAddress.Canonical())
+			})
+		})
+	})
+}
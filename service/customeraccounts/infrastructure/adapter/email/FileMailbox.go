@@ -0,0 +1,51 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+)
+
+// FileMailbox does not deliver emails, it writes each one as .eml file into a directory,
+// e.g. for development and tests.
+type FileMailbox struct {
+	directory string
+	from      string
+	sequence  uint64
+}
+
+func NewFileMailbox(directory string, from string) *FileMailbox {
+	return &FileMailbox{directory: directory, from: from}
+}
+
+func (mailbox *FileMailbox) Send(ctx context.Context, email application.Email) error {
+	wrapWithMsg := "fileMailbox.Send"
+
+	if err := os.MkdirAll(mailbox.directory, 0700); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	now := time.Now()
+	fileName := fmt.Sprintf(
+		"%s-%06d-%s.eml",
+		now.UTC().Format("20060102T150405.000000000"),
+		atomic.AddUint64(&mailbox.sequence, 1),
+		strings.NewReplacer("@", "_at_", "/", "_", `\`, "_").Replace(email.To),
+	)
+
+	message := buildMessage(mailbox.from, email, now)
+
+	if err := ioutil.WriteFile(filepath.Join(mailbox.directory, fileName), message, 0600); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return nil
+}
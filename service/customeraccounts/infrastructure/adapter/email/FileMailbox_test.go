@@ -0,0 +1,52 @@
+package email_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/email"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileMailbox(t *testing.T) {
+	Convey("Given a FileMailbox with a directory which does not exist yet", t, func() {
+		tempDir, err := ioutil.TempDir("", "mailbox")
+		So(err, ShouldBeNil)
+
+		directory := filepath.Join(tempDir, "emails")
+		mailbox := email.NewFileMailbox(directory, "no-reply@example.com")
+
+		Convey("When two emails are sent", func() {
+			someEmail := application.Email{
+				To:      "fiona@gallagher.net",
+				Subject: "Bitte bestätige deine E-Mail-Adresse",
+				Body:    "Hallo Fiona,\nbitte bestätige ...\n",
+			}
+
+			err = mailbox.Send(context.Background(), someEmail)
+			So(err, ShouldBeNil)
+			err = mailbox.Send(context.Background(), someEmail)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should write each one into a separate .eml file", func() {
+				files, err := filepath.Glob(filepath.Join(directory, "*_at_gallagher.net.eml"))
+				So(err, ShouldBeNil)
+				So(files, ShouldHaveLength, 2)
+
+				content, err := ioutil.ReadFile(files[0])
+				So(err, ShouldBeNil)
+				So(string(content), ShouldStartWith, "From: no-reply@example.com\r\nTo: fiona@gallagher.net\r\n")
+				So(string(content), ShouldContainSubstring, "Subject: =?utf-8?q?Bitte_best=C3=A4tige_deine_E-Mail-Adresse?=\r\n")
+				So(string(content), ShouldEndWith, "\r\n\r\nHallo Fiona,\r\nbitte bestätige ...\r\n")
+			})
+		})
+
+		Reset(func() {
+			So(os.RemoveAll(tempDir), ShouldBeNil)
+		})
+	})
+}
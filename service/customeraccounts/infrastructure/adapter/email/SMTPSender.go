@@ -0,0 +1,109 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+)
+
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPSender delivers emails to an SMTP server. It uses STARTTLS if the server supports it,
+// and it only authenticates if a username is configured.
+type SMTPSender struct {
+	hostAndPort string
+	username    string
+	password    string
+	from        string
+}
+
+func NewSMTPSender(hostAndPort string, username string, password string, from string) *SMTPSender {
+	return &SMTPSender{
+		hostAndPort: hostAndPort,
+		username:    username,
+		password:    password,
+		from:        from,
+	}
+}
+
+func (sender *SMTPSender) Send(ctx context.Context, email application.Email) error {
+	wrapWithMsg := "smtpSender.Send"
+
+	if err := sender.send(ctx, email); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return nil
+}
+
+func (sender *SMTPSender) send(ctx context.Context, email application.Email) error {
+	host, _, err := net.SplitHostPort(sender.hostAndPort)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{}
+
+	conn, err := dialer.DialContext(ctx, "tcp", sender.hostAndPort)
+	if err != nil {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultSMTPTimeout)
+	}
+
+	if err = conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if sender.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", sender.username, sender.password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(sender.from); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(email.To); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(buildMessage(sender.from, email, time.Now())); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,113 @@
+package email_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/email"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSMTPSender(t *testing.T) {
+	Convey("Given an SMTPSender for a running SMTP server", t, func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+
+		received := make(chan []string, 1)
+		go serveOneSMTPSession(listener, received)
+
+		sender := email.NewSMTPSender(listener.Addr().String(), "", "", "no-reply@example.com")
+
+		Convey("When an email is sent", func() {
+			err = sender.Send(
+				context.Background(),
+				application.Email{To: "fiona@gallagher.net", Subject: "Welcome", Body: "Hello Fiona"},
+			)
+
+			Convey("Then the server should receive it", func() {
+				So(err, ShouldBeNil)
+
+				commands := <-received
+				So(commands, ShouldContain, "MAIL FROM:<no-reply@example.com> BODY=8BITMIME")
+				So(commands, ShouldContain, "RCPT TO:<fiona@gallagher.net>")
+				So(commands, ShouldContain, "Subject: Welcome")
+				So(commands, ShouldContain, "Hello Fiona")
+			})
+		})
+
+		Reset(func() {
+			_ = listener.Close()
+		})
+	})
+
+	Convey("Given an SMTPSender for an SMTP server which is not running", t, func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		hostAndPort := listener.Addr().String()
+		So(listener.Close(), ShouldBeNil)
+
+		sender := email.NewSMTPSender(hostAndPort, "", "", "no-reply@example.com")
+
+		Convey("When an email is sent", func() {
+			err = sender.Send(context.Background(), application.Email{To: "fiona@gallagher.net"})
+
+			Convey("Then it should fail", func() {
+				So(errors.Is(err, shared.ErrTechnical), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+// serveOneSMTPSession speaks just enough SMTP for a single message and reports all received lines.
+func serveOneSMTPSession(listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	var lines []string
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	inData := false
+
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		switch {
+		case inData && line == ".":
+			inData = false
+			reply("250 OK")
+		case inData:
+		case strings.HasPrefix(line, "EHLO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(line, "DATA"):
+			inData = true
+			reply("354 go ahead")
+		case strings.HasPrefix(line, "QUIT"):
+			reply("221 bye")
+			received <- lines
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+
+	received <- lines
+}
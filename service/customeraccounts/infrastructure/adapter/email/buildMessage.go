@@ -0,0 +1,29 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+)
+
+// buildMessage renders an RFC 5322 message. The subject is always MIME encoded,
+// which also keeps line breaks in rendered names from injecting headers.
+func buildMessage(from string, email application.Email, date time.Time) []byte {
+	message := &bytes.Buffer{}
+
+	_, _ = fmt.Fprintf(message, "From: %s\r\n", from)
+	_, _ = fmt.Fprintf(message, "To: %s\r\n", email.To)
+	_, _ = fmt.Fprintf(message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	_, _ = fmt.Fprintf(message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	_, _ = fmt.Fprint(message, "MIME-Version: 1.0\r\n")
+	_, _ = fmt.Fprint(message, "Content-Type: text/plain; charset=utf-8\r\n")
+	_, _ = fmt.Fprint(message, "Content-Transfer-Encoding: 8bit\r\n")
+	_, _ = fmt.Fprint(message, "\r\n")
+	_, _ = fmt.Fprint(message, strings.ReplaceAll(strings.ReplaceAll(email.Body, "\r\n", "\n"), "\n", "\r\n"))
+
+	return message.Bytes()
+}
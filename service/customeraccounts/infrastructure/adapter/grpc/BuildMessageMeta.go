@@ -0,0 +1,50 @@
+package customergrpc
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	MetadataKeyRequestID     = "x-request-id"
+	MetadataKeyCorrelationID = "x-correlation-id"
+	MetadataKeyCausationID   = "x-causation-id"
+	MetadataKeyActor         = "x-actor"
+
+	MetadataKeyIdempotencyKey = "idempotency-key"
+)
+
+// BuildMessageMeta reads the tracing metadata of an incoming request.
+// The causation ID falls back to the request ID or a generated one, so that it is never empty,
+// and the correlation ID falls back to the causation ID, which means that the request starts a new correlation.
+// The idempotency key is optional, commands with the same key are only executed once.
+func BuildMessageMeta(ctx context.Context) es.MessageMeta {
+	md, _ := metadata.FromIncomingContext(ctx) // a missing metadata is fine, we'll use fallbacks
+
+	causationID := firstMetadataValue(md, MetadataKeyCausationID, MetadataKeyRequestID)
+	if causationID == "" {
+		causationID = uuid.New().String()
+	}
+
+	correlationID := firstMetadataValue(md, MetadataKeyCorrelationID)
+	if correlationID == "" {
+		correlationID = causationID
+	}
+
+	messageMeta := es.BuildMessageMeta(correlationID, causationID, firstMetadataValue(md, MetadataKeyActor))
+
+	return messageMeta.WithIdempotencyKey(firstMetadataValue(md, MetadataKeyIdempotencyKey))
+}
+
+func firstMetadataValue(md metadata.MD, keys ...string) string {
+	for _, key := range keys {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,67 @@
+package customergrpc_test
+
+import (
+	"context"
+	"testing"
+
+	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestBuildMessageMeta(t *testing.T) {
+	Convey("Given a request with correlation ID, causation ID, actor and idempotency key", t, func() {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs(
+				customergrpc.MetadataKeyCorrelationID, "some-correlation-id",
+				customergrpc.MetadataKeyCausationID, "some-causation-id",
+				customergrpc.MetadataKeyRequestID, "some-request-id",
+				customergrpc.MetadataKeyActor, "some-actor",
+				customergrpc.MetadataKeyIdempotencyKey, "some-idempotency-key",
+			),
+		)
+
+		Convey("When MessageMeta is built", func() {
+			messageMeta := customergrpc.BuildMessageMeta(ctx)
+
+			Convey("Then it should contain them", func() {
+				So(messageMeta.CorrelationID(), ShouldEqual, "some-correlation-id")
+				So(messageMeta.CausationID(), ShouldEqual, "some-causation-id")
+				So(messageMeta.Actor(), ShouldEqual, "some-actor")
+				So(messageMeta.IdempotencyKey(), ShouldEqual, "some-idempotency-key")
+			})
+		})
+	})
+
+	Convey("Given a request with only a request ID", t, func() {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs(customergrpc.MetadataKeyRequestID, "some-request-id"),
+		)
+
+		Convey("When MessageMeta is built", func() {
+			messageMeta := customergrpc.BuildMessageMeta(ctx)
+
+			Convey("Then the request ID should be the causation ID and the correlation ID", func() {
+				So(messageMeta.CausationID(), ShouldEqual, "some-request-id")
+				So(messageMeta.CorrelationID(), ShouldEqual, "some-request-id")
+				So(messageMeta.Actor(), ShouldBeEmpty)
+				So(messageMeta.IdempotencyKey(), ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given a request without metadata", t, func() {
+		ctx := context.Background()
+
+		Convey("When MessageMeta is built", func() {
+			messageMeta := customergrpc.BuildMessageMeta(ctx)
+
+			Convey("Then a causation ID should be generated and be used as the correlation ID", func() {
+				So(messageMeta.CausationID(), ShouldNotBeEmpty)
+				So(messageMeta.CorrelationID(), ShouldEqual, messageMeta.CausationID())
+			})
+		})
+	})
+}
@@ -4,44 +4,63 @@ import (
 	"context"
 
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
 	"github.com/golang/protobuf/ptypes/empty"
 )
 
 type customerServer struct {
 	register            hexagon.ForRegisteringCustomers
 	confirmEmailAddress hexagon.ForConfirmingCustomerEmailAddresses
+	regenerateConfirm   hexagon.ForRegeneratingCustomerEmailConfirmations
 	changeEmailAddress  hexagon.ForChangingCustomerEmailAddresses
 	changeName          hexagon.ForChangingCustomerNames
 	delete              hexagon.ForDeletingCustomers
+	forget              hexagon.ForForgettingCustomers
 	retrieveView        hexagon.ForRetrievingCustomerViews
+	retrieveViewAt      hexagon.ForRetrievingCustomerViewsAt
+	retrieveViewByEmail hexagon.ForRetrievingCustomerViewsByEmailAddress
+	retrieveHistory     hexagon.ForRetrievingCustomerHistories
+	listCustomers       hexagon.ForListingCustomers
 }
 
 func NewCustomerServer(
 	register hexagon.ForRegisteringCustomers,
 	confirmEmailAddress hexagon.ForConfirmingCustomerEmailAddresses,
+	regenerateConfirm hexagon.ForRegeneratingCustomerEmailConfirmations,
 	changeEmailAddress hexagon.ForChangingCustomerEmailAddresses,
 	changeName hexagon.ForChangingCustomerNames,
 	delete hexagon.ForDeletingCustomers,
+	forget hexagon.ForForgettingCustomers,
 	retrieveView hexagon.ForRetrievingCustomerViews,
+	retrieveViewAt hexagon.ForRetrievingCustomerViewsAt,
+	retrieveViewByEmail hexagon.ForRetrievingCustomerViewsByEmailAddress,
+	retrieveHistory hexagon.ForRetrievingCustomerHistories,
+	listCustomers hexagon.ForListingCustomers,
 ) *customerServer {
 	server := &customerServer{
 		register:            register,
 		confirmEmailAddress: confirmEmailAddress,
+		regenerateConfirm:   regenerateConfirm,
 		changeEmailAddress:  changeEmailAddress,
 		changeName:          changeName,
 		delete:              delete,
+		forget:              forget,
 		retrieveView:        retrieveView,
+		retrieveViewAt:      retrieveViewAt,
+		retrieveViewByEmail: retrieveViewByEmail,
+		retrieveHistory:     retrieveHistory,
+		listCustomers:       listCustomers,
 	}
 
 	return server
 }
 
 func (server *customerServer) Register(
-	_ context.Context,
+	ctx context.Context,
 	req *RegisterRequest,
 ) (*RegisterResponse, error) {
 
-	customerID, err := server.register(req.EmailAddress, req.GivenName, req.FamilyName)
+	customerID, err := server.register(ctx, req.EmailAddress, req.GivenName, req.FamilyName, BuildMessageMeta(ctx))
 	if err != nil {
 		return nil, MapToGRPCErrors(err)
 	}
@@ -50,11 +69,34 @@ func (server *customerServer) Register(
 }
 
 func (server *customerServer) ConfirmEmailAddress(
-	_ context.Context,
+	ctx context.Context,
 	req *ConfirmEmailAddressRequest,
 ) (*empty.Empty, error) {
 
-	if err := server.confirmEmailAddress(req.Id, req.ConfirmationHash); err != nil {
+	expectedVersion, err := ExpectedVersion(ctx, req.ExpectedVersion)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	err = server.confirmEmailAddress(ctx, req.Id, req.ConfirmationHash, expectedVersion, BuildMessageMeta(ctx))
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (server *customerServer) RegenerateEmailConfirmation(
+	ctx context.Context,
+	req *RegenerateEmailConfirmationRequest,
+) (*empty.Empty, error) {
+
+	expectedVersion, err := ExpectedVersion(ctx, req.ExpectedVersion)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	if err = server.regenerateConfirm(ctx, req.Id, expectedVersion, BuildMessageMeta(ctx)); err != nil {
 		return nil, MapToGRPCErrors(err)
 	}
 
@@ -62,11 +104,17 @@ func (server *customerServer) ConfirmEmailAddress(
 }
 
 func (server *customerServer) ChangeEmailAddress(
-	_ context.Context,
+	ctx context.Context,
 	req *ChangeEmailAddressRequest,
 ) (*empty.Empty, error) {
 
-	if err := server.changeEmailAddress(req.Id, req.EmailAddress); err != nil {
+	expectedVersion, err := ExpectedVersion(ctx, req.ExpectedVersion)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	err = server.changeEmailAddress(ctx, req.Id, req.EmailAddress, expectedVersion, BuildMessageMeta(ctx))
+	if err != nil {
 		return nil, MapToGRPCErrors(err)
 	}
 
@@ -74,11 +122,17 @@ func (server *customerServer) ChangeEmailAddress(
 }
 
 func (server *customerServer) ChangeName(
-	_ context.Context,
+	ctx context.Context,
 	req *ChangeNameRequest,
 ) (*empty.Empty, error) {
 
-	if err := server.changeName(req.Id, req.GivenName, req.FamilyName); err != nil {
+	expectedVersion, err := ExpectedVersion(ctx, req.ExpectedVersion)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	err = server.changeName(ctx, req.Id, req.GivenName, req.FamilyName, expectedVersion, BuildMessageMeta(ctx))
+	if err != nil {
 		return nil, MapToGRPCErrors(err)
 	}
 
@@ -86,11 +140,28 @@ func (server *customerServer) ChangeName(
 }
 
 func (server *customerServer) Delete(
-	_ context.Context,
+	ctx context.Context,
 	req *DeleteRequest,
 ) (*empty.Empty, error) {
 
-	if err := server.delete(req.Id); err != nil {
+	expectedVersion, err := ExpectedVersion(ctx, req.ExpectedVersion)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	if err = server.delete(ctx, req.Id, expectedVersion, BuildMessageMeta(ctx)); err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (server *customerServer) Forget(
+	ctx context.Context,
+	req *ForgetRequest,
+) (*empty.Empty, error) {
+
+	if err := server.forget(ctx, req.Id, BuildMessageMeta(ctx)); err != nil {
 		return nil, MapToGRPCErrors(err)
 	}
 
@@ -98,16 +169,121 @@ func (server *customerServer) Delete(
 }
 
 func (server *customerServer) RetrieveView(
-	_ context.Context,
+	ctx context.Context,
 	req *RetrieveViewRequest,
 ) (*RetrieveViewResponse, error) {
 
-	view, err := server.retrieveView(req.Id)
+	view, err := server.retrieveView(ctx, req.Id)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	SendETag(ctx, view.Version)
+
+	return buildRetrieveViewResponse(view), nil
+}
+
+func (server *customerServer) RetrieveViewAt(
+	ctx context.Context,
+	req *RetrieveViewAtRequest,
+) (*RetrieveViewResponse, error) {
+
+	view, err := server.retrieveViewAt(ctx, req.Id, uint(req.Version), req.OccurredAt)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	SendETag(ctx, view.Version)
+
+	return buildRetrieveViewResponse(view), nil
+}
+
+func (server *customerServer) RetrieveViewByEmailAddress(
+	ctx context.Context,
+	req *RetrieveViewByEmailAddressRequest,
+) (*RetrieveViewResponse, error) {
+
+	view, err := server.retrieveViewByEmail(ctx, req.EmailAddress)
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	SendETag(ctx, view.Version)
+
+	return buildRetrieveViewResponse(view), nil
+}
+
+func (server *customerServer) RetrieveHistory(
+	ctx context.Context,
+	req *RetrieveHistoryRequest,
+) (*RetrieveHistoryResponse, error) {
+
+	history, err := server.retrieveHistory(ctx, req.Id, uint(req.FromVersion), uint(req.PageSize))
 	if err != nil {
 		return nil, MapToGRPCErrors(err)
 	}
 
+	response := &RetrieveHistoryResponse{NextFromVersion: uint64(history.NextFromVersion)}
+
+	for _, entry := range history.Events {
+		response.Events = append(
+			response.Events,
+			&HistoryEvent{
+				EventName:  entry.EventName,
+				OccurredAt: entry.OccurredAt,
+				Version:    uint64(entry.Version),
+				Payload:    entry.Payload,
+			},
+		)
+	}
+
+	return response, nil
+}
+
+func (server *customerServer) ListCustomers(
+	ctx context.Context,
+	req *ListCustomersRequest,
+) (*ListCustomersResponse, error) {
+
+	page, err := server.listCustomers(
+		ctx,
+		uint(req.PageSize),
+		req.PageToken,
+		req.OrderBy,
+		req.ConfirmationStatus,
+		req.DeletionStatus,
+		req.RegisteredFrom,
+		req.RegisteredTo,
+	)
+
+	if err != nil {
+		return nil, MapToGRPCErrors(err)
+	}
+
+	response := &ListCustomersResponse{NextPageToken: page.NextPageToken}
+
+	for _, view := range page.Customers {
+		response.Customers = append(
+			response.Customers,
+			&CustomerListEntry{
+				Id:                      view.ID,
+				EmailAddress:            view.EmailAddress,
+				IsEmailAddressConfirmed: view.IsEmailAddressConfirmed,
+				GivenName:               view.GivenName,
+				FamilyName:              view.FamilyName,
+				IsDeleted:               view.IsDeleted,
+				RegisteredAt:            view.RegisteredAt,
+				Version:                 uint64(view.Version),
+			},
+		)
+	}
+
+	return response, nil
+}
+
+func buildRetrieveViewResponse(view customer.View) *RetrieveViewResponse {
 	response := &RetrieveViewResponse{
+		Id:                      view.ID,
 		EmailAddress:            view.EmailAddress,
 		IsEmailAddressConfirmed: view.IsEmailAddressConfirmed,
 		GivenName:               view.GivenName,
@@ -115,5 +291,5 @@ func (server *customerServer) RetrieveView(
 		Version:                 uint64(view.Version),
 	}
 
-	return response, nil
+	return response
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
 	"github.com/golang/protobuf/ptypes/empty"
 	. "github.com/smartystreets/goconvey/convey"
@@ -25,6 +26,21 @@ var mockedView = customer.View{
 	IsDeleted:               false,
 	Version:                 2,
 }
+var mockedHistory = customer.History{
+	Events: []customer.HistoryEntry{
+		{
+			EventName:  "CustomerRegistered",
+			OccurredAt: "2020-06-01T12:00:00Z",
+			Version:    1,
+			Payload:    map[string]string{"emailAddress": mockedView.EmailAddress},
+		},
+	},
+	NextFromVersion: 2,
+}
+var mockedListPage = customer.ListPage{
+	Customers:     []customer.ListView{{View: mockedView, RegisteredAt: "2020-06-01T12:00:00Z"}},
+	NextPageToken: "next",
+}
 var expectedErrCode = codes.InvalidArgument
 var expectedErrMsg = "invalid input"
 
@@ -89,6 +105,30 @@ func TestGRPCServer(t *testing.T) {
 			})
 		})
 
+		Convey("\nUsecase: RegenerateEmailConfirmation", func() {
+			Convey("Given the application will return success", func() {
+				Convey("When the request is handled", func() {
+					res, err := successCustomerServer.RegenerateEmailConfirmation(
+						context.Background(),
+						&customergrpc.RegenerateEmailConfirmationRequest{},
+					)
+
+					thenItShouldSuccees(res, err)
+				})
+			})
+
+			Convey("Given the application will return an error", func() {
+				Convey("When the request is handled", func() {
+					res, err := failureCustomerServer.RegenerateEmailConfirmation(
+						context.Background(),
+						&customergrpc.RegenerateEmailConfirmationRequest{},
+					)
+
+					thenItShouldFailWithTheExpectedError(res, err)
+				})
+			})
+		})
+
 		Convey("\nUsecase: ChangeEmailAddress", func() {
 			Convey("Given the application will return success", func() {
 				Convey("When the request is handled", func() {
@@ -161,6 +201,30 @@ func TestGRPCServer(t *testing.T) {
 			})
 		})
 
+		Convey("\nUsecase: Forget", func() {
+			Convey("Given the application will return success", func() {
+				Convey("When the request is handled", func() {
+					res, err := successCustomerServer.Forget(
+						context.Background(),
+						&customergrpc.ForgetRequest{},
+					)
+
+					thenItShouldSuccees(res, err)
+				})
+			})
+
+			Convey("Given the application will return an error", func() {
+				Convey("When the request is handled", func() {
+					res, err := failureCustomerServer.Forget(
+						context.Background(),
+						&customergrpc.ForgetRequest{},
+					)
+
+					thenItShouldFailWithTheExpectedError(res, err)
+				})
+			})
+		})
+
 		Convey("\nUsecase: RetrieveView", func() {
 			Convey("Given the application will return success", func() {
 				Convey("When the request is handled", func() {
@@ -174,6 +238,7 @@ func TestGRPCServer(t *testing.T) {
 						So(res, ShouldNotBeNil)
 
 						expectedRes := &customergrpc.RetrieveViewResponse{
+							Id:                      mockedView.ID,
 							EmailAddress:            mockedView.EmailAddress,
 							IsEmailAddressConfirmed: mockedView.IsEmailAddressConfirmed,
 							GivenName:               mockedView.GivenName,
@@ -201,6 +266,184 @@ func TestGRPCServer(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("\nUsecase: RetrieveViewAt", func() {
+			Convey("Given the application will return success", func() {
+				Convey("When the request is handled", func() {
+					res, err := successCustomerServer.RetrieveViewAt(
+						context.Background(),
+						&customergrpc.RetrieveViewAtRequest{},
+					)
+
+					Convey("Then it should succeed", func() {
+						So(err, ShouldBeNil)
+						So(res, ShouldNotBeNil)
+
+						expectedRes := &customergrpc.RetrieveViewResponse{
+							Id:                      mockedView.ID,
+							EmailAddress:            mockedView.EmailAddress,
+							IsEmailAddressConfirmed: mockedView.IsEmailAddressConfirmed,
+							GivenName:               mockedView.GivenName,
+							FamilyName:              mockedView.FamilyName,
+							Version:                 uint64(mockedView.Version),
+						}
+
+						So(res, ShouldResemble, expectedRes)
+					})
+				})
+			})
+
+			Convey("Given the application will return an error", func() {
+				Convey("When the request is handled", func() {
+					res, err := failureCustomerServer.RetrieveViewAt(
+						context.Background(),
+						&customergrpc.RetrieveViewAtRequest{},
+					)
+
+					Convey("Then it should fail with the exptected error", func() {
+						So(err, ShouldBeError)
+						So(err, ShouldResemble, status.Error(expectedErrCode, expectedErrMsg))
+						So(res, ShouldBeNil)
+					})
+				})
+			})
+		})
+
+		Convey("\nUsecase: RetrieveViewByEmailAddress", func() {
+			Convey("Given the application will return success", func() {
+				Convey("When the request is handled", func() {
+					res, err := successCustomerServer.RetrieveViewByEmailAddress(
+						context.Background(),
+						&customergrpc.RetrieveViewByEmailAddressRequest{},
+					)
+
+					Convey("Then it should succeed", func() {
+						So(err, ShouldBeNil)
+						So(res, ShouldNotBeNil)
+
+						expectedRes := &customergrpc.RetrieveViewResponse{
+							Id:                      mockedView.ID,
+							EmailAddress:            mockedView.EmailAddress,
+							IsEmailAddressConfirmed: mockedView.IsEmailAddressConfirmed,
+							GivenName:               mockedView.GivenName,
+							FamilyName:              mockedView.FamilyName,
+							Version:                 uint64(mockedView.Version),
+						}
+
+						So(res, ShouldResemble, expectedRes)
+					})
+				})
+			})
+
+			Convey("Given the application will return an error", func() {
+				Convey("When the request is handled", func() {
+					res, err := failureCustomerServer.RetrieveViewByEmailAddress(
+						context.Background(),
+						&customergrpc.RetrieveViewByEmailAddressRequest{},
+					)
+
+					Convey("Then it should fail with the exptected error", func() {
+						So(err, ShouldBeError)
+						So(err, ShouldResemble, status.Error(expectedErrCode, expectedErrMsg))
+						So(res, ShouldBeNil)
+					})
+				})
+			})
+		})
+
+		Convey("\nUsecase: RetrieveHistory", func() {
+			Convey("Given the application will return success", func() {
+				Convey("When the request is handled", func() {
+					res, err := successCustomerServer.RetrieveHistory(
+						context.Background(),
+						&customergrpc.RetrieveHistoryRequest{},
+					)
+
+					Convey("Then it should succeed", func() {
+						So(err, ShouldBeNil)
+						So(res, ShouldNotBeNil)
+
+						expectedRes := &customergrpc.RetrieveHistoryResponse{
+							Events: []*customergrpc.HistoryEvent{
+								{
+									EventName:  mockedHistory.Events[0].EventName,
+									OccurredAt: mockedHistory.Events[0].OccurredAt,
+									Version:    uint64(mockedHistory.Events[0].Version),
+									Payload:    mockedHistory.Events[0].Payload,
+								},
+							},
+							NextFromVersion: uint64(mockedHistory.NextFromVersion),
+						}
+
+						So(res, ShouldResemble, expectedRes)
+					})
+				})
+			})
+
+			Convey("Given the application will return an error", func() {
+				Convey("When the request is handled", func() {
+					res, err := failureCustomerServer.RetrieveHistory(
+						context.Background(),
+						&customergrpc.RetrieveHistoryRequest{},
+					)
+
+					Convey("Then it should fail with the exptected error", func() {
+						So(err, ShouldBeError)
+						So(err, ShouldResemble, status.Error(expectedErrCode, expectedErrMsg))
+						So(res, ShouldBeNil)
+					})
+				})
+			})
+		})
+
+		Convey("\nUsecase: ListCustomers", func() {
+			Convey("Given the application will return success", func() {
+				Convey("When the request is handled", func() {
+					res, err := successCustomerServer.ListCustomers(
+						context.Background(),
+						&customergrpc.ListCustomersRequest{},
+					)
+
+					Convey("Then it should succeed", func() {
+						So(err, ShouldBeNil)
+						So(res, ShouldNotBeNil)
+
+						expectedRes := &customergrpc.ListCustomersResponse{
+							Customers: []*customergrpc.CustomerListEntry{
+								{
+									Id:                      mockedView.ID,
+									EmailAddress:            mockedView.EmailAddress,
+									IsEmailAddressConfirmed: mockedView.IsEmailAddressConfirmed,
+									GivenName:               mockedView.GivenName,
+									FamilyName:              mockedView.FamilyName,
+									IsDeleted:               mockedView.IsDeleted,
+									RegisteredAt:            mockedListPage.Customers[0].RegisteredAt,
+									Version:                 uint64(mockedView.Version),
+								},
+							},
+							NextPageToken: mockedListPage.NextPageToken,
+						}
+
+						So(res, ShouldResemble, expectedRes)
+					})
+				})
+			})
+
+			Convey("Given the application will return an error", func() {
+				Convey("When the request is handled", func() {
+					res, err := failureCustomerServer.ListCustomers(
+						context.Background(),
+						&customergrpc.ListCustomersRequest{},
+					)
+
+					Convey("Then it should fail with the exptected error", func() {
+						So(err, ShouldBeError)
+						So(err, ShouldResemble, status.Error(expectedErrCode, expectedErrMsg))
+						So(res, ShouldBeNil)
+					})
+				})
+			})
+		})
 	})
 }
 
@@ -221,24 +464,46 @@ func thenItShouldFailWithTheExpectedError(res *empty.Empty, err error) {
 
 func buildSuccessCustomerServer() customergrpc.CustomerServer {
 	customerGRPCServer := customergrpc.NewCustomerServer(
-		func(emailAddress, givenName, familyName string) (value.CustomerID, error) {
+		func(ctx context.Context, emailAddress, givenName, familyName string, messageMeta es.MessageMeta) (value.CustomerID, error) {
 			return mockedID, nil
 		},
-		func(customerID, confirmationHash string) error {
+		func(ctx context.Context, customerID, confirmationHash string, expectedVersion uint, messageMeta es.MessageMeta) error {
+			return nil
+		},
+		func(ctx context.Context, customerID string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID, emailAddress string) error {
+		func(ctx context.Context, customerID, emailAddress string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID, givenName, familyName string) error {
+		func(ctx context.Context, customerID, givenName, familyName string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID string) error {
+		func(ctx context.Context, customerID string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return nil
 		},
-		func(customerID string) (customer.View, error) {
+		func(ctx context.Context, customerID string, messageMeta es.MessageMeta) error {
+			return nil
+		},
+		func(ctx context.Context, customerID string) (customer.View, error) {
+			return mockedView, nil
+		},
+		func(ctx context.Context, customerID string, asOfVersion uint, asOfTime string) (customer.View, error) {
+			return mockedView, nil
+		},
+		func(ctx context.Context, emailAddress string) (customer.View, error) {
 			return mockedView, nil
 		},
+		func(ctx context.Context, customerID string, fromVersion uint, pageSize uint) (customer.History, error) {
+			return mockedHistory, nil
+		},
+		func(
+			ctx context.Context,
+			pageSize uint,
+			pageToken, orderBy, confirmationStatus, deletionStatus, registeredFrom, registeredTo string,
+		) (customer.ListPage, error) {
+			return mockedListPage, nil
+		},
 	)
 
 	return customerGRPCServer
@@ -249,24 +514,46 @@ func buildFailureCustomerServer() customergrpc.CustomerServer {
 	mockedErr := errors.Mark(errors.New(expectedErrMsg), shared.ErrInputIsInvalid)
 
 	customerGRPCServer := customergrpc.NewCustomerServer(
-		func(emailAddress, givenName, familyName string) (value.CustomerID, error) {
+		func(ctx context.Context, emailAddress, givenName, familyName string, messageMeta es.MessageMeta) (value.CustomerID, error) {
 			return mockedID, mockedErr
 		},
-		func(customerID, confirmationHash string) error {
+		func(ctx context.Context, customerID, confirmationHash string, expectedVersion uint, messageMeta es.MessageMeta) error {
+			return mockedErr
+		},
+		func(ctx context.Context, customerID string, expectedVersion uint, messageMeta es.MessageMeta) error {
+			return mockedErr
+		},
+		func(ctx context.Context, customerID, emailAddress string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return mockedErr
 		},
-		func(customerID, emailAddress string) error {
+		func(ctx context.Context, customerID, givenName, familyName string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return mockedErr
 		},
-		func(customerID, givenName, familyName string) error {
+		func(ctx context.Context, customerID string, expectedVersion uint, messageMeta es.MessageMeta) error {
 			return mockedErr
 		},
-		func(customerID string) error {
+		func(ctx context.Context, customerID string, messageMeta es.MessageMeta) error {
 			return mockedErr
 		},
-		func(customerID string) (customer.View, error) {
+		func(ctx context.Context, customerID string) (customer.View, error) {
 			return mockedView, mockedErr
 		},
+		func(ctx context.Context, customerID string, asOfVersion uint, asOfTime string) (customer.View, error) {
+			return mockedView, mockedErr
+		},
+		func(ctx context.Context, emailAddress string) (customer.View, error) {
+			return mockedView, mockedErr
+		},
+		func(ctx context.Context, customerID string, fromVersion uint, pageSize uint) (customer.History, error) {
+			return customer.History{}, mockedErr
+		},
+		func(
+			ctx context.Context,
+			pageSize uint,
+			pageToken, orderBy, confirmationStatus, deletionStatus, registeredFrom, registeredTo string,
+		) (customer.ListPage, error) {
+			return customer.ListPage{}, mockedErr
+		},
 	)
 
 	return customerGRPCServer
@@ -0,0 +1,50 @@
+package customergrpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	MetadataKeyIfMatch = "if-match"
+	MetadataKeyETag    = "etag"
+)
+
+// ExpectedVersion prefers the expectedVersion of the request and falls back to the if-match metadata,
+// which contains an ETag like "5" (REST clients send it as If-Match header). Both are optional, 0 means any version.
+func ExpectedVersion(ctx context.Context, expectedVersion uint64) (uint, error) {
+	if expectedVersion > 0 {
+		return uint(expectedVersion), nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx) // a missing metadata is fine, then there is no If-Match
+
+	ifMatch := firstMetadataValue(md, MetadataKeyIfMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil || version == 0 {
+		err = errors.Newf("if-match must be an ETag of a Customer version, got [%s]", ifMatch)
+		return 0, errors.Mark(err, shared.ErrInputIsInvalid)
+	}
+
+	return uint(version), nil
+}
+
+// SendETag sends the version of a Customer as etag metadata, which the REST gateway forwards as ETag header.
+func SendETag(ctx context.Context, version uint) {
+	// it's not an error for the client if the header can't be sent, e.g. in unit tests without a transport stream
+	_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataKeyETag, BuildETag(version)))
+}
+
+func BuildETag(version uint) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
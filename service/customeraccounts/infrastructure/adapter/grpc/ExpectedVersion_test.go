@@ -0,0 +1,94 @@
+package customergrpc_test
+
+import (
+	"context"
+	"testing"
+
+	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	Convey("Given a request with an expected version and an if-match ETag", t, func() {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs(customergrpc.MetadataKeyIfMatch, customergrpc.BuildETag(3)),
+		)
+
+		Convey("When the expected version is read", func() {
+			expectedVersion, err := customergrpc.ExpectedVersion(ctx, 5)
+
+			Convey("Then it should be the one of the request", func() {
+				So(err, ShouldBeNil)
+				So(expectedVersion, ShouldEqual, 5)
+			})
+		})
+	})
+
+	Convey("Given a request with only an if-match ETag", t, func() {
+		for ifMatch, version := range map[string]uint{`"3"`: 3, `W/"4"`: 4, "*": 0} {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(customergrpc.MetadataKeyIfMatch, ifMatch))
+
+			Convey("When the expected version is read from "+ifMatch, func() {
+				expectedVersion, err := customergrpc.ExpectedVersion(ctx, 0)
+
+				Convey("Then it should be the one of the ETag", func() {
+					So(err, ShouldBeNil)
+					So(expectedVersion, ShouldEqual, version)
+				})
+			})
+		}
+	})
+
+	Convey("Given a request with an if-match value which is not an ETag of a version", t, func() {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs(customergrpc.MetadataKeyIfMatch, `"abc"`),
+		)
+
+		Convey("When the expected version is read", func() {
+			_, err := customergrpc.ExpectedVersion(ctx, 0)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldBeError)
+				So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a request without expected version", t, func() {
+		Convey("When the expected version is read", func() {
+			expectedVersion, err := customergrpc.ExpectedVersion(context.Background(), 0)
+
+			Convey("Then it should be 0", func() {
+				So(err, ShouldBeNil)
+				So(expectedVersion, ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestMapToGRPCErrors_WithVersionMismatch(t *testing.T) {
+	Convey("Given a version mismatch error", t, func() {
+		appErr := errors.Mark(errors.New("expected version 1 but the Customer is at version 2"), shared.ErrVersionMismatch)
+
+		Convey("When it is mapped to a gRPC error", func() {
+			st := status.Convert(customergrpc.MapToGRPCErrors(appErr))
+
+			Convey("Then it should be Aborted with a version PreconditionFailure", func() {
+				So(st.Code(), ShouldEqual, codes.Aborted)
+				So(st.Details(), ShouldHaveLength, 1)
+
+				preconditionFailure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+				So(ok, ShouldBeTrue)
+				So(preconditionFailure.GetViolations()[0].GetType(), ShouldEqual, customergrpc.PreconditionViolationTypeVersion)
+			})
+		})
+	})
+}
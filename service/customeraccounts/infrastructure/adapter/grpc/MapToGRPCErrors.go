@@ -1,12 +1,17 @@
 package customergrpc
 
 import (
+	"context"
+
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/cockroachdb/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const PreconditionViolationTypeVersion = "VERSION"
+
 func MapToGRPCErrors(appErr error) error {
 	var code codes.Code
 
@@ -20,11 +25,20 @@ func MapToGRPCErrors(appErr error) error {
 
 	case errors.Is(appErr, shared.ErrDomainConstraintsViolation):
 		code = codes.FailedPrecondition
+	case errors.Is(appErr, shared.ErrTooManyAttempts):
+		code = codes.ResourceExhausted
 
 	case errors.Is(appErr, shared.ErrMaxRetriesExceeded):
 		code = codes.Aborted
 	case errors.Is(appErr, shared.ErrConcurrencyConflict):
 		code = codes.Aborted
+	case errors.Is(appErr, shared.ErrVersionMismatch):
+		return versionMismatchError(appErr)
+
+	case errors.Is(appErr, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(appErr, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
 
 	default:
 		code = codes.Internal
@@ -32,3 +46,26 @@ func MapToGRPCErrors(appErr error) error {
 
 	return status.Errorf(code, "%s", errors.Cause(appErr))
 }
+
+// versionMismatchError has a PreconditionFailure detail, so that the REST gateway can respond with 412.
+func versionMismatchError(appErr error) error {
+	st := status.New(codes.Aborted, errors.Cause(appErr).Error())
+
+	stWithDetails, err := st.WithDetails(
+		&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        PreconditionViolationTypeVersion,
+					Subject:     "customer",
+					Description: errors.Cause(appErr).Error(),
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return st.Err()
+	}
+
+	return stWithDetails.Err()
+}
@@ -0,0 +1,64 @@
+package customergrpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMapToGRPCErrors_WithContextErrors(t *testing.T) {
+	Convey("Given errors caused by a cancelled request or an exceeded deadline", t, func() {
+		cancelled := shared.MarkAndWrapError(context.Canceled, shared.ErrTechnical, "eventStore.Load")
+		deadlineExceeded := shared.MarkAndWrapError(context.DeadlineExceeded, shared.ErrTechnical, "eventStore.Load")
+
+		Convey("When they are mapped to gRPC errors", func() {
+			cancelledCode := status.Code(customergrpc.MapToGRPCErrors(cancelled))
+			deadlineExceededCode := status.Code(customergrpc.MapToGRPCErrors(deadlineExceeded))
+
+			Convey("Then they should be Canceled and DeadlineExceeded instead of Internal", func() {
+				So(cancelledCode, ShouldEqual, codes.Canceled)
+				So(deadlineExceededCode, ShouldEqual, codes.DeadlineExceeded)
+			})
+		})
+	})
+}
+
+func TestMapToGRPCErrors_WithExpiredConfirmationHash(t *testing.T) {
+	Convey("Given an error caused by an expired confirmation hash", t, func() {
+		expired := errors.Wrap(domain.ErrConfirmationHashExpired, "customerCommandHandler.ConfirmCustomerEmailAddress")
+		expired = errors.Mark(expired, shared.ErrDomainConstraintsViolation)
+
+		Convey("When it is mapped to a gRPC error", func() {
+			grpcErr := customergrpc.MapToGRPCErrors(expired)
+
+			Convey("Then it should be FailedPrecondition with the dedicated reason", func() {
+				So(status.Code(grpcErr), ShouldEqual, codes.FailedPrecondition)
+				So(status.Convert(grpcErr).Message(), ShouldEqual, domain.ErrConfirmationHashExpired.Error())
+			})
+		})
+	})
+}
+
+func TestMapToGRPCErrors_WithLockedOutConfirmation(t *testing.T) {
+	Convey("Given an error caused by too many failed confirmation attempts", t, func() {
+		lockedOut := errors.Mark(customer.ErrConfirmationLockedOut, shared.ErrTooManyAttempts)
+		lockedOut = errors.Wrap(lockedOut, "customerCommandHandler.ConfirmCustomerEmailAddress")
+
+		Convey("When it is mapped to a gRPC error", func() {
+			grpcErr := customergrpc.MapToGRPCErrors(lockedOut)
+
+			Convey("Then it should be ResourceExhausted with the dedicated reason", func() {
+				So(status.Code(grpcErr), ShouldEqual, codes.ResourceExhausted)
+				So(status.Convert(grpcErr).Message(), ShouldEqual, customer.ErrConfirmationLockedOut.Error())
+			})
+		})
+	})
+}
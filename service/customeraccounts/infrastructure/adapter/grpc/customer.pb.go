@@ -123,6 +123,7 @@ func (m *RegisterResponse) GetId() string {
 type ConfirmEmailAddressRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	ConfirmationHash     string   `protobuf:"bytes,2,opt,name=confirmationHash,proto3" json:"confirmationHash,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -167,9 +168,64 @@ func (m *ConfirmEmailAddressRequest) GetConfirmationHash() string {
 	return ""
 }
 
+func (m *ConfirmEmailAddressRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+type RegenerateEmailConfirmationRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegenerateEmailConfirmationRequest) Reset()         { *m = RegenerateEmailConfirmationRequest{} }
+func (m *RegenerateEmailConfirmationRequest) String() string { return proto.CompactTextString(m) }
+func (*RegenerateEmailConfirmationRequest) ProtoMessage()    {}
+func (*RegenerateEmailConfirmationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{3}
+}
+
+func (m *RegenerateEmailConfirmationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegenerateEmailConfirmationRequest.Unmarshal(m, b)
+}
+func (m *RegenerateEmailConfirmationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegenerateEmailConfirmationRequest.Marshal(b, m, deterministic)
+}
+func (m *RegenerateEmailConfirmationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegenerateEmailConfirmationRequest.Merge(m, src)
+}
+func (m *RegenerateEmailConfirmationRequest) XXX_Size() int {
+	return xxx_messageInfo_RegenerateEmailConfirmationRequest.Size(m)
+}
+func (m *RegenerateEmailConfirmationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegenerateEmailConfirmationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegenerateEmailConfirmationRequest proto.InternalMessageInfo
+
+func (m *RegenerateEmailConfirmationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RegenerateEmailConfirmationRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
 type ChangeEmailAddressRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	EmailAddress         string   `protobuf:"bytes,2,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -179,7 +235,7 @@ func (m *ChangeEmailAddressRequest) Reset()         { *m = ChangeEmailAddressReq
 func (m *ChangeEmailAddressRequest) String() string { return proto.CompactTextString(m) }
 func (*ChangeEmailAddressRequest) ProtoMessage()    {}
 func (*ChangeEmailAddressRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9efa92dae3d6ec46, []int{3}
+	return fileDescriptor_9efa92dae3d6ec46, []int{4}
 }
 
 func (m *ChangeEmailAddressRequest) XXX_Unmarshal(b []byte) error {
@@ -214,10 +270,18 @@ func (m *ChangeEmailAddressRequest) GetEmailAddress() string {
 	return ""
 }
 
+func (m *ChangeEmailAddressRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
 type ChangeNameRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	GivenName            string   `protobuf:"bytes,2,opt,name=givenName,proto3" json:"givenName,omitempty"`
 	FamilyName           string   `protobuf:"bytes,3,opt,name=familyName,proto3" json:"familyName,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -227,7 +291,7 @@ func (m *ChangeNameRequest) Reset()         { *m = ChangeNameRequest{} }
 func (m *ChangeNameRequest) String() string { return proto.CompactTextString(m) }
 func (*ChangeNameRequest) ProtoMessage()    {}
 func (*ChangeNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9efa92dae3d6ec46, []int{4}
+	return fileDescriptor_9efa92dae3d6ec46, []int{5}
 }
 
 func (m *ChangeNameRequest) XXX_Unmarshal(b []byte) error {
@@ -269,8 +333,16 @@ func (m *ChangeNameRequest) GetFamilyName() string {
 	return ""
 }
 
+func (m *ChangeNameRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
 type DeleteRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion      uint64   `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -280,7 +352,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9efa92dae3d6ec46, []int{5}
+	return fileDescriptor_9efa92dae3d6ec46, []int{6}
 }
 
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
@@ -308,6 +380,52 @@ func (m *DeleteRequest) GetId() string {
 	return ""
 }
 
+func (m *DeleteRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
+}
+
+type ForgetRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ForgetRequest) Reset()         { *m = ForgetRequest{} }
+func (m *ForgetRequest) String() string { return proto.CompactTextString(m) }
+func (*ForgetRequest) ProtoMessage()    {}
+func (*ForgetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{7}
+}
+
+func (m *ForgetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ForgetRequest.Unmarshal(m, b)
+}
+func (m *ForgetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ForgetRequest.Marshal(b, m, deterministic)
+}
+func (m *ForgetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ForgetRequest.Merge(m, src)
+}
+func (m *ForgetRequest) XXX_Size() int {
+	return xxx_messageInfo_ForgetRequest.Size(m)
+}
+func (m *ForgetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ForgetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ForgetRequest proto.InternalMessageInfo
+
+func (m *ForgetRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
 type RetrieveViewRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -319,7 +437,7 @@ func (m *RetrieveViewRequest) Reset()         { *m = RetrieveViewRequest{} }
 func (m *RetrieveViewRequest) String() string { return proto.CompactTextString(m) }
 func (*RetrieveViewRequest) ProtoMessage()    {}
 func (*RetrieveViewRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9efa92dae3d6ec46, []int{6}
+	return fileDescriptor_9efa92dae3d6ec46, []int{8}
 }
 
 func (m *RetrieveViewRequest) XXX_Unmarshal(b []byte) error {
@@ -347,12 +465,107 @@ func (m *RetrieveViewRequest) GetId() string {
 	return ""
 }
 
+type RetrieveViewAtRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version              uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	OccurredAt           string   `protobuf:"bytes,3,opt,name=occurredAt,proto3" json:"occurredAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetrieveViewAtRequest) Reset()         { *m = RetrieveViewAtRequest{} }
+func (m *RetrieveViewAtRequest) String() string { return proto.CompactTextString(m) }
+func (*RetrieveViewAtRequest) ProtoMessage()    {}
+func (*RetrieveViewAtRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{9}
+}
+
+func (m *RetrieveViewAtRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetrieveViewAtRequest.Unmarshal(m, b)
+}
+func (m *RetrieveViewAtRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetrieveViewAtRequest.Marshal(b, m, deterministic)
+}
+func (m *RetrieveViewAtRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetrieveViewAtRequest.Merge(m, src)
+}
+func (m *RetrieveViewAtRequest) XXX_Size() int {
+	return xxx_messageInfo_RetrieveViewAtRequest.Size(m)
+}
+func (m *RetrieveViewAtRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetrieveViewAtRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetrieveViewAtRequest proto.InternalMessageInfo
+
+func (m *RetrieveViewAtRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RetrieveViewAtRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *RetrieveViewAtRequest) GetOccurredAt() string {
+	if m != nil {
+		return m.OccurredAt
+	}
+	return ""
+}
+
+type RetrieveViewByEmailAddressRequest struct {
+	EmailAddress         string   `protobuf:"bytes,1,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetrieveViewByEmailAddressRequest) Reset()         { *m = RetrieveViewByEmailAddressRequest{} }
+func (m *RetrieveViewByEmailAddressRequest) String() string { return proto.CompactTextString(m) }
+func (*RetrieveViewByEmailAddressRequest) ProtoMessage()    {}
+func (*RetrieveViewByEmailAddressRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{10}
+}
+
+func (m *RetrieveViewByEmailAddressRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetrieveViewByEmailAddressRequest.Unmarshal(m, b)
+}
+func (m *RetrieveViewByEmailAddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetrieveViewByEmailAddressRequest.Marshal(b, m, deterministic)
+}
+func (m *RetrieveViewByEmailAddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetrieveViewByEmailAddressRequest.Merge(m, src)
+}
+func (m *RetrieveViewByEmailAddressRequest) XXX_Size() int {
+	return xxx_messageInfo_RetrieveViewByEmailAddressRequest.Size(m)
+}
+func (m *RetrieveViewByEmailAddressRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetrieveViewByEmailAddressRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetrieveViewByEmailAddressRequest proto.InternalMessageInfo
+
+func (m *RetrieveViewByEmailAddressRequest) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
 type RetrieveViewResponse struct {
 	EmailAddress            string   `protobuf:"bytes,1,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
 	IsEmailAddressConfirmed bool     `protobuf:"varint,2,opt,name=isEmailAddressConfirmed,proto3" json:"isEmailAddressConfirmed,omitempty"`
 	GivenName               string   `protobuf:"bytes,3,opt,name=givenName,proto3" json:"givenName,omitempty"`
 	FamilyName              string   `protobuf:"bytes,4,opt,name=familyName,proto3" json:"familyName,omitempty"`
 	Version                 uint64   `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	Id                      string   `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
 	XXX_unrecognized        []byte   `json:"-"`
 	XXX_sizecache           int32    `json:"-"`
@@ -362,7 +575,7 @@ func (m *RetrieveViewResponse) Reset()         { *m = RetrieveViewResponse{} }
 func (m *RetrieveViewResponse) String() string { return proto.CompactTextString(m) }
 func (*RetrieveViewResponse) ProtoMessage()    {}
 func (*RetrieveViewResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9efa92dae3d6ec46, []int{7}
+	return fileDescriptor_9efa92dae3d6ec46, []int{11}
 }
 
 func (m *RetrieveViewResponse) XXX_Unmarshal(b []byte) error {
@@ -418,64 +631,516 @@ func (m *RetrieveViewResponse) GetVersion() uint64 {
 	return 0
 }
 
+func (m *RetrieveViewResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type RetrieveHistoryRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FromVersion          uint64   `protobuf:"varint,2,opt,name=fromVersion,proto3" json:"fromVersion,omitempty"`
+	PageSize             uint32   `protobuf:"varint,3,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetrieveHistoryRequest) Reset()         { *m = RetrieveHistoryRequest{} }
+func (m *RetrieveHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*RetrieveHistoryRequest) ProtoMessage()    {}
+func (*RetrieveHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{12}
+}
+
+func (m *RetrieveHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetrieveHistoryRequest.Unmarshal(m, b)
+}
+func (m *RetrieveHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetrieveHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *RetrieveHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetrieveHistoryRequest.Merge(m, src)
+}
+func (m *RetrieveHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_RetrieveHistoryRequest.Size(m)
+}
+func (m *RetrieveHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetrieveHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetrieveHistoryRequest proto.InternalMessageInfo
+
+func (m *RetrieveHistoryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RetrieveHistoryRequest) GetFromVersion() uint64 {
+	if m != nil {
+		return m.FromVersion
+	}
+	return 0
+}
+
+func (m *RetrieveHistoryRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+type RetrieveHistoryResponse struct {
+	Events               []*HistoryEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextFromVersion      uint64          `protobuf:"varint,2,opt,name=nextFromVersion,proto3" json:"nextFromVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RetrieveHistoryResponse) Reset()         { *m = RetrieveHistoryResponse{} }
+func (m *RetrieveHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*RetrieveHistoryResponse) ProtoMessage()    {}
+func (*RetrieveHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{13}
+}
+
+func (m *RetrieveHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetrieveHistoryResponse.Unmarshal(m, b)
+}
+func (m *RetrieveHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetrieveHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *RetrieveHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetrieveHistoryResponse.Merge(m, src)
+}
+func (m *RetrieveHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_RetrieveHistoryResponse.Size(m)
+}
+func (m *RetrieveHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetrieveHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetrieveHistoryResponse proto.InternalMessageInfo
+
+func (m *RetrieveHistoryResponse) GetEvents() []*HistoryEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *RetrieveHistoryResponse) GetNextFromVersion() uint64 {
+	if m != nil {
+		return m.NextFromVersion
+	}
+	return 0
+}
+
+type HistoryEvent struct {
+	EventName            string            `protobuf:"bytes,1,opt,name=eventName,proto3" json:"eventName,omitempty"`
+	OccurredAt           string            `protobuf:"bytes,2,opt,name=occurredAt,proto3" json:"occurredAt,omitempty"`
+	Version              uint64            `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Payload              map[string]string `protobuf:"bytes,4,rep,name=payload,proto3" json:"payload,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *HistoryEvent) Reset()         { *m = HistoryEvent{} }
+func (m *HistoryEvent) String() string { return proto.CompactTextString(m) }
+func (*HistoryEvent) ProtoMessage()    {}
+func (*HistoryEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{14}
+}
+
+func (m *HistoryEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryEvent.Unmarshal(m, b)
+}
+func (m *HistoryEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryEvent.Marshal(b, m, deterministic)
+}
+func (m *HistoryEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryEvent.Merge(m, src)
+}
+func (m *HistoryEvent) XXX_Size() int {
+	return xxx_messageInfo_HistoryEvent.Size(m)
+}
+func (m *HistoryEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryEvent proto.InternalMessageInfo
+
+func (m *HistoryEvent) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
+func (m *HistoryEvent) GetOccurredAt() string {
+	if m != nil {
+		return m.OccurredAt
+	}
+	return ""
+}
+
+func (m *HistoryEvent) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *HistoryEvent) GetPayload() map[string]string {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type ListCustomersRequest struct {
+	PageSize             uint32   `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken            string   `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	OrderBy              string   `protobuf:"bytes,3,opt,name=orderBy,proto3" json:"orderBy,omitempty"`
+	ConfirmationStatus   string   `protobuf:"bytes,4,opt,name=confirmationStatus,proto3" json:"confirmationStatus,omitempty"`
+	DeletionStatus       string   `protobuf:"bytes,5,opt,name=deletionStatus,proto3" json:"deletionStatus,omitempty"`
+	RegisteredFrom       string   `protobuf:"bytes,6,opt,name=registeredFrom,proto3" json:"registeredFrom,omitempty"`
+	RegisteredTo         string   `protobuf:"bytes,7,opt,name=registeredTo,proto3" json:"registeredTo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListCustomersRequest) Reset()         { *m = ListCustomersRequest{} }
+func (m *ListCustomersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListCustomersRequest) ProtoMessage()    {}
+func (*ListCustomersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{15}
+}
+
+func (m *ListCustomersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCustomersRequest.Unmarshal(m, b)
+}
+func (m *ListCustomersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCustomersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListCustomersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCustomersRequest.Merge(m, src)
+}
+func (m *ListCustomersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListCustomersRequest.Size(m)
+}
+func (m *ListCustomersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCustomersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCustomersRequest proto.InternalMessageInfo
+
+func (m *ListCustomersRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListCustomersRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListCustomersRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
+func (m *ListCustomersRequest) GetConfirmationStatus() string {
+	if m != nil {
+		return m.ConfirmationStatus
+	}
+	return ""
+}
+
+func (m *ListCustomersRequest) GetDeletionStatus() string {
+	if m != nil {
+		return m.DeletionStatus
+	}
+	return ""
+}
+
+func (m *ListCustomersRequest) GetRegisteredFrom() string {
+	if m != nil {
+		return m.RegisteredFrom
+	}
+	return ""
+}
+
+func (m *ListCustomersRequest) GetRegisteredTo() string {
+	if m != nil {
+		return m.RegisteredTo
+	}
+	return ""
+}
+
+type ListCustomersResponse struct {
+	Customers            []*CustomerListEntry `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`
+	NextPageToken        string               `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ListCustomersResponse) Reset()         { *m = ListCustomersResponse{} }
+func (m *ListCustomersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListCustomersResponse) ProtoMessage()    {}
+func (*ListCustomersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{16}
+}
+
+func (m *ListCustomersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCustomersResponse.Unmarshal(m, b)
+}
+func (m *ListCustomersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCustomersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListCustomersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCustomersResponse.Merge(m, src)
+}
+func (m *ListCustomersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListCustomersResponse.Size(m)
+}
+func (m *ListCustomersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCustomersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCustomersResponse proto.InternalMessageInfo
+
+func (m *ListCustomersResponse) GetCustomers() []*CustomerListEntry {
+	if m != nil {
+		return m.Customers
+	}
+	return nil
+}
+
+func (m *ListCustomersResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type CustomerListEntry struct {
+	Id                      string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EmailAddress            string   `protobuf:"bytes,2,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	IsEmailAddressConfirmed bool     `protobuf:"varint,3,opt,name=isEmailAddressConfirmed,proto3" json:"isEmailAddressConfirmed,omitempty"`
+	GivenName               string   `protobuf:"bytes,4,opt,name=givenName,proto3" json:"givenName,omitempty"`
+	FamilyName              string   `protobuf:"bytes,5,opt,name=familyName,proto3" json:"familyName,omitempty"`
+	IsDeleted               bool     `protobuf:"varint,6,opt,name=isDeleted,proto3" json:"isDeleted,omitempty"`
+	RegisteredAt            string   `protobuf:"bytes,7,opt,name=registeredAt,proto3" json:"registeredAt,omitempty"`
+	Version                 uint64   `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
+}
+
+func (m *CustomerListEntry) Reset()         { *m = CustomerListEntry{} }
+func (m *CustomerListEntry) String() string { return proto.CompactTextString(m) }
+func (*CustomerListEntry) ProtoMessage()    {}
+func (*CustomerListEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{17}
+}
+
+func (m *CustomerListEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerListEntry.Unmarshal(m, b)
+}
+func (m *CustomerListEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerListEntry.Marshal(b, m, deterministic)
+}
+func (m *CustomerListEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerListEntry.Merge(m, src)
+}
+func (m *CustomerListEntry) XXX_Size() int {
+	return xxx_messageInfo_CustomerListEntry.Size(m)
+}
+func (m *CustomerListEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerListEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerListEntry proto.InternalMessageInfo
+
+func (m *CustomerListEntry) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CustomerListEntry) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+func (m *CustomerListEntry) GetIsEmailAddressConfirmed() bool {
+	if m != nil {
+		return m.IsEmailAddressConfirmed
+	}
+	return false
+}
+
+func (m *CustomerListEntry) GetGivenName() string {
+	if m != nil {
+		return m.GivenName
+	}
+	return ""
+}
+
+func (m *CustomerListEntry) GetFamilyName() string {
+	if m != nil {
+		return m.FamilyName
+	}
+	return ""
+}
+
+func (m *CustomerListEntry) GetIsDeleted() bool {
+	if m != nil {
+		return m.IsDeleted
+	}
+	return false
+}
+
+func (m *CustomerListEntry) GetRegisteredAt() string {
+	if m != nil {
+		return m.RegisteredAt
+	}
+	return ""
+}
+
+func (m *CustomerListEntry) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*RegisterRequest)(nil), "customergrpc.RegisterRequest")
 	proto.RegisterType((*RegisterResponse)(nil), "customergrpc.RegisterResponse")
 	proto.RegisterType((*ConfirmEmailAddressRequest)(nil), "customergrpc.ConfirmEmailAddressRequest")
+	proto.RegisterType((*RegenerateEmailConfirmationRequest)(nil), "customergrpc.RegenerateEmailConfirmationRequest")
 	proto.RegisterType((*ChangeEmailAddressRequest)(nil), "customergrpc.ChangeEmailAddressRequest")
 	proto.RegisterType((*ChangeNameRequest)(nil), "customergrpc.ChangeNameRequest")
 	proto.RegisterType((*DeleteRequest)(nil), "customergrpc.DeleteRequest")
+	proto.RegisterType((*ForgetRequest)(nil), "customergrpc.ForgetRequest")
 	proto.RegisterType((*RetrieveViewRequest)(nil), "customergrpc.RetrieveViewRequest")
+	proto.RegisterType((*RetrieveViewAtRequest)(nil), "customergrpc.RetrieveViewAtRequest")
+	proto.RegisterType((*RetrieveViewByEmailAddressRequest)(nil), "customergrpc.RetrieveViewByEmailAddressRequest")
 	proto.RegisterType((*RetrieveViewResponse)(nil), "customergrpc.RetrieveViewResponse")
+	proto.RegisterType((*RetrieveHistoryRequest)(nil), "customergrpc.RetrieveHistoryRequest")
+	proto.RegisterType((*RetrieveHistoryResponse)(nil), "customergrpc.RetrieveHistoryResponse")
+	proto.RegisterType((*HistoryEvent)(nil), "customergrpc.HistoryEvent")
+	proto.RegisterMapType((map[string]string)(nil), "customergrpc.HistoryEvent.PayloadEntry")
+	proto.RegisterType((*ListCustomersRequest)(nil), "customergrpc.ListCustomersRequest")
+	proto.RegisterType((*ListCustomersResponse)(nil), "customergrpc.ListCustomersResponse")
+	proto.RegisterType((*CustomerListEntry)(nil), "customergrpc.CustomerListEntry")
 }
 
-func init() { proto.RegisterFile("customer.proto", fileDescriptor_9efa92dae3d6ec46) }
+func init() {
+	proto.RegisterFile("customer.proto", fileDescriptor_9efa92dae3d6ec46)
+}
 
 var fileDescriptor_9efa92dae3d6ec46 = []byte{
-	// 532 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x95, 0xdd, 0x0f, 0xd2, 0x51, 0x28, 0xcd, 0x06, 0xb5, 0xa9, 0x53, 0xd2, 0x76, 0x11, 0x10,
-	0x82, 0x64, 0xab, 0x70, 0x41, 0xdc, 0x50, 0xa8, 0xc4, 0x09, 0x24, 0x1f, 0x50, 0xaf, 0x9b, 0x78,
-	0xe2, 0xac, 0x14, 0x7b, 0x8d, 0xd7, 0x09, 0xaa, 0x10, 0x12, 0xe2, 0xc8, 0x95, 0xbf, 0xc2, 0xcf,
-	0xe0, 0xc6, 0x5f, 0xe0, 0x87, 0x20, 0xaf, 0x6d, 0xc5, 0x1f, 0xdd, 0xa8, 0x52, 0x8f, 0x3b, 0xf3,
-	0x34, 0xef, 0xcd, 0xec, 0x7b, 0xb0, 0x3f, 0x5d, 0xca, 0x44, 0x04, 0x18, 0xdb, 0x51, 0x2c, 0x12,
-	0x41, 0xda, 0xc5, 0xdb, 0x8f, 0xa3, 0xa9, 0xd5, 0xf7, 0x85, 0xf0, 0x17, 0xe8, 0xa8, 0xde, 0x64,
-	0x39, 0x73, 0x30, 0x88, 0x92, 0xeb, 0x0c, 0x6a, 0x9d, 0xe4, 0x4d, 0x16, 0x71, 0x87, 0x85, 0xa1,
-	0x48, 0x58, 0xc2, 0x45, 0x28, 0xb3, 0x2e, 0x95, 0xf0, 0xc0, 0x45, 0x9f, 0xcb, 0x04, 0x63, 0x17,
-	0x3f, 0x2f, 0x51, 0x26, 0x84, 0x42, 0x1b, 0x03, 0xc6, 0x17, 0x6f, 0x3d, 0x2f, 0x46, 0x29, 0x7b,
-	0xc6, 0x99, 0x31, 0xdc, 0x73, 0x2b, 0x35, 0x72, 0x02, 0x7b, 0x3e, 0x5f, 0x61, 0xf8, 0x81, 0x05,
-	0xd8, 0x33, 0x15, 0x60, 0x5d, 0x20, 0x03, 0x80, 0x19, 0x0b, 0xf8, 0xe2, 0x5a, 0xb5, 0xb7, 0x54,
-	0xbb, 0x54, 0xa1, 0x14, 0x0e, 0xd6, 0xa4, 0x32, 0x12, 0xa1, 0x44, 0xb2, 0x0f, 0x26, 0xf7, 0x72,
-	0x2e, 0x93, 0x7b, 0xf4, 0x0a, 0xac, 0xb1, 0x08, 0x67, 0x3c, 0x0e, 0x2e, 0x4b, 0xc4, 0x85, 0xc6,
-	0x1a, 0x9a, 0x8c, 0xe0, 0x60, 0x9a, 0xa1, 0xd5, 0x76, 0xef, 0x99, 0x9c, 0xe7, 0xb2, 0x1a, 0x75,
-	0xfa, 0x11, 0x8e, 0xc7, 0x73, 0x16, 0xfa, 0x78, 0x9b, 0xc1, 0xf5, 0x63, 0x98, 0xcd, 0x63, 0x50,
-	0x06, 0x9d, 0x6c, 0x60, 0xba, 0x9c, 0x6e, 0xd0, 0xdd, 0x2e, 0x76, 0x0a, 0xf7, 0xdf, 0xe1, 0x02,
-	0x13, 0xdd, 0x78, 0xfa, 0x04, 0xba, 0x2e, 0x26, 0x31, 0xc7, 0x15, 0x7e, 0xe2, 0xf8, 0x45, 0x07,
-	0xfb, 0x63, 0xc0, 0xc3, 0x2a, 0x2e, 0x3f, 0xff, 0x6d, 0x3e, 0xfd, 0x35, 0x1c, 0x71, 0x59, 0x3e,
-	0x5a, 0xfe, 0x41, 0xe8, 0xa9, 0x85, 0x5a, 0xae, 0xae, 0x5d, 0x5d, 0x7e, 0x6b, 0xf3, 0xf2, 0xdb,
-	0xf5, 0xe5, 0x49, 0x0f, 0xee, 0xad, 0x30, 0x96, 0x5c, 0x84, 0xbd, 0x9d, 0x33, 0x63, 0xb8, 0xed,
-	0x16, 0xcf, 0x97, 0xbf, 0x77, 0xa0, 0x35, 0xce, 0x93, 0x40, 0x26, 0xd0, 0x2a, 0x5c, 0x45, 0x1e,
-	0xd9, 0xe5, 0x80, 0xd8, 0x35, 0x8b, 0x5b, 0x03, 0x5d, 0x3b, 0xbb, 0x06, 0x3d, 0xfa, 0xf1, 0xf7,
-	0xdf, 0x2f, 0xb3, 0x43, 0xdb, 0xce, 0xea, 0xc2, 0x29, 0xa0, 0x6f, 0x8c, 0x11, 0xf9, 0x69, 0x40,
-	0xf7, 0x06, 0x5b, 0x92, 0x61, 0x75, 0xa0, 0xde, 0xb9, 0xd6, 0xa1, 0x9d, 0xe5, 0xd1, 0x2e, 0xc2,
-	0x6a, 0x5f, 0xa6, 0x61, 0xa5, 0x17, 0x8a, 0xf2, 0x85, 0xf5, 0xb4, 0x4c, 0xe9, 0x7c, 0xe5, 0xde,
-	0x37, 0x47, 0x7d, 0x02, 0xcb, 0xc6, 0x38, 0xb9, 0x9d, 0x53, 0x31, 0xdf, 0x0d, 0x20, 0x4d, 0x27,
-	0x93, 0x67, 0x35, 0x2d, 0x3a, 0xaf, 0x6b, 0xa5, 0x3c, 0x57, 0x52, 0x1e, 0x5b, 0x83, 0xcd, 0x52,
-	0x52, 0x09, 0x73, 0x80, 0xb5, 0xf5, 0xc9, 0xe9, 0x4d, 0xcc, 0xa5, 0x50, 0x68, 0x19, 0xcf, 0x15,
-	0x63, 0xdf, 0x3a, 0x6c, 0x32, 0x86, 0x2c, 0xc0, 0x94, 0xe9, 0x0a, 0x76, 0xb3, 0x04, 0x90, 0x7e,
-	0x95, 0xa5, 0x92, 0x0b, 0x2d, 0xc3, 0xb1, 0x62, 0xe8, 0x8e, 0x3a, 0x0d, 0x06, 0x12, 0x41, 0xbb,
-	0x1c, 0x09, 0x72, 0x5e, 0x37, 0x47, 0x23, 0x56, 0x16, 0xdd, 0x04, 0xc9, 0x3d, 0x94, 0x33, 0x92,
-	0x26, 0xe3, 0x64, 0x57, 0x89, 0x7b, 0xf5, 0x3f, 0x00, 0x00, 0xff, 0xff, 0x01, 0xcd, 0xf1, 0x14,
-	0xd6, 0x05, 0x00, 0x00,
+	// 1137 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x4b, 0x6f, 0x1b, 0x55,
+	0x14, 0xd6, 0xd8, 0x89, 0xe3, 0x9c, 0xd8, 0x79, 0xdc, 0xbc, 0x9c, 0x49, 0xc8, 0xe3, 0xa6, 0x0d,
+	0x21, 0x55, 0xed, 0x36, 0x48, 0xa8, 0x8a, 0xc4, 0xc2, 0x0d, 0x29, 0x45, 0x42, 0xa8, 0x9a, 0x56,
+	0x15, 0x2b, 0xd0, 0xc4, 0x73, 0xec, 0x8c, 0x6a, 0xcf, 0x98, 0x99, 0x6b, 0xa7, 0xe6, 0x21, 0x41,
+	0x97, 0x20, 0xb1, 0x61, 0x85, 0x58, 0xf0, 0x5b, 0xf8, 0x0d, 0x6c, 0x59, 0xc2, 0xff, 0x40, 0xf7,
+	0x31, 0xf1, 0xdc, 0x79, 0xb8, 0x6e, 0xbb, 0x9b, 0x39, 0xf7, 0xcc, 0xf9, 0xce, 0xe3, 0xbb, 0x67,
+	0x3e, 0x58, 0x6c, 0x0d, 0x42, 0xe6, 0xf7, 0x30, 0xa8, 0xf7, 0x03, 0x9f, 0xf9, 0xa4, 0x12, 0xbd,
+	0x77, 0x82, 0x7e, 0xcb, 0xdc, 0xee, 0xf8, 0x7e, 0xa7, 0x8b, 0x0d, 0x71, 0x76, 0x39, 0x68, 0x37,
+	0xb0, 0xd7, 0x67, 0x23, 0xe9, 0x6a, 0xee, 0xa8, 0x43, 0xbb, 0xef, 0x36, 0x6c, 0xcf, 0xf3, 0x99,
+	0xcd, 0x5c, 0xdf, 0x0b, 0xe5, 0x29, 0x0d, 0x61, 0xc9, 0xc2, 0x8e, 0x1b, 0x32, 0x0c, 0x2c, 0xfc,
+	0x66, 0x80, 0x21, 0x23, 0x14, 0x2a, 0xd8, 0xb3, 0xdd, 0x6e, 0xd3, 0x71, 0x02, 0x0c, 0xc3, 0x9a,
+	0xb1, 0x6f, 0x1c, 0xcf, 0x5b, 0x9a, 0x8d, 0xec, 0xc0, 0x7c, 0xc7, 0x1d, 0xa2, 0xf7, 0x85, 0xdd,
+	0xc3, 0x5a, 0x41, 0x38, 0x8c, 0x0d, 0x64, 0x17, 0xa0, 0x6d, 0xf7, 0xdc, 0xee, 0x48, 0x1c, 0x17,
+	0xc5, 0x71, 0xcc, 0x42, 0x29, 0x2c, 0x8f, 0x41, 0xc3, 0xbe, 0xef, 0x85, 0x48, 0x16, 0xa1, 0xe0,
+	0x3a, 0x0a, 0xab, 0xe0, 0x3a, 0xf4, 0x95, 0x01, 0xe6, 0xb9, 0xef, 0xb5, 0xdd, 0xa0, 0x77, 0x11,
+	0x43, 0x8e, 0x92, 0x4c, 0xb8, 0x93, 0x13, 0x58, 0x6e, 0x49, 0x6f, 0x51, 0xde, 0x63, 0x3b, 0xbc,
+	0x52, 0x79, 0xa5, 0xec, 0xe4, 0x18, 0x96, 0xf0, 0x65, 0x1f, 0x5b, 0x0c, 0x9d, 0xe7, 0x18, 0x84,
+	0xae, 0xef, 0x89, 0x1c, 0x67, 0xac, 0xa4, 0x99, 0x7e, 0x05, 0xd4, 0xc2, 0x0e, 0x7a, 0x18, 0xd8,
+	0x0c, 0x45, 0x1a, 0xe7, 0xb1, 0x60, 0x79, 0xb9, 0x64, 0xc4, 0x2f, 0x64, 0xc7, 0x1f, 0xc1, 0xd6,
+	0xf9, 0x95, 0xed, 0x75, 0x70, 0x9a, 0x12, 0x93, 0x73, 0x29, 0x64, 0xcc, 0x65, 0xfa, 0xd2, 0x7e,
+	0x31, 0x60, 0x45, 0x62, 0xf3, 0x91, 0xe4, 0x61, 0xbe, 0xd3, 0x9c, 0xb3, 0xb2, 0x99, 0xc9, 0xce,
+	0xe6, 0x33, 0xa8, 0x7e, 0x82, 0x5d, 0x64, 0xf8, 0xee, 0x3d, 0xdd, 0x83, 0xea, 0x23, 0x3f, 0xe8,
+	0x20, 0xcb, 0x09, 0x45, 0x6f, 0xc3, 0xaa, 0x85, 0x2c, 0x70, 0x71, 0x88, 0xcf, 0x5d, 0xbc, 0xce,
+	0x73, 0xb3, 0x61, 0x3d, 0xee, 0xd6, 0xcc, 0x8b, 0x47, 0x6a, 0x30, 0x37, 0xd4, 0x52, 0x8a, 0x5e,
+	0x79, 0x7f, 0xfc, 0x56, 0x6b, 0x10, 0x04, 0xe8, 0x34, 0x59, 0xd4, 0x9f, 0xb1, 0x85, 0x7e, 0x0a,
+	0x07, 0x71, 0x88, 0x87, 0xa3, 0x2c, 0x1a, 0x4c, 0x71, 0x1d, 0xe9, 0x3f, 0x06, 0xac, 0xe9, 0x35,
+	0xa9, 0x5b, 0x35, 0xcd, 0x5d, 0x7e, 0x00, 0x9b, 0x6e, 0x18, 0x47, 0x56, 0x1c, 0x47, 0x47, 0xd4,
+	0x53, 0xb6, 0xf2, 0x8e, 0x75, 0x76, 0x14, 0x27, 0xb3, 0x63, 0x26, 0xc5, 0x8e, 0x58, 0xdf, 0x66,
+	0xf5, 0xbe, 0xc9, 0x0e, 0x97, 0x6e, 0x46, 0xd1, 0x86, 0x8d, 0xa8, 0xba, 0xc7, 0x6e, 0xc8, 0xfc,
+	0x60, 0x94, 0x37, 0x8b, 0x7d, 0x58, 0x68, 0x07, 0x7e, 0x4f, 0xa7, 0x48, 0xdc, 0x44, 0x4c, 0x28,
+	0xf7, 0xed, 0x0e, 0x3e, 0x75, 0xbf, 0x95, 0x29, 0x57, 0xad, 0x9b, 0x77, 0x7a, 0x0d, 0x9b, 0x29,
+	0x1c, 0xd5, 0xc8, 0x53, 0x28, 0xe1, 0x10, 0x3d, 0xc6, 0x5b, 0x58, 0x3c, 0x5e, 0x38, 0x35, 0xeb,
+	0xf1, 0x0d, 0x5c, 0x57, 0xee, 0x17, 0xdc, 0xc5, 0x52, 0x9e, 0x9c, 0xb3, 0x1e, 0xbe, 0x64, 0x8f,
+	0x52, 0x09, 0x25, 0xcd, 0xf4, 0x3f, 0x03, 0x2a, 0xf1, 0x10, 0xbc, 0xb3, 0x22, 0x88, 0x68, 0x9d,
+	0x2c, 0x6f, 0x6c, 0x48, 0xf0, 0xaa, 0x90, 0xe4, 0x55, 0xbc, 0xb3, 0x45, 0xbd, 0xb3, 0x4d, 0x98,
+	0xeb, 0xdb, 0xa3, 0xae, 0x6f, 0x3b, 0xb5, 0x19, 0x51, 0xc7, 0xfb, 0xf9, 0x75, 0xd4, 0x9f, 0x48,
+	0xcf, 0x0b, 0x8f, 0x05, 0x23, 0x2b, 0xfa, 0xce, 0x3c, 0x83, 0x4a, 0xfc, 0x80, 0x2c, 0x43, 0xf1,
+	0x05, 0x8e, 0x54, 0x92, 0xfc, 0x91, 0xac, 0xc1, 0xec, 0xd0, 0xee, 0x0e, 0xa2, 0x85, 0x21, 0x5f,
+	0xce, 0x0a, 0x0f, 0x0c, 0xfa, 0x6b, 0x01, 0xd6, 0x3e, 0x77, 0x43, 0x76, 0xae, 0x30, 0x6f, 0x48,
+	0x1e, 0x9f, 0x8a, 0xa1, 0x4f, 0x85, 0xf7, 0x82, 0x3f, 0x3f, 0xf3, 0x5f, 0xa0, 0x17, 0xed, 0xa0,
+	0x1b, 0x03, 0xaf, 0xd5, 0x0f, 0x1c, 0x0c, 0x1e, 0x8e, 0x14, 0x03, 0xa3, 0x57, 0x52, 0x07, 0x12,
+	0x5f, 0xfd, 0x4f, 0x99, 0xcd, 0x06, 0xa1, 0xe2, 0x61, 0xc6, 0x09, 0x39, 0x82, 0x45, 0x07, 0xbb,
+	0x18, 0xf3, 0x9d, 0x15, 0xbe, 0x09, 0x2b, 0xf7, 0x0b, 0xd4, 0xdf, 0x0b, 0x1d, 0x3e, 0x45, 0xc5,
+	0xd4, 0x84, 0x95, 0xdf, 0xbd, 0xb1, 0xe5, 0x99, 0x5f, 0x9b, 0x93, 0x77, 0x2f, 0x6e, 0xa3, 0xdf,
+	0xc3, 0x7a, 0xa2, 0x1f, 0x8a, 0x6f, 0x1f, 0xc3, 0x7c, 0x34, 0x98, 0x88, 0x72, 0x7b, 0xfa, 0xa8,
+	0xa2, 0x6f, 0xf8, 0xf7, 0x72, 0x44, 0xe3, 0x2f, 0xc8, 0x2d, 0xa8, 0x72, 0x8e, 0x3d, 0x49, 0xf4,
+	0x4d, 0x37, 0xd2, 0x3f, 0x0a, 0xb0, 0x92, 0x0a, 0xf3, 0x56, 0xff, 0x9d, 0x09, 0x3b, 0xa4, 0xf8,
+	0x06, 0x3b, 0x64, 0x66, 0xf2, 0x0e, 0x99, 0x4d, 0xed, 0x90, 0x1d, 0x98, 0x77, 0x43, 0xf9, 0xe7,
+	0x90, 0x0b, 0xa3, 0x6c, 0x8d, 0x0d, 0xfa, 0x04, 0x9a, 0x2c, 0x3d, 0x01, 0xfd, 0xae, 0x94, 0xb5,
+	0xbb, 0x72, 0xfa, 0xd7, 0x02, 0x94, 0xa3, 0xee, 0x90, 0x4b, 0x28, 0x47, 0x92, 0x85, 0xbc, 0xa7,
+	0x0f, 0x22, 0xa1, 0x9f, 0xcc, 0xdd, 0xbc, 0x63, 0x39, 0x5a, 0xba, 0xf9, 0xea, 0xef, 0x7f, 0x7f,
+	0x2b, 0xac, 0x9c, 0x19, 0x27, 0xb4, 0xd2, 0x18, 0xde, 0x6f, 0x44, 0xde, 0xe4, 0x67, 0x03, 0x56,
+	0x33, 0x24, 0x0f, 0x39, 0x4e, 0x0c, 0x3e, 0x57, 0x15, 0x99, 0x1b, 0x75, 0x29, 0xf6, 0xea, 0x91,
+	0x12, 0xac, 0x5f, 0x70, 0x25, 0x48, 0xef, 0x0b, 0xc8, 0x3b, 0x67, 0xc6, 0x89, 0x79, 0x14, 0x87,
+	0x6c, 0x7c, 0xe7, 0x3a, 0x3f, 0x34, 0xc4, 0x24, 0x6d, 0x19, 0xa9, 0xa1, 0x2e, 0x06, 0xf9, 0xd3,
+	0x80, 0xed, 0x09, 0xda, 0x87, 0xdc, 0x4b, 0x55, 0xf9, 0x1a, 0x99, 0x94, 0x9b, 0xdc, 0x47, 0x22,
+	0xb9, 0x7b, 0xbc, 0x1f, 0x77, 0xa6, 0x4b, 0x4e, 0x66, 0xf0, 0xa3, 0x01, 0x24, 0xad, 0x9e, 0x48,
+	0x62, 0xa3, 0xe5, 0xea, 0xab, 0xdc, 0x7c, 0x3e, 0x10, 0xf9, 0x1c, 0xf2, 0x66, 0xed, 0x4e, 0xce,
+	0x87, 0x5c, 0x01, 0x8c, 0x35, 0x14, 0xd9, 0xcb, 0x42, 0x8e, 0xa9, 0xab, 0x5c, 0xc4, 0x03, 0x81,
+	0xb8, 0xcd, 0x11, 0x37, 0xd2, 0x88, 0x1e, 0x8f, 0xfd, 0x25, 0x94, 0x24, 0xab, 0xc9, 0xb6, 0x8e,
+	0xa2, 0xc9, 0xa6, 0x5c, 0x84, 0x2d, 0x81, 0xb0, 0x7a, 0xb2, 0x92, 0x0a, 0x4f, 0xbe, 0x86, 0x92,
+	0xd4, 0x4b, 0xc9, 0xc8, 0x9a, 0x8a, 0xca, 0x8d, 0xbc, 0x2f, 0x22, 0x9b, 0xb4, 0x96, 0x4e, 0xbc,
+	0x2d, 0xc3, 0xf6, 0xa1, 0x12, 0xd7, 0x26, 0xe4, 0x20, 0xc9, 0x9c, 0x94, 0x16, 0x33, 0xe9, 0x24,
+	0x17, 0x75, 0x8d, 0x54, 0x49, 0x24, 0xa3, 0xa4, 0x6b, 0x58, 0xd4, 0xa5, 0x1b, 0x39, 0xcc, 0x0f,
+	0xd8, 0x64, 0x6f, 0x82, 0xba, 0x23, 0x50, 0x37, 0xc8, 0x5a, 0xba, 0x5c, 0x9b, 0x91, 0xdf, 0x0d,
+	0x30, 0xf3, 0x15, 0x1d, 0x69, 0xe4, 0x03, 0x64, 0x6a, 0xbf, 0xa9, 0x32, 0x3a, 0x12, 0x19, 0xed,
+	0x13, 0x8d, 0xab, 0x61, 0xe3, 0x72, 0x74, 0x57, 0x50, 0xf5, 0x6e, 0xc4, 0xd5, 0x9f, 0x0c, 0x58,
+	0x8a, 0x02, 0xa8, 0xdf, 0x3c, 0xb9, 0x95, 0x1d, 0x5f, 0x17, 0x59, 0xe6, 0xed, 0xd7, 0x78, 0xa9,
+	0x44, 0x14, 0x8b, 0xc9, 0x56, 0xba, 0x35, 0x57, 0x0a, 0xcf, 0x87, 0xaa, 0xf6, 0xbb, 0x23, 0x89,
+	0x02, 0xb3, 0xb4, 0x81, 0x79, 0x38, 0xd1, 0x47, 0x81, 0xaf, 0x0b, 0xf0, 0x25, 0x52, 0xd5, 0xba,
+	0x70, 0x59, 0x12, 0x6c, 0xfd, 0xf0, 0xff, 0x01, 0x00, 0xad, 0x0e, 0x91, 0x15, 0x40, 0x0f, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConn
+var _ grpc.ClientConnInterface
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+const _ = grpc.SupportPackageIsVersion6
 
 // CustomerClient is the client API for Customer service.
 //
@@ -483,17 +1148,23 @@ const _ = grpc.SupportPackageIsVersion4
 type CustomerClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 	ConfirmEmailAddress(ctx context.Context, in *ConfirmEmailAddressRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	RegenerateEmailConfirmation(ctx context.Context, in *RegenerateEmailConfirmationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	ChangeEmailAddress(ctx context.Context, in *ChangeEmailAddressRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	ChangeName(ctx context.Context, in *ChangeNameRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Forget(ctx context.Context, in *ForgetRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	RetrieveView(ctx context.Context, in *RetrieveViewRequest, opts ...grpc.CallOption) (*RetrieveViewResponse, error)
+	RetrieveViewAt(ctx context.Context, in *RetrieveViewAtRequest, opts ...grpc.CallOption) (*RetrieveViewResponse, error)
+	RetrieveViewByEmailAddress(ctx context.Context, in *RetrieveViewByEmailAddressRequest, opts ...grpc.CallOption) (*RetrieveViewResponse, error)
+	RetrieveHistory(ctx context.Context, in *RetrieveHistoryRequest, opts ...grpc.CallOption) (*RetrieveHistoryResponse, error)
+	ListCustomers(ctx context.Context, in *ListCustomersRequest, opts ...grpc.CallOption) (*ListCustomersResponse, error)
 }
 
 type customerClient struct {
-	cc *grpc.ClientConn
+	cc grpc.ClientConnInterface
 }
 
-func NewCustomerClient(cc *grpc.ClientConn) CustomerClient {
+func NewCustomerClient(cc grpc.ClientConnInterface) CustomerClient {
 	return &customerClient{cc}
 }
 
@@ -515,6 +1186,15 @@ func (c *customerClient) ConfirmEmailAddress(ctx context.Context, in *ConfirmEma
 	return out, nil
 }
 
+func (c *customerClient) RegenerateEmailConfirmation(ctx context.Context, in *RegenerateEmailConfirmationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/customergrpc.Customer/RegenerateEmailConfirmation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *customerClient) ChangeEmailAddress(ctx context.Context, in *ChangeEmailAddressRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/customergrpc.Customer/ChangeEmailAddress", in, out, opts...)
@@ -542,6 +1222,15 @@ func (c *customerClient) Delete(ctx context.Context, in *DeleteRequest, opts ...
 	return out, nil
 }
 
+func (c *customerClient) Forget(ctx context.Context, in *ForgetRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/customergrpc.Customer/Forget", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *customerClient) RetrieveView(ctx context.Context, in *RetrieveViewRequest, opts ...grpc.CallOption) (*RetrieveViewResponse, error) {
 	out := new(RetrieveViewResponse)
 	err := c.cc.Invoke(ctx, "/customergrpc.Customer/RetrieveView", in, out, opts...)
@@ -551,14 +1240,56 @@ func (c *customerClient) RetrieveView(ctx context.Context, in *RetrieveViewReque
 	return out, nil
 }
 
+func (c *customerClient) RetrieveViewAt(ctx context.Context, in *RetrieveViewAtRequest, opts ...grpc.CallOption) (*RetrieveViewResponse, error) {
+	out := new(RetrieveViewResponse)
+	err := c.cc.Invoke(ctx, "/customergrpc.Customer/RetrieveViewAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerClient) RetrieveViewByEmailAddress(ctx context.Context, in *RetrieveViewByEmailAddressRequest, opts ...grpc.CallOption) (*RetrieveViewResponse, error) {
+	out := new(RetrieveViewResponse)
+	err := c.cc.Invoke(ctx, "/customergrpc.Customer/RetrieveViewByEmailAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerClient) RetrieveHistory(ctx context.Context, in *RetrieveHistoryRequest, opts ...grpc.CallOption) (*RetrieveHistoryResponse, error) {
+	out := new(RetrieveHistoryResponse)
+	err := c.cc.Invoke(ctx, "/customergrpc.Customer/RetrieveHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerClient) ListCustomers(ctx context.Context, in *ListCustomersRequest, opts ...grpc.CallOption) (*ListCustomersResponse, error) {
+	out := new(ListCustomersResponse)
+	err := c.cc.Invoke(ctx, "/customergrpc.Customer/ListCustomers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CustomerServer is the server API for Customer service.
 type CustomerServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	ConfirmEmailAddress(context.Context, *ConfirmEmailAddressRequest) (*empty.Empty, error)
+	RegenerateEmailConfirmation(context.Context, *RegenerateEmailConfirmationRequest) (*empty.Empty, error)
 	ChangeEmailAddress(context.Context, *ChangeEmailAddressRequest) (*empty.Empty, error)
 	ChangeName(context.Context, *ChangeNameRequest) (*empty.Empty, error)
 	Delete(context.Context, *DeleteRequest) (*empty.Empty, error)
+	Forget(context.Context, *ForgetRequest) (*empty.Empty, error)
 	RetrieveView(context.Context, *RetrieveViewRequest) (*RetrieveViewResponse, error)
+	RetrieveViewAt(context.Context, *RetrieveViewAtRequest) (*RetrieveViewResponse, error)
+	RetrieveViewByEmailAddress(context.Context, *RetrieveViewByEmailAddressRequest) (*RetrieveViewResponse, error)
+	RetrieveHistory(context.Context, *RetrieveHistoryRequest) (*RetrieveHistoryResponse, error)
+	ListCustomers(context.Context, *ListCustomersRequest) (*ListCustomersResponse, error)
 }
 
 // UnimplementedCustomerServer can be embedded to have forward compatible implementations.
@@ -571,6 +1302,9 @@ func (*UnimplementedCustomerServer) Register(ctx context.Context, req *RegisterR
 func (*UnimplementedCustomerServer) ConfirmEmailAddress(ctx context.Context, req *ConfirmEmailAddressRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConfirmEmailAddress not implemented")
 }
+func (*UnimplementedCustomerServer) RegenerateEmailConfirmation(ctx context.Context, req *RegenerateEmailConfirmationRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegenerateEmailConfirmation not implemented")
+}
 func (*UnimplementedCustomerServer) ChangeEmailAddress(ctx context.Context, req *ChangeEmailAddressRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChangeEmailAddress not implemented")
 }
@@ -580,9 +1314,24 @@ func (*UnimplementedCustomerServer) ChangeName(ctx context.Context, req *ChangeN
 func (*UnimplementedCustomerServer) Delete(ctx context.Context, req *DeleteRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (*UnimplementedCustomerServer) Forget(ctx context.Context, req *ForgetRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Forget not implemented")
+}
 func (*UnimplementedCustomerServer) RetrieveView(ctx context.Context, req *RetrieveViewRequest) (*RetrieveViewResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RetrieveView not implemented")
 }
+func (*UnimplementedCustomerServer) RetrieveViewAt(ctx context.Context, req *RetrieveViewAtRequest) (*RetrieveViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetrieveViewAt not implemented")
+}
+func (*UnimplementedCustomerServer) RetrieveViewByEmailAddress(ctx context.Context, req *RetrieveViewByEmailAddressRequest) (*RetrieveViewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetrieveViewByEmailAddress not implemented")
+}
+func (*UnimplementedCustomerServer) RetrieveHistory(ctx context.Context, req *RetrieveHistoryRequest) (*RetrieveHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetrieveHistory not implemented")
+}
+func (*UnimplementedCustomerServer) ListCustomers(ctx context.Context, req *ListCustomersRequest) (*ListCustomersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCustomers not implemented")
+}
 
 func RegisterCustomerServer(s *grpc.Server, srv CustomerServer) {
 	s.RegisterService(&_Customer_serviceDesc, srv)
@@ -624,6 +1373,24 @@ func _Customer_ConfirmEmailAddress_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Customer_RegenerateEmailConfirmation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegenerateEmailConfirmationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServer).RegenerateEmailConfirmation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/customergrpc.Customer/RegenerateEmailConfirmation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServer).RegenerateEmailConfirmation(ctx, req.(*RegenerateEmailConfirmationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Customer_ChangeEmailAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ChangeEmailAddressRequest)
 	if err := dec(in); err != nil {
@@ -678,6 +1445,24 @@ func _Customer_Delete_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Customer_Forget_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForgetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServer).Forget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/customergrpc.Customer/Forget",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServer).Forget(ctx, req.(*ForgetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Customer_RetrieveView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RetrieveViewRequest)
 	if err := dec(in); err != nil {
@@ -696,6 +1481,78 @@ func _Customer_RetrieveView_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Customer_RetrieveViewAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetrieveViewAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServer).RetrieveViewAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/customergrpc.Customer/RetrieveViewAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServer).RetrieveViewAt(ctx, req.(*RetrieveViewAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Customer_RetrieveViewByEmailAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetrieveViewByEmailAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServer).RetrieveViewByEmailAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/customergrpc.Customer/RetrieveViewByEmailAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServer).RetrieveViewByEmailAddress(ctx, req.(*RetrieveViewByEmailAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Customer_RetrieveHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetrieveHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServer).RetrieveHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/customergrpc.Customer/RetrieveHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServer).RetrieveHistory(ctx, req.(*RetrieveHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Customer_ListCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServer).ListCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/customergrpc.Customer/ListCustomers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServer).ListCustomers(ctx, req.(*ListCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Customer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "customergrpc.Customer",
 	HandlerType: (*CustomerServer)(nil),
@@ -708,6 +1565,10 @@ var _Customer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ConfirmEmailAddress",
 			Handler:    _Customer_ConfirmEmailAddress_Handler,
 		},
+		{
+			MethodName: "RegenerateEmailConfirmation",
+			Handler:    _Customer_RegenerateEmailConfirmation_Handler,
+		},
 		{
 			MethodName: "ChangeEmailAddress",
 			Handler:    _Customer_ChangeEmailAddress_Handler,
@@ -720,10 +1581,30 @@ var _Customer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _Customer_Delete_Handler,
 		},
+		{
+			MethodName: "Forget",
+			Handler:    _Customer_Forget_Handler,
+		},
 		{
 			MethodName: "RetrieveView",
 			Handler:    _Customer_RetrieveView_Handler,
 		},
+		{
+			MethodName: "RetrieveViewAt",
+			Handler:    _Customer_RetrieveViewAt_Handler,
+		},
+		{
+			MethodName: "RetrieveViewByEmailAddress",
+			Handler:    _Customer_RetrieveViewByEmailAddress_Handler,
+		},
+		{
+			MethodName: "RetrieveHistory",
+			Handler:    _Customer_RetrieveHistory_Handler,
+		},
+		{
+			MethodName: "ListCustomers",
+			Handler:    _Customer_ListCustomers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "customer.proto",
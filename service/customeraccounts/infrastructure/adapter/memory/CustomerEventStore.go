@@ -0,0 +1,556 @@
+package memory
+
+import (
+	"context"
+
+	"sort"
+	"sync"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+const streamPrefix = "customer"
+
+type storedEvent struct {
+	globalPosition uint64
+	streamID       es.StreamID
+	eventName      string
+	occurredAt     string
+	payload        []byte
+	streamVersion  uint
+}
+
+// CustomerEventStore is an in-memory replacement for postgres.CustomerEventStore.
+// It is meant for tests and local runs and enforces the same semantics: optimistic concurrency
+// on (stream, version), duplicate detection when starting a stream, unique email addresses and snapshots.
+type CustomerEventStore struct {
+	mutex                             sync.RWMutex
+	streams                           map[string][]storedEvent
+	snapshots                         map[string]storedEvent
+	lastGlobalPosition                uint64
+	outbox                            []es.OutboxMessage
+	lastOutboxMessageID               uint64
+	uniqueEmailAddresses              map[string]string
+	marshalDomainEvent                es.MarshalDomainEvent
+	unmarshalDomainEvent              es.UnmarshalDomainEvent
+	contentType                       string
+	buildUniqueEmailAddressAssertions customer.ForBuildingUniqueEmailAddressAssertions
+	snapshotInterval                  uint
+	buildSnapshot                     es.BuildSnapshot
+}
+
+func NewCustomerEventStore(
+	marshalDomainEvent es.MarshalDomainEvent,
+	unmarshalDomainEvent es.UnmarshalDomainEvent,
+	contentType string,
+	buildUniqueEmailAddressAssertions customer.ForBuildingUniqueEmailAddressAssertions,
+	snapshotInterval uint,
+	buildSnapshot es.BuildSnapshot,
+) *CustomerEventStore {
+
+	return &CustomerEventStore{
+		streams:                           make(map[string][]storedEvent),
+		snapshots:                         make(map[string]storedEvent),
+		uniqueEmailAddresses:              make(map[string]string),
+		marshalDomainEvent:                marshalDomainEvent,
+		unmarshalDomainEvent:              unmarshalDomainEvent,
+		contentType:                       contentType,
+		buildUniqueEmailAddressAssertions: buildUniqueEmailAddressAssertions,
+		snapshotInterval:                  snapshotInterval,
+		buildSnapshot:                     buildSnapshot,
+	}
+}
+
+func (s *CustomerEventStore) RetrieveEventStream(ctx context.Context, id value.CustomerID) (es.EventStream, error) {
+	wrapWithMsg := "customerEventStore.RetrieveEventStream"
+
+	s.mutex.RLock()
+	eventStream, err := s.loadEventStreamWithSnapshot(ctx, s.streamID(id))
+	s.mutex.RUnlock()
+
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if len(eventStream) == 0 {
+		err := errors.New("customer not found")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	return eventStream, nil
+}
+
+func (s *CustomerEventStore) RetrieveFullEventStream(ctx context.Context, id value.CustomerID) (es.EventStream, error) {
+	wrapWithMsg := "customerEventStore.RetrieveFullEventStream"
+
+	s.mutex.RLock()
+	eventStream, err := s.loadEventStream(ctx, s.streamID(id), false)
+	s.mutex.RUnlock()
+
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if len(eventStream) == 0 {
+		err := errors.New("customer not found")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	return eventStream, nil
+}
+
+func (s *CustomerEventStore) RetrieveEventStreamRange(
+	ctx context.Context,
+	id value.CustomerID,
+	fromVersion uint,
+	maxEvents uint,
+) (es.EventStream, error) {
+
+	wrapWithMsg := "customerEventStore.RetrieveEventStreamRange"
+
+	s.mutex.RLock()
+	fullEventStream, err := s.loadEventStream(ctx, s.streamID(id), false)
+	s.mutex.RUnlock()
+
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if len(fullEventStream) == 0 && fromVersion <= 1 {
+		err := errors.New("customer not found")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	var eventStream es.EventStream
+
+	for _, event := range fullEventStream {
+		if uint(len(eventStream)) == maxEvents {
+			break
+		}
+
+		if event.Meta().StreamVersion() >= fromVersion {
+			eventStream = append(eventStream, event)
+		}
+	}
+
+	return eventStream, nil
+}
+
+func (s *CustomerEventStore) StartEventStream(ctx context.Context, customerRegistered domain.CustomerRegistered) error {
+	wrapWithMsg := "customerEventStore.StartEventStream"
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	uniqueEmailAddresses, err := s.assertUniqueEmailAddress(s.buildUniqueEmailAddressAssertions(customerRegistered))
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	streamID := s.streamID(customerRegistered.CustomerID())
+
+	eventsToAppend, err := s.prepareEventsToAppend(ctx, streamID, customerRegistered)
+	if err != nil {
+		if errors.Is(err, shared.ErrConcurrencyConflict) {
+			return shared.MarkAndWrapError(errors.New("found duplicate customer"), shared.ErrDuplicate, wrapWithMsg)
+		}
+
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	snapshot, err := s.prepareSnapshotIfDue(ctx, streamID, customerRegistered)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	s.commit(streamID, eventsToAppend, snapshot, uniqueEmailAddresses)
+
+	return nil
+}
+
+func (s *CustomerEventStore) AppendToEventStream(
+	ctx context.Context,
+	recordedEvents es.RecordedEvents,
+	id value.CustomerID,
+) error {
+
+	wrapWithMsg := "customerEventStore.AppendToEventStream"
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	uniqueEmailAddresses, err := s.assertUniqueEmailAddress(s.buildUniqueEmailAddressAssertions(recordedEvents...))
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	streamID := s.streamID(id)
+
+	eventsToAppend, err := s.prepareEventsToAppend(ctx, streamID, recordedEvents...)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	snapshot, err := s.prepareSnapshotIfDue(ctx, streamID, recordedEvents...)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	s.commit(streamID, eventsToAppend, snapshot, uniqueEmailAddresses)
+
+	return nil
+}
+
+func (s *CustomerEventStore) PurgeEventStream(_ context.Context, id value.CustomerID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for emailAddress, customerID := range s.uniqueEmailAddresses {
+		if customerID == id.String() {
+			delete(s.uniqueEmailAddresses, emailAddress)
+		}
+	}
+
+	delete(s.streams, s.streamID(id).String())
+	delete(s.snapshots, s.streamID(id).String())
+
+	var outbox []es.OutboxMessage
+
+	for _, message := range s.outbox {
+		if message.StreamID() != s.streamID(id) {
+			outbox = append(outbox, message)
+		}
+	}
+
+	s.outbox = outbox
+
+	return nil
+}
+
+func (s *CustomerEventStore) RetrieveCustomerIDByEmailAddress(
+	_ context.Context,
+	emailAddress value.EmailAddress,
+) (value.CustomerID, error) {
+
+	wrapWithMsg := "customerEventStore.RetrieveCustomerIDByEmailAddress"
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	customerID, found := s.uniqueEmailAddresses[emailAddress.Canonical()]
+	if !found {
+		err := errors.New("customer not found")
+		return value.CustomerID{}, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	return value.RebuildCustomerID(customerID), nil
+}
+
+// ReadGlobalEvents reads the events of all streams in the order in which they were appended.
+func (s *CustomerEventStore) ReadGlobalEvents(
+	ctx context.Context,
+	afterPosition uint64,
+	maxEvents uint,
+) ([]es.GlobalEvent, error) {
+
+	wrapWithMsg := "customerEventStore.ReadGlobalEvents"
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var storedEvents []storedEvent
+
+	for _, stream := range s.streams {
+		for _, storedEvent := range stream {
+			if storedEvent.globalPosition > afterPosition {
+				storedEvents = append(storedEvents, storedEvent)
+			}
+		}
+	}
+
+	sort.Slice(storedEvents, func(i, j int) bool {
+		return storedEvents[i].globalPosition < storedEvents[j].globalPosition
+	})
+
+	var globalEvents []es.GlobalEvent
+
+	for _, storedEvent := range storedEvents {
+		if uint(len(globalEvents)) == maxEvents {
+			break
+		}
+
+		domainEvent, err := s.unmarshalDomainEvent(ctx, storedEvent.eventName, storedEvent.payload, storedEvent.streamVersion)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+		}
+
+		globalEvents = append(
+			globalEvents,
+			es.BuildGlobalEvent(storedEvent.globalPosition, storedEvent.streamID, domainEvent),
+		)
+	}
+
+	return globalEvents, nil
+}
+
+// ReadOutboxMessages reads the events which were not published yet, it is meant to be used by es.OutboxRelay.
+func (s *CustomerEventStore) ReadOutboxMessages(
+	_ context.Context,
+	afterID uint64,
+	maxMessages uint,
+) ([]es.OutboxMessage, error) {
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var messages []es.OutboxMessage
+
+	for _, message := range s.outbox {
+		if uint(len(messages)) == maxMessages {
+			break
+		}
+
+		if message.ID() > afterID {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *CustomerEventStore) MarkOutboxMessageAsPublished(_ context.Context, id uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for idx, message := range s.outbox {
+		if message.ID() == id {
+			s.outbox = append(s.outbox[:idx], s.outbox[idx+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *CustomerEventStore) streamID(id value.CustomerID) es.StreamID {
+	return es.NewStreamID(streamPrefix + "-" + id.String())
+}
+
+/***** local methods for reading from the event store - they must be called while holding a lock *****/
+
+// loadEventStreamWithSnapshot returns the latest snapshot (if any) followed by all newer events.
+func (s *CustomerEventStore) loadEventStreamWithSnapshot(
+	ctx context.Context,
+	streamID es.StreamID,
+) (es.EventStream, error) {
+
+	return s.loadEventStream(ctx, streamID, true)
+}
+
+func (s *CustomerEventStore) loadEventStream(
+	ctx context.Context,
+	streamID es.StreamID,
+	useSnapshot bool,
+) (es.EventStream, error) {
+
+	wrapWithMsg := "loadEventStream"
+
+	var eventStream es.EventStream
+	var storedEvents []storedEvent
+	fromVersion := uint(0)
+
+	if snapshot, found := s.snapshots[streamID.String()]; found && useSnapshot {
+		storedEvents = append(storedEvents, snapshot)
+		fromVersion = snapshot.streamVersion + 1
+	}
+
+	for _, storedEvent := range s.streams[streamID.String()] {
+		if storedEvent.streamVersion >= fromVersion {
+			storedEvents = append(storedEvents, storedEvent)
+		}
+	}
+
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := s.unmarshalDomainEvent(ctx, storedEvent.eventName, storedEvent.payload, storedEvent.streamVersion)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+		}
+
+		eventStream = append(eventStream, domainEvent)
+	}
+
+	return eventStream, nil
+}
+
+/***** local methods for writing to the event store - they must be called while holding the write lock *****/
+
+func (s *CustomerEventStore) prepareEventsToAppend(
+	ctx context.Context,
+	streamID es.StreamID,
+	events ...es.DomainEvent,
+) ([]storedEvent, error) {
+
+	wrapWithMsg := "prepareEventsToAppend"
+	usedVersions := make(map[uint]bool)
+
+	for _, storedEvent := range s.streams[streamID.String()] {
+		usedVersions[storedEvent.streamVersion] = true
+	}
+
+	var eventsToAppend []storedEvent
+
+	for _, event := range events {
+		payload, err := s.marshalDomainEvent(ctx, event)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+		}
+
+		streamVersion := event.Meta().StreamVersion()
+
+		if usedVersions[streamVersion] {
+			err := errors.Newf("stream version [%d] is already used", streamVersion)
+			return nil, shared.MarkAndWrapError(err, shared.ErrConcurrencyConflict, wrapWithMsg)
+		}
+
+		usedVersions[streamVersion] = true
+
+		eventsToAppend = append(
+			eventsToAppend,
+			storedEvent{
+				streamID:      streamID,
+				eventName:     event.Meta().EventName(),
+				occurredAt:    event.Meta().OccurredAt(),
+				payload:       payload,
+				streamVersion: streamVersion,
+			},
+		)
+	}
+
+	return eventsToAppend, nil
+}
+
+// prepareSnapshotIfDue returns a new snapshot if the events crossed a multiple of the snapshot interval, else nil.
+func (s *CustomerEventStore) prepareSnapshotIfDue(
+	ctx context.Context,
+	streamID es.StreamID,
+	events ...es.DomainEvent,
+) (*storedEvent, error) {
+
+	wrapWithMsg := "prepareSnapshotIfDue"
+
+	if s.snapshotInterval == 0 || len(events) == 0 {
+		return nil, nil
+	}
+
+	firstVersion := events[0].Meta().StreamVersion()
+	lastVersion := events[len(events)-1].Meta().StreamVersion()
+
+	if (firstVersion-1)/s.snapshotInterval == lastVersion/s.snapshotInterval {
+		return nil, nil
+	}
+
+	eventStream, err := s.loadEventStreamWithSnapshot(ctx, streamID)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	snapshot := s.buildSnapshot(append(eventStream, events...))
+
+	payload, err := s.marshalDomainEvent(ctx, snapshot)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+	}
+
+	return &storedEvent{
+		eventName:     snapshot.Meta().EventName(),
+		payload:       payload,
+		streamVersion: snapshot.Meta().StreamVersion(),
+	}, nil
+}
+
+func (s *CustomerEventStore) commit(
+	streamID es.StreamID,
+	eventsToAppend []storedEvent,
+	snapshot *storedEvent,
+	uniqueEmailAddresses map[string]string,
+) {
+
+	for idx := range eventsToAppend {
+		s.lastGlobalPosition++
+		eventsToAppend[idx].globalPosition = s.lastGlobalPosition
+
+		s.lastOutboxMessageID++
+		s.outbox = append(
+			s.outbox,
+			es.RebuildOutboxMessage(
+				s.lastOutboxMessageID,
+				streamID,
+				eventsToAppend[idx].eventName,
+				eventsToAppend[idx].occurredAt,
+				eventsToAppend[idx].streamVersion,
+				s.contentType,
+				eventsToAppend[idx].payload,
+			),
+		)
+	}
+
+	s.streams[streamID.String()] = append(s.streams[streamID.String()], eventsToAppend...)
+	s.uniqueEmailAddresses = uniqueEmailAddresses
+
+	if snapshot != nil {
+		s.snapshots[streamID.String()] = *snapshot
+	}
+}
+
+/***** local methods for asserting unique email addresses - they must be called while holding the write lock *****/
+
+// assertUniqueEmailAddress works on a copy of the unique email addresses, which only replaces
+// the original once all events were prepared successfully, so that a failure leaves no traces.
+func (s *CustomerEventStore) assertUniqueEmailAddress(
+	assertions customer.UniqueEmailAddressAssertions,
+) (map[string]string, error) {
+
+	wrapWithMsg := "assertUniqueEmailAddresse"
+
+	uniqueEmailAddresses := make(map[string]string, len(s.uniqueEmailAddresses))
+	for emailAddress, customerID := range s.uniqueEmailAddresses {
+		uniqueEmailAddresses[emailAddress] = customerID
+	}
+
+	for _, assertion := range assertions {
+		switch assertion.DesiredAction() {
+		case customer.ShouldAddUniqueEmailAddress:
+			emailAddressToAdd := assertion.EmailAddressToAdd().Canonical()
+
+			if _, found := uniqueEmailAddresses[emailAddressToAdd]; found {
+				return nil, errors.Wrap(errors.Mark(errors.New("duplicate email address"), shared.ErrDuplicate), wrapWithMsg)
+			}
+
+			uniqueEmailAddresses[emailAddressToAdd] = assertion.CustomerID().String()
+		case customer.ShouldReplaceUniqueEmailAddress:
+			emailAddressToAdd := assertion.EmailAddressToAdd().Canonical()
+			emailAddressToRemove := assertion.EmailAddressToRemove().Canonical()
+
+			customerID, found := uniqueEmailAddresses[emailAddressToRemove]
+			if !found || emailAddressToAdd == emailAddressToRemove {
+				continue // same as an UPDATE which does not match any row or does not change it
+			}
+
+			if _, found := uniqueEmailAddresses[emailAddressToAdd]; found {
+				return nil, errors.Wrap(errors.Mark(errors.New("duplicate email address"), shared.ErrDuplicate), wrapWithMsg)
+			}
+
+			delete(uniqueEmailAddresses, emailAddressToRemove)
+			uniqueEmailAddresses[emailAddressToAdd] = customerID
+		case customer.ShouldRemoveUniqueEmailAddress:
+			delete(uniqueEmailAddresses, assertion.EmailAddressToRemove().Canonical())
+		}
+	}
+
+	return uniqueEmailAddresses, nil
+}
@@ -0,0 +1,197 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/memory"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCustomerEventStore(t *testing.T) {
+	Convey("Prepare test artifacts", t, func() {
+		var err error
+
+		ctx := context.Background()
+
+		eventStore := memory.NewCustomerEventStore(
+			serialization.MarshalCustomerEvent,
+			serialization.UnmarshalCustomerEvent,
+			es.ContentTypeJSON,
+			customer.BuildUniqueEmailAddressAssertions,
+			3,
+			customer.BuildSnapshot,
+		)
+
+		customerID := value.GenerateCustomerID()
+		otherCustomerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("fiona@gallagher.net")
+		newEmailAddress := value.RebuildEmailAddress("fiona@pratt.net")
+		confirmationHash := value.GenerateConfirmationHash()
+		personName := value.RebuildPersonName("Fiona", "Gallagher")
+
+		customerRegistered := domain.BuildCustomerRegistered(customerID, emailAddress, confirmationHash, personName, es.MessageMeta{}, 1)
+
+		Convey("When a Customer's event stream is started", func() {
+			err = eventStore.StartEventStream(ctx, customerRegistered)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be retrievable", func() {
+				eventStream, err := eventStore.RetrieveEventStream(ctx, customerID)
+				So(err, ShouldBeNil)
+				So(eventStream, ShouldHaveLength, 1)
+				So(eventStream[0], ShouldResemble, customerRegistered)
+			})
+
+			Convey("Then the event should be in the outbox", func() {
+				messages, err := eventStore.ReadOutboxMessages(ctx, 0, 10)
+				So(err, ShouldBeNil)
+				So(messages, ShouldHaveLength, 1)
+				So(messages[0].EventName(), ShouldEqual, customerRegistered.Meta().EventName())
+				So(messages[0].StreamVersion(), ShouldEqual, 1)
+
+				Convey("And when it is marked as published", func() {
+					err = eventStore.MarkOutboxMessageAsPublished(ctx, messages[0].ID())
+					So(err, ShouldBeNil)
+
+					Convey("Then the outbox should be empty", func() {
+						messages, err = eventStore.ReadOutboxMessages(ctx, 0, 10)
+						So(err, ShouldBeNil)
+						So(messages, ShouldBeEmpty)
+					})
+				})
+			})
+
+			Convey("And when it is started again", func() {
+				err = eventStore.StartEventStream(ctx, customerRegistered)
+
+				Convey("Then it should fail with a duplicate error", func() {
+					So(errors.Is(err, shared.ErrDuplicate), ShouldBeTrue)
+				})
+			})
+
+			Convey("And when another Customer's stream is started with the same email address", func() {
+				otherCustomerRegistered := domain.BuildCustomerRegistered(otherCustomerID, emailAddress, confirmationHash, personName, es.MessageMeta{}, 1)
+				err = eventStore.StartEventStream(ctx, otherCustomerRegistered)
+
+				Convey("Then it should fail with a duplicate error", func() {
+					So(errors.Is(err, shared.ErrDuplicate), ShouldBeTrue)
+
+					Convey("And the other Customer's stream should not exist", func() {
+						_, err = eventStore.RetrieveEventStream(ctx, otherCustomerID)
+						So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+					})
+				})
+			})
+
+			Convey("And when an event with an already used stream version is appended", func() {
+				nameChanged := domain.BuildCustomerNameChanged(customerID, personName, es.MessageMeta{}, 1)
+				err = eventStore.AppendToEventStream(ctx, es.RecordedEvents{nameChanged}, customerID)
+
+				Convey("Then it should fail with a concurrency conflict", func() {
+					So(errors.Is(err, shared.ErrConcurrencyConflict), ShouldBeTrue)
+				})
+			})
+
+			Convey("And when the email address is changed", func() {
+				emailAddressChanged := domain.BuildCustomerEmailAddressChanged(customerID, newEmailAddress, confirmationHash, emailAddress, es.MessageMeta{}, 2)
+				err = eventStore.AppendToEventStream(ctx, es.RecordedEvents{emailAddressChanged}, customerID)
+				So(err, ShouldBeNil)
+
+				Convey("Then another Customer should be able to use the previous email address", func() {
+					otherCustomerRegistered := domain.BuildCustomerRegistered(otherCustomerID, emailAddress, confirmationHash, personName, es.MessageMeta{}, 1)
+					err = eventStore.StartEventStream(ctx, otherCustomerRegistered)
+					So(err, ShouldBeNil)
+				})
+			})
+
+			Convey("And when enough events are appended to reach the snapshot interval", func() {
+				newPersonName := value.RebuildPersonName("Fiona", "Pratt")
+				nameChanged := domain.BuildCustomerNameChanged(customerID, newPersonName, es.MessageMeta{}, 2)
+				emailAddressChanged := domain.BuildCustomerEmailAddressChanged(customerID, newEmailAddress, confirmationHash, emailAddress, es.MessageMeta{}, 3)
+				err = eventStore.AppendToEventStream(ctx, es.RecordedEvents{nameChanged, emailAddressChanged}, customerID)
+				So(err, ShouldBeNil)
+
+				Convey("Then the event stream should start with a snapshot of version 3", func() {
+					eventStream, err := eventStore.RetrieveEventStream(ctx, customerID)
+					So(err, ShouldBeNil)
+					So(eventStream, ShouldHaveLength, 1)
+					So(eventStream[0], ShouldHaveSameTypeAs, domain.CustomerSnapshot{})
+					So(eventStream[0].Meta().StreamVersion(), ShouldEqual, 3)
+
+					snapshot := eventStream[0].(domain.CustomerSnapshot)
+					So(snapshot.PersonName(), ShouldResemble, newPersonName)
+					So(snapshot.EmailAddress(), ShouldResemble, newEmailAddress)
+
+					Convey("And when another event is appended", func() {
+						emailAddressConfirmed := domain.BuildCustomerEmailAddressConfirmed(customerID, newEmailAddress, es.MessageMeta{}, 4)
+						err = eventStore.AppendToEventStream(ctx, es.RecordedEvents{emailAddressConfirmed}, customerID)
+						So(err, ShouldBeNil)
+
+						Convey("Then the event stream should contain the snapshot and the newer event", func() {
+							eventStream, err := eventStore.RetrieveEventStream(ctx, customerID)
+							So(err, ShouldBeNil)
+							So(eventStream, ShouldHaveLength, 2)
+							So(eventStream[0].Meta().StreamVersion(), ShouldEqual, 3)
+							So(eventStream[1], ShouldResemble, emailAddressConfirmed)
+
+							view := customer.BuildViewFrom(eventStream)
+							So(view.IsEmailAddressConfirmed, ShouldBeTrue)
+							So(view.Version, ShouldEqual, 4)
+						})
+					})
+				})
+			})
+
+			Convey("And when events are appended to several streams", func() {
+				otherCustomerRegistered := domain.BuildCustomerRegistered(otherCustomerID, newEmailAddress, confirmationHash, personName, es.MessageMeta{}, 1)
+				err = eventStore.StartEventStream(ctx, otherCustomerRegistered)
+				So(err, ShouldBeNil)
+
+				emailAddressConfirmed := domain.BuildCustomerEmailAddressConfirmed(customerID, emailAddress, es.MessageMeta{}, 2)
+				err = eventStore.AppendToEventStream(ctx, es.RecordedEvents{emailAddressConfirmed}, customerID)
+				So(err, ShouldBeNil)
+
+				Convey("Then the global events should be readable in the order in which they were appended", func() {
+					globalEvents, err := eventStore.ReadGlobalEvents(ctx, 0, 10)
+					So(err, ShouldBeNil)
+					So(globalEvents, ShouldHaveLength, 3)
+					So(globalEvents[0].Event(), ShouldResemble, customerRegistered)
+					So(globalEvents[1].Event(), ShouldResemble, otherCustomerRegistered)
+					So(globalEvents[1].StreamID().String(), ShouldEqual, "customer-"+otherCustomerID.String())
+					So(globalEvents[2].Event(), ShouldResemble, emailAddressConfirmed)
+
+					Convey("And reading after a global position should respect maxEvents", func() {
+						globalEvents, err = eventStore.ReadGlobalEvents(ctx, globalEvents[0].GlobalPosition(), 1)
+						So(err, ShouldBeNil)
+						So(globalEvents, ShouldHaveLength, 1)
+						So(globalEvents[0].Event(), ShouldResemble, otherCustomerRegistered)
+					})
+				})
+			})
+
+			Convey("And when the event stream is purged", func() {
+				err = eventStore.PurgeEventStream(ctx, customerID)
+				So(err, ShouldBeNil)
+
+				Convey("Then it should not be found any more", func() {
+					_, err = eventStore.RetrieveEventStream(ctx, customerID)
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+
+					Convey("And the email address should be usable again", func() {
+						otherCustomerRegistered := domain.BuildCustomerRegistered(otherCustomerID, emailAddress, confirmationHash, personName, es.MessageMeta{}, 1)
+						err = eventStore.StartEventStream(ctx, otherCustomerRegistered)
+						So(err, ShouldBeNil)
+					})
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// CustomerListStore is an in-memory replacement for projection.SQLCustomerListStore.
+type CustomerListStore struct {
+	mutex      sync.RWMutex
+	views      map[string]customer.ListView
+	forgotten  map[string]bool
+	checkpoint uint64
+}
+
+func NewCustomerListStore() *CustomerListStore {
+	return &CustomerListStore{
+		views:     make(map[string]customer.ListView),
+		forgotten: make(map[string]bool),
+	}
+}
+
+func (s *CustomerListStore) SaveCustomerListView(
+	_ context.Context,
+	view customer.ListView,
+	globalPosition uint64,
+) error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, ok := s.views[view.ID]
+
+	switch {
+	case !ok:
+		s.views[view.ID] = view
+	case stored.Version < view.Version && !s.forgotten[view.ID]:
+		view.RegisteredAt = stored.RegisteredAt
+		s.views[view.ID] = view
+	}
+
+	s.checkpoint = globalPosition
+
+	return nil
+}
+
+func (s *CustomerListStore) ForgetCustomerListView(_ context.Context, customerID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if view, ok := s.views[customerID]; ok {
+		view.EmailAddress, view.GivenName, view.FamilyName = es.RedactedPII, es.RedactedPII, es.RedactedPII
+		view.IsDeleted = true
+		s.views[customerID] = view
+		s.forgotten[customerID] = true
+	}
+
+	return nil
+}
+
+func (s *CustomerListStore) CustomerListCheckpoint(_ context.Context) (uint64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.checkpoint, nil
+}
+
+func (s *CustomerListStore) RetrieveCustomerListViews(
+	_ context.Context,
+	query application.CustomerListQuery,
+) ([]customer.ListView, error) {
+
+	wrapWithMsg := "customerListStore.RetrieveCustomerListViews"
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	type sortableView struct {
+		sortKey string
+		view    customer.ListView
+	}
+
+	var after *sortableView
+
+	if query.After != nil {
+		after = &sortableView{sortKey: query.After.SortKey}
+		after.view.ID = query.After.CustomerID
+
+		if query.OrderBy == application.CustomerListOrderByRegisteredAt {
+			registeredAt, err := time.Parse(time.RFC3339Nano, query.After.SortKey)
+			if err != nil {
+				return nil, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+			}
+
+			after.sortKey = sortableTime(registeredAt)
+		}
+	}
+
+	isBefore := func(a, b sortableView) bool {
+		if a.sortKey == b.sortKey {
+			return a.view.ID < b.view.ID != query.Descending
+		}
+
+		return a.sortKey < b.sortKey != query.Descending
+	}
+
+	var matches []sortableView
+
+	for _, view := range s.views {
+		var registeredAt time.Time
+		var err error
+
+		if view.RegisteredAt != "" {
+			if registeredAt, err = time.Parse(time.RFC3339Nano, view.RegisteredAt); err != nil {
+				return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+			}
+		}
+
+		switch {
+		case query.IsEmailAddressConfirmed != nil && view.IsEmailAddressConfirmed != *query.IsEmailAddressConfirmed:
+			continue
+		case query.IsDeleted != nil && view.IsDeleted != *query.IsDeleted:
+			continue
+		case !query.RegisteredFrom.IsZero() && registeredAt.Before(query.RegisteredFrom):
+			continue
+		case !query.RegisteredTo.IsZero() && !registeredAt.Before(query.RegisteredTo):
+			continue
+		}
+
+		match := sortableView{view: view}
+
+		switch query.OrderBy {
+		case application.CustomerListOrderByRegisteredAt:
+			match.sortKey = sortableTime(registeredAt)
+		case application.CustomerListOrderByEmailAddress:
+			match.sortKey = view.EmailAddress
+		case application.CustomerListOrderByFamilyName:
+			match.sortKey = view.FamilyName
+		}
+
+		if after != nil && !isBefore(*after, match) {
+			continue
+		}
+
+		matches = append(matches, match)
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return isBefore(matches[i], matches[j]) })
+
+	var views []customer.ListView
+
+	for _, match := range matches {
+		if uint(len(views)) == query.Limit {
+			break
+		}
+
+		views = append(views, match.view)
+	}
+
+	return views, nil
+}
+
+// sortableTime can be compared as string, like the other sort keys.
+func sortableTime(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+// EncryptionKeyStore is an in-memory replacement for postgres.EncryptionKeyStore.
+type EncryptionKeyStore struct {
+	mutex    sync.Mutex
+	keys     map[string][]byte
+	shredded map[string]bool
+}
+
+func NewEncryptionKeyStore() *EncryptionKeyStore {
+	return &EncryptionKeyStore{
+		keys:     make(map[string][]byte),
+		shredded: make(map[string]bool),
+	}
+}
+
+func (s *EncryptionKeyStore) RetrieveOrCreateEncryptionKey(_ context.Context, subjectID string) ([]byte, error) {
+	wrapWithMsg := "encryptionKeyStore.RetrieveOrCreateEncryptionKey"
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.shredded[subjectID] {
+		err := errors.New("encryption key was shredded")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	if key, ok := s.keys[subjectID]; ok {
+		return key, nil
+	}
+
+	key, err := es.GenerateEncryptionKey()
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	s.keys[subjectID] = key
+
+	return key, nil
+}
+
+func (s *EncryptionKeyStore) RetrieveEncryptionKey(_ context.Context, subjectID string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key, ok := s.keys[subjectID]
+	if !ok {
+		err := errors.New("encryption key not found")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, "encryptionKeyStore.RetrieveEncryptionKey")
+	}
+
+	return key, nil
+}
+
+func (s *EncryptionKeyStore) ShredEncryptionKey(_ context.Context, subjectID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.keys, subjectID)
+	s.shredded[subjectID] = true
+
+	return nil
+}
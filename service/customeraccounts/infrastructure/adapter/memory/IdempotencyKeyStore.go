@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+type idempotencyRecord struct {
+	fingerprint string
+	result      string
+	isRecorded  bool
+	expiresAt   time.Time
+}
+
+// IdempotencyKeyStore is an in-memory replacement for es.SQLIdempotencyKeyStore.
+type IdempotencyKeyStore struct {
+	mutex              sync.Mutex
+	records            map[string]idempotencyRecord
+	ttl                time.Duration
+	reservationTimeout time.Duration
+}
+
+func NewIdempotencyKeyStore(ttl time.Duration, reservationTimeout time.Duration) *IdempotencyKeyStore {
+	return &IdempotencyKeyStore{
+		records:            make(map[string]idempotencyRecord),
+		ttl:                ttl,
+		reservationTimeout: reservationTimeout,
+	}
+}
+
+func (s *IdempotencyKeyStore) ReserveIdempotencyKey(
+	_ context.Context,
+	idempotencyKey, fingerprint string,
+) (string, bool, error) {
+
+	wrapWithMsg := "idempotencyKeyStore.ReserveIdempotencyKey"
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.records[idempotencyKey]
+
+	switch {
+	case !ok || record.expiresAt.Before(time.Now()):
+		s.records[idempotencyKey] = idempotencyRecord{
+			fingerprint: fingerprint,
+			expiresAt:   time.Now().Add(s.reservationTimeout),
+		}
+
+		return "", false, nil
+	case record.fingerprint != fingerprint:
+		err := errors.New("idempotency key was already used for another command")
+		return "", false, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	case !record.isRecorded:
+		err := errors.New("a command with this idempotency key is still in progress")
+		return "", false, shared.MarkAndWrapError(err, shared.ErrConcurrencyConflict, wrapWithMsg)
+	}
+
+	return record.result, true, nil
+}
+
+func (s *IdempotencyKeyStore) RecordIdempotentResult(_ context.Context, idempotencyKey, result string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, ok := s.records[idempotencyKey]; ok {
+		record.result = result
+		record.isRecorded = true
+		record.expiresAt = time.Now().Add(s.ttl)
+		s.records[idempotencyKey] = record
+	}
+
+	return nil
+}
+
+func (s *IdempotencyKeyStore) ReleaseIdempotencyKey(_ context.Context, idempotencyKey string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, ok := s.records[idempotencyKey]; ok && !record.isRecorded {
+		delete(s.records, idempotencyKey)
+	}
+
+	return nil
+}
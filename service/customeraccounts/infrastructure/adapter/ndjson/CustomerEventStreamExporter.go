@@ -0,0 +1,159 @@
+package ndjson
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const exportBatchSize = 100
+
+// ExportFilter selects the events to export, zero values do not filter.
+// From is inclusive and To is exclusive, both are compared with the occurredAt of the events.
+type ExportFilter struct {
+	StreamID es.StreamID
+	From     time.Time
+	To       time.Time
+}
+
+// CustomerEventStreamExporter writes events as newline delimited json, in the global order of all streams.
+// It always pages through all events, so that a stream and a time range can be combined.
+// By default the personal data is encrypted with the keys of the Customers, so forgetting a Customer (shredding
+// the key) also makes the exported personal data unreadable, and such files can only be imported where the keys are.
+type CustomerEventStreamExporter struct {
+	readGlobalEvents              es.ReadGlobalEvents
+	marshalCustomerEvent          es.MarshalDomainEvent
+	marshalDecryptedCustomerEvent es.MarshalDomainEvent
+}
+
+func NewCustomerEventStreamExporter(
+	readGlobalEvents es.ReadGlobalEvents,
+	marshalProtectedCustomerEvent es.MarshalDomainEvent,
+	marshalDecryptedCustomerEvent es.MarshalDomainEvent,
+) *CustomerEventStreamExporter {
+
+	return &CustomerEventStreamExporter{
+		readGlobalEvents:              readGlobalEvents,
+		marshalCustomerEvent:          marshalProtectedCustomerEvent,
+		marshalDecryptedCustomerEvent: marshalDecryptedCustomerEvent,
+	}
+}
+
+// WithDecryptedPII returns an exporter which writes the personal data in clear text, e.g. to move events into
+// another database, which doesn't have the keys. Forgetting a Customer does not reach into such files!
+func (e *CustomerEventStreamExporter) WithDecryptedPII() *CustomerEventStreamExporter {
+	return &CustomerEventStreamExporter{
+		readGlobalEvents:              e.readGlobalEvents,
+		marshalCustomerEvent:          e.marshalDecryptedCustomerEvent,
+		marshalDecryptedCustomerEvent: e.marshalDecryptedCustomerEvent,
+	}
+}
+
+func (e *CustomerEventStreamExporter) Export(ctx context.Context, writer io.Writer, filter ExportFilter) (uint, error) {
+	var exported uint
+	var afterPosition uint64
+	wrapWithMsg := "customerEventStreamExporter.Export"
+
+	bufferedWriter := bufio.NewWriter(writer)
+
+	for {
+		globalEvents, err := e.readGlobalEvents(ctx, afterPosition, exportBatchSize)
+		if err != nil {
+			return exported, errors.Wrap(err, wrapWithMsg)
+		}
+
+		if len(globalEvents) == 0 {
+			break
+		}
+
+		for _, globalEvent := range globalEvents {
+			afterPosition = globalEvent.GlobalPosition()
+
+			matches, err := e.matches(globalEvent, filter)
+			if err != nil {
+				return exported, errors.Wrap(err, wrapWithMsg)
+			}
+
+			if !matches {
+				continue
+			}
+
+			if err = e.writeLine(ctx, bufferedWriter, globalEvent); err != nil {
+				return exported, errors.Wrap(err, wrapWithMsg)
+			}
+
+			exported++
+		}
+	}
+
+	if err := bufferedWriter.Flush(); err != nil {
+		return exported, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return exported, nil
+}
+
+func (e *CustomerEventStreamExporter) matches(globalEvent es.GlobalEvent, filter ExportFilter) (bool, error) {
+	if filter.StreamID.String() != "" && globalEvent.StreamID().String() != filter.StreamID.String() {
+		return false, nil
+	}
+
+	if filter.From.IsZero() && filter.To.IsZero() {
+		return true, nil
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339Nano, globalEvent.Event().Meta().OccurredAt())
+	if err != nil {
+		return false, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, "matches")
+	}
+
+	if !filter.From.IsZero() && occurredAt.Before(filter.From) {
+		return false, nil
+	}
+
+	if !filter.To.IsZero() && !occurredAt.Before(filter.To) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (e *CustomerEventStreamExporter) writeLine(
+	ctx context.Context,
+	writer io.Writer,
+	globalEvent es.GlobalEvent,
+) error {
+
+	wrapWithMsg := "writeLine"
+
+	payload, err := e.marshalCustomerEvent(ctx, globalEvent.Event())
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	line, err := jsoniter.ConfigFastest.Marshal(
+		eventLine{
+			StreamID:      globalEvent.StreamID().String(),
+			StreamVersion: globalEvent.Event().Meta().StreamVersion(),
+			EventName:     globalEvent.Event().Meta().EventName(),
+			OccurredAt:    globalEvent.Event().Meta().OccurredAt(),
+			Payload:       payload,
+		},
+	)
+
+	if err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+	}
+
+	if _, err = writer.Write(append(line, '\n')); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return nil
+}
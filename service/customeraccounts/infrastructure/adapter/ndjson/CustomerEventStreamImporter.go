@@ -0,0 +1,209 @@
+package ndjson
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	maxLineSize  = 1024 * 1024
+	streamPrefix = "customer"
+)
+
+type customerEvent interface {
+	es.DomainEvent
+	CustomerID() value.CustomerID
+}
+
+type importStream struct {
+	streamID   string
+	customerID value.CustomerID
+	events     es.RecordedEvents
+}
+
+// CustomerEventStreamImporter reads the files which CustomerEventStreamExporter writes.
+// The whole file is validated before anything is written: each line must belong to the stream of its Customer,
+// each stream must continue exactly at the version which it has in the event store (or start at 1) and its
+// versions must have no gaps. Encrypted personal data can only be imported where the keys of the Customers are,
+// so unmarshalCustomerEvent must fail for missing keys instead of redacting the personal data.
+// Each stream is then appended in one transaction, which also rebuilds its unique email addresses.
+type CustomerEventStreamImporter struct {
+	unmarshalCustomerEvent      es.UnmarshalDomainEvent
+	retrieveCustomerEventStream application.ForRetrievingCustomerEventStreams
+	appendToCustomerEventStream application.ForAppendingToCustomerEventStreams
+}
+
+func NewCustomerEventStreamImporter(
+	unmarshalCustomerEvent es.UnmarshalDomainEvent,
+	retrieveCustomerEventStream application.ForRetrievingCustomerEventStreams,
+	appendToCustomerEventStream application.ForAppendingToCustomerEventStreams,
+) *CustomerEventStreamImporter {
+
+	return &CustomerEventStreamImporter{
+		unmarshalCustomerEvent:      unmarshalCustomerEvent,
+		retrieveCustomerEventStream: retrieveCustomerEventStream,
+		appendToCustomerEventStream: appendToCustomerEventStream,
+	}
+}
+
+// Import returns the number of imported events. If appending a stream fails, the streams before it stay imported.
+func (i *CustomerEventStreamImporter) Import(ctx context.Context, reader io.Reader) (uint, error) {
+	var imported uint
+	wrapWithMsg := "customerEventStreamImporter.Import"
+
+	streams, err := i.readStreams(ctx, reader)
+	if err != nil {
+		return 0, errors.Wrap(err, wrapWithMsg)
+	}
+
+	for _, stream := range streams {
+		if err = i.assertStreamContinues(ctx, stream); err != nil {
+			return 0, errors.Wrap(err, wrapWithMsg)
+		}
+	}
+
+	for _, stream := range streams {
+		if err = i.appendToCustomerEventStream(ctx, stream.events, stream.customerID); err != nil {
+			return imported, errors.Wrapf(err, "%s: failed to append stream [%s]", wrapWithMsg, stream.streamID)
+		}
+
+		imported += uint(len(stream.events))
+	}
+
+	return imported, nil
+}
+
+func (i *CustomerEventStreamImporter) readStreams(ctx context.Context, reader io.Reader) ([]*importStream, error) {
+	var streams []*importStream
+	var lineNumber uint
+	wrapWithMsg := "readStreams"
+
+	streamsByID := make(map[string]*importStream)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		lineNumber++
+
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		line := eventLine{}
+		if err := jsoniter.ConfigFastest.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, lineError(wrapWithMsg, lineNumber))
+		}
+
+		event, err := i.unmarshalLine(ctx, line)
+		if err != nil {
+			return nil, errors.Wrap(err, lineError(wrapWithMsg, lineNumber))
+		}
+
+		stream, ok := streamsByID[line.StreamID]
+		if !ok {
+			stream = &importStream{streamID: line.StreamID, customerID: event.CustomerID()}
+			streamsByID[line.StreamID] = stream
+			streams = append(streams, stream)
+		}
+
+		if err = assertEventContinuesStream(stream, event); err != nil {
+			return nil, errors.Wrap(err, lineError(wrapWithMsg, lineNumber))
+		}
+
+		stream.events = append(stream.events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return streams, nil
+}
+
+func (i *CustomerEventStreamImporter) unmarshalLine(ctx context.Context, line eventLine) (customerEvent, error) {
+	wrapWithMsg := "unmarshalLine"
+
+	if line.StreamID == "" || line.StreamVersion == 0 {
+		err := errors.New("stream_id and stream_version must be set")
+		return nil, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	}
+
+	domainEvent, err := i.unmarshalCustomerEvent(ctx, line.EventName, line.Payload, line.StreamVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	event, ok := domainEvent.(customerEvent)
+	if !ok || event.Meta().StreamVersion() != line.StreamVersion {
+		err = errors.Newf("[%s] is not a Customer event of version %d", line.EventName, line.StreamVersion)
+		return nil, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	}
+
+	if streamID := es.NewStreamID(streamPrefix + "-" + event.CustomerID().String()); streamID.String() != line.StreamID {
+		err = errors.Newf("stream_id [%s] is not the stream of Customer [%s]", line.StreamID, event.CustomerID().String())
+		return nil, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	}
+
+	return event, nil
+}
+
+func (i *CustomerEventStreamImporter) assertStreamContinues(ctx context.Context, stream *importStream) error {
+	var currentVersion uint
+	wrapWithMsg := "assertStreamContinues"
+
+	eventStream, err := i.retrieveCustomerEventStream(ctx, stream.customerID)
+
+	switch {
+	case err == nil:
+		currentVersion = eventStream[len(eventStream)-1].Meta().StreamVersion()
+	case !errors.Is(err, shared.ErrNotFound):
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	if firstVersion := stream.events[0].Meta().StreamVersion(); firstVersion != currentVersion+1 {
+		err = errors.Newf(
+			"stream [%s] is at version %d in the event store, the import starts at version %d",
+			stream.streamID,
+			currentVersion,
+			firstVersion,
+		)
+
+		return shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	}
+
+	return nil
+}
+
+func assertEventContinuesStream(stream *importStream, event customerEvent) error {
+	if len(stream.events) == 0 {
+		return nil
+	}
+
+	previousVersion := stream.events[len(stream.events)-1].Meta().StreamVersion()
+
+	if event.Meta().StreamVersion() != previousVersion+1 {
+		err := errors.Newf(
+			"stream [%s] continues with version %d after version %d",
+			stream.streamID,
+			event.Meta().StreamVersion(),
+			previousVersion,
+		)
+
+		return shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, "assertEventContinuesStream")
+	}
+
+	return nil
+}
+
+func lineError(wrapWithMsg string, lineNumber uint) string {
+	return wrapWithMsg + ": line " + strconv.FormatUint(uint64(lineNumber), 10)
+}
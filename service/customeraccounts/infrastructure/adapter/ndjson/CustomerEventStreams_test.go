@@ -0,0 +1,330 @@
+package ndjson_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/memory"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/ndjson"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCustomerEventStreams(t *testing.T) {
+	ctx := context.Background()
+
+	newEventStore := func() *memory.CustomerEventStore {
+		return memory.NewCustomerEventStore(
+			serialization.MarshalCustomerEvent,
+			serialization.UnmarshalCustomerEvent,
+			es.ContentTypeJSON,
+			customer.BuildUniqueEmailAddressAssertions,
+			0,
+			customer.BuildSnapshot,
+		)
+	}
+
+	newImporter := func(eventStore *memory.CustomerEventStore) *ndjson.CustomerEventStreamImporter {
+		return ndjson.NewCustomerEventStreamImporter(
+			serialization.UnmarshalCustomerEvent,
+			eventStore.RetrieveEventStream,
+			eventStore.AppendToEventStream,
+		)
+	}
+
+	Convey("Given a source event store with two Customers", t, func() {
+		sourceStore := newEventStore()
+		exporter := ndjson.NewCustomerEventStreamExporter(
+			sourceStore.ReadGlobalEvents,
+			serialization.MarshalCustomerEvent,
+			serialization.MarshalCustomerEvent,
+		)
+
+		customerID := value.GenerateCustomerID()
+		otherCustomerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("kevin@ball.net")
+		newEmailAddress := value.RebuildEmailAddress("kev@alibi.net")
+		otherEmailAddress := value.RebuildEmailAddress("veronica@fisher.net")
+		personName := value.RebuildPersonName("Kevin", "Ball")
+		otherPersonName := value.RebuildPersonName("Veronica", "Fisher")
+
+		So(sourceStore.StartEventStream(ctx, domain.BuildCustomerRegistered(
+			customerID, emailAddress, value.GenerateConfirmationHash(), personName, es.MessageMeta{}, 1,
+		)), ShouldBeNil)
+
+		So(sourceStore.StartEventStream(ctx, domain.BuildCustomerRegistered(
+			otherCustomerID, otherEmailAddress, value.GenerateConfirmationHash(), otherPersonName,
+			es.MessageMeta{}, 1,
+		)), ShouldBeNil)
+
+		So(sourceStore.AppendToEventStream(ctx, es.RecordedEvents{domain.BuildCustomerEmailAddressChanged(
+			customerID, newEmailAddress, value.GenerateConfirmationHash(), emailAddress,
+			es.MessageMeta{}, 2,
+		)}, customerID), ShouldBeNil)
+
+		Convey("When all streams are exported", func() {
+			export := &bytes.Buffer{}
+			exported, err := exporter.Export(ctx, export, ndjson.ExportFilter{})
+			So(err, ShouldBeNil)
+
+			Convey("Then there should be one line per event", func() {
+				So(exported, ShouldEqual, 3)
+				So(strings.Count(export.String(), "\n"), ShouldEqual, 3)
+			})
+
+			Convey("And when they are imported into an empty event store", func() {
+				targetStore := newEventStore()
+				imported, err := newImporter(targetStore).Import(ctx, export)
+				So(err, ShouldBeNil)
+				So(imported, ShouldEqual, 3)
+
+				Convey("Then the streams should be the same as in the source event store", func() {
+					for _, id := range []value.CustomerID{customerID, otherCustomerID} {
+						sourceStream, err := sourceStore.RetrieveEventStream(ctx, id)
+						So(err, ShouldBeNil)
+
+						targetStream, err := targetStore.RetrieveEventStream(ctx, id)
+						So(err, ShouldBeNil)
+						So(targetStream, ShouldResemble, sourceStream)
+					}
+				})
+
+				Convey("Then the unique email addresses should be rebuilt", func() {
+					err = targetStore.StartEventStream(ctx, domain.BuildCustomerRegistered(
+						value.GenerateCustomerID(), newEmailAddress, value.GenerateConfirmationHash(),
+						personName, es.MessageMeta{}, 1,
+					))
+					So(errors.Is(err, shared.ErrDuplicate), ShouldBeTrue)
+
+					err = targetStore.StartEventStream(ctx, domain.BuildCustomerRegistered(
+						value.GenerateCustomerID(), emailAddress, value.GenerateConfirmationHash(),
+						personName, es.MessageMeta{}, 1,
+					))
+					So(err, ShouldBeNil)
+				})
+			})
+
+			Convey("And when they are imported again into the source event store", func() {
+				imported, err := newImporter(sourceStore).Import(ctx, export)
+
+				Convey("Then it should fail without importing anything", func() {
+					So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+					So(imported, ShouldEqual, 0)
+				})
+			})
+
+			Convey("And when a line is missing in the export", func() {
+				lines := strings.SplitAfter(export.String(), "\n")
+				var withoutFirstLine string
+
+				for _, line := range lines[1:] {
+					withoutFirstLine += line
+				}
+
+				targetStore := newEventStore()
+				imported, err := newImporter(targetStore).Import(ctx, strings.NewReader(withoutFirstLine))
+
+				Convey("Then the import should fail without importing anything", func() {
+					So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+					So(imported, ShouldEqual, 0)
+
+					_, err = targetStore.RetrieveEventStream(ctx, otherCustomerID)
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+				})
+			})
+		})
+
+		Convey("When one stream is exported", func() {
+			export := &bytes.Buffer{}
+			exported, err := exporter.Export(ctx,
+				export,
+				ndjson.ExportFilter{StreamID: es.NewStreamID("customer-" + customerID.String())},
+			)
+
+			Convey("Then only its events should be exported", func() {
+				So(err, ShouldBeNil)
+				So(exported, ShouldEqual, 2)
+				So(export.String(), ShouldNotContainSubstring, otherCustomerID.String())
+			})
+		})
+
+		Convey("When a time range is exported", func() {
+			exportedAll, err := exporter.Export(ctx, &bytes.Buffer{}, ndjson.ExportFilter{To: time.Now().Add(time.Minute)})
+			So(err, ShouldBeNil)
+
+			exportedNone, err := exporter.Export(ctx, &bytes.Buffer{}, ndjson.ExportFilter{From: time.Now().Add(time.Minute)})
+			So(err, ShouldBeNil)
+
+			Convey("Then only the events which occurred in the range should be exported", func() {
+				So(exportedAll, ShouldEqual, 3)
+				So(exportedNone, ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given a source event store which encrypts the personal data, with a Customer and a forgotten Customer", t, func() {
+		keyStore := memory.NewEncryptionKeyStore()
+		piiProtection := serialization.NewCustomerPIIProtection(
+			keyStore.RetrieveOrCreateEncryptionKey,
+			keyStore.RetrieveEncryptionKey,
+		)
+
+		newProtectedEventStore := func() *memory.CustomerEventStore {
+			return memory.NewCustomerEventStore(
+				piiProtection.Marshal(serialization.MarshalCustomerEvent),
+				piiProtection.Unmarshal(serialization.UnmarshalCustomerEvent),
+				es.ContentTypeJSON,
+				customer.BuildUniqueEmailAddressAssertions,
+				0,
+				customer.BuildSnapshot,
+			)
+		}
+
+		sourceStore := newProtectedEventStore()
+		exporter := ndjson.NewCustomerEventStreamExporter(
+			sourceStore.ReadGlobalEvents,
+			piiProtection.Marshal(serialization.MarshalCustomerEvent),
+			serialization.MarshalCustomerEvent,
+		)
+
+		customerID := value.GenerateCustomerID()
+		forgottenCustomerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("kevin@ball.net")
+		forgottenEmailAddress := value.RebuildEmailAddress("veronica@fisher.net")
+		personName := value.RebuildPersonName("Kevin", "Ball")
+
+		So(sourceStore.StartEventStream(ctx, domain.BuildCustomerRegistered(
+			customerID, emailAddress, value.GenerateConfirmationHash(), personName, es.MessageMeta{}, 1,
+		)), ShouldBeNil)
+
+		So(sourceStore.StartEventStream(ctx, domain.BuildCustomerRegistered(
+			forgottenCustomerID, forgottenEmailAddress, value.GenerateConfirmationHash(),
+			personName, es.MessageMeta{}, 1,
+		)), ShouldBeNil)
+
+		So(keyStore.ShredEncryptionKey(ctx, forgottenCustomerID.String()), ShouldBeNil)
+
+		Convey("When all streams are exported", func() {
+			export := &bytes.Buffer{}
+			exported, err := exporter.Export(ctx, export, ndjson.ExportFilter{})
+			So(err, ShouldBeNil)
+			So(exported, ShouldEqual, 2)
+
+			Convey("Then the export should not contain personal data in clear text", func() {
+				So(export.String(), ShouldNotContainSubstring, emailAddress.String())
+				So(export.String(), ShouldNotContainSubstring, personName.FamilyName())
+				So(export.String(), ShouldNotContainSubstring, forgottenEmailAddress.String())
+			})
+
+			Convey("And when they are imported into an event store with the same keys", func() {
+				targetStore := newProtectedEventStore()
+				importer := ndjson.NewCustomerEventStreamImporter(
+					piiProtection.UnmarshalWithRequiredKeys(serialization.UnmarshalCustomerEvent),
+					targetStore.RetrieveEventStream,
+					targetStore.AppendToEventStream,
+				)
+
+				imported, err := importer.Import(ctx, export)
+				So(err, ShouldBeNil)
+				So(imported, ShouldEqual, 2)
+
+				Convey("Then the streams should be the same as in the source event store", func() {
+					for _, id := range []value.CustomerID{customerID, forgottenCustomerID} {
+						sourceStream, err := sourceStore.RetrieveEventStream(ctx, id)
+						So(err, ShouldBeNil)
+
+						targetStream, err := targetStore.RetrieveEventStream(ctx, id)
+						So(err, ShouldBeNil)
+						So(targetStream, ShouldResemble, sourceStream)
+					}
+				})
+			})
+
+			Convey("And when they are imported into an event store with other keys", func() {
+				otherKeyStore := memory.NewEncryptionKeyStore()
+				otherPIIProtection := serialization.NewCustomerPIIProtection(
+					otherKeyStore.RetrieveOrCreateEncryptionKey,
+					otherKeyStore.RetrieveEncryptionKey,
+				)
+
+				targetStore := memory.NewCustomerEventStore(
+					otherPIIProtection.Marshal(serialization.MarshalCustomerEvent),
+					otherPIIProtection.Unmarshal(serialization.UnmarshalCustomerEvent),
+					es.ContentTypeJSON,
+					customer.BuildUniqueEmailAddressAssertions,
+					0,
+					customer.BuildSnapshot,
+				)
+
+				importer := ndjson.NewCustomerEventStreamImporter(
+					otherPIIProtection.UnmarshalWithRequiredKeys(serialization.UnmarshalCustomerEvent),
+					targetStore.RetrieveEventStream,
+					targetStore.AppendToEventStream,
+				)
+
+				imported, err := importer.Import(ctx, export)
+
+				Convey("Then it should fail without importing anything", func() {
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+					So(imported, ShouldEqual, 0)
+
+					_, err = targetStore.RetrieveEventStream(ctx, customerID)
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+				})
+			})
+		})
+
+		Convey("When all streams are exported with decrypted personal data", func() {
+			export := &bytes.Buffer{}
+			_, err := exporter.WithDecryptedPII().Export(ctx, export, ndjson.ExportFilter{})
+			So(err, ShouldBeNil)
+
+			Convey("Then the export should contain the personal data in clear text, except of the forgotten Customer", func() {
+				So(export.String(), ShouldContainSubstring, emailAddress.String())
+				So(export.String(), ShouldNotContainSubstring, forgottenEmailAddress.String())
+				So(export.String(), ShouldContainSubstring, es.RedactedPII)
+			})
+		})
+	})
+
+	Convey("When a line with the stream_id of another Customer is imported", t, func() {
+		customerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("kevin@ball.net")
+		payload, err := serialization.MarshalCustomerEvent(ctx, domain.BuildCustomerRegistered(
+			customerID, emailAddress, value.GenerateConfirmationHash(),
+			value.RebuildPersonName("Kevin", "Ball"), es.MessageMeta{}, 1,
+		))
+		So(err, ShouldBeNil)
+
+		line := `{"stream_id":"customer-` + value.GenerateCustomerID().String() + `","stream_version":1,` +
+			`"event_name":"CustomerRegistered","payload":` + string(payload) + "}\n"
+
+		targetStore := newEventStore()
+		imported, err := newImporter(targetStore).Import(ctx, strings.NewReader(line))
+
+		Convey("Then it should fail without importing anything", func() {
+			So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+			So(imported, ShouldEqual, 0)
+
+			_, err = targetStore.RetrieveEventStream(ctx, customerID)
+			So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+		})
+	})
+
+	Convey("When an invalid line is imported", t, func() {
+		_, err := newImporter(newEventStore()).Import(ctx, strings.NewReader("{\"stream_id\":\n"))
+
+		Convey("Then it should fail", func() {
+			So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,16 @@
+package ndjson
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// eventLine is one line of an export file, its fields are the columns of the eventstore table.
+// The payload is the json of MarshalCustomerEvent, with the personal data encrypted unless it was exported
+// WithDecryptedPII.
+type eventLine struct {
+	StreamID      string              `json:"stream_id"`
+	StreamVersion uint                `json:"stream_version"`
+	EventName     string              `json:"event_name"`
+	OccurredAt    string              `json:"occurred_at"`
+	Payload       jsoniter.RawMessage `json:"payload"`
+}
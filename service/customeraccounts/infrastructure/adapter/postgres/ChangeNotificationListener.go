@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/lib/pq"
+)
+
+const (
+	listenerMinReconnectInterval = 100 * time.Millisecond
+	listenerMaxReconnectInterval = 10 * time.Second
+)
+
+// ChangeNotificationListener turns the notifications which es.SQLEventStore sends (with es.PostgresDialect) on each append
+// into es.ChangeNotifications on a Go channel.
+// While the connection is down, it sends a poll request every pollInterval instead,
+// and it sends one after reconnecting, because notifications might have been lost in between.
+type ChangeNotificationListener struct {
+	dsn                 string
+	notificationChannel string
+	pollInterval        time.Duration
+	logger              *shared.Logger
+	notifications       chan es.ChangeNotification
+}
+
+func NewChangeNotificationListener(
+	dsn string,
+	notificationChannel string,
+	pollInterval time.Duration,
+	logger *shared.Logger,
+) *ChangeNotificationListener {
+
+	return &ChangeNotificationListener{
+		dsn:                 dsn,
+		notificationChannel: notificationChannel,
+		pollInterval:        pollInterval,
+		logger:              logger,
+		notifications:       make(chan es.ChangeNotification, 100),
+	}
+}
+
+func (l *ChangeNotificationListener) Notifications() <-chan es.ChangeNotification {
+	return l.notifications
+}
+
+// Run listens until ctx is cancelled.
+func (l *ChangeNotificationListener) Run(ctx context.Context) error {
+	wrapWithMsg := "changeNotificationListener.Run"
+
+	connectionEvents := make(chan pq.ListenerEventType, 10)
+
+	listener := pq.NewListener(
+		l.dsn,
+		listenerMinReconnectInterval,
+		listenerMaxReconnectInterval,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				l.logger.Warnf("changeNotificationListener: connection problem: %s", err)
+			}
+
+			select {
+			case connectionEvents <- event:
+			default:
+			}
+		},
+	)
+
+	defer func() { _ = listener.Close() }()
+
+	// Listen blocks until the first connection is established, so we start polling in the meantime
+	listenErr := make(chan error, 1)
+	go func() { listenErr <- listener.Listen(l.notificationChannel) }()
+
+	isConnected := false
+	pollTicker := time.NewTicker(l.pollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-listenErr:
+			if err != nil {
+				return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+			}
+		case event := <-connectionEvents:
+			switch event {
+			case pq.ListenerEventConnected, pq.ListenerEventReconnected:
+				isConnected = true
+			case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+				isConnected = false
+			}
+		case notification := <-listener.Notify:
+			if notification == nil { // sent by pq after reconnecting
+				l.send(ctx, es.BuildPollRequest())
+				continue
+			}
+
+			l.send(ctx, l.unmarshalChangeNotification(notification.Extra))
+		case <-pollTicker.C:
+			if !isConnected {
+				l.send(ctx, es.BuildPollRequest())
+			}
+		}
+	}
+}
+
+func (l *ChangeNotificationListener) send(ctx context.Context, notification es.ChangeNotification) {
+	select {
+	case <-ctx.Done():
+	case l.notifications <- notification:
+	}
+}
+
+func (l *ChangeNotificationListener) unmarshalChangeNotification(payload string) es.ChangeNotification {
+	notification, err := es.UnmarshalChangeNotification(payload)
+	if err != nil {
+		l.logger.Warnf("changeNotificationListener: invalid notification [%s]", payload)
+		return es.BuildPollRequest()
+	}
+
+	return notification
+}
@@ -1,8 +1,8 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"math"
 	"strings"
 
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
@@ -11,43 +11,42 @@ import (
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
-	"github.com/lib/pq"
 )
 
 const streamPrefix = "customer"
 
+// CustomerEventStore adds the Customer specifics to es.SQLEventStore:
+// the stream IDs of Customers and the unique email addresses, which are asserted in the same transaction.
+// It works with every es.SQLDialect, the SQLite adapter only contributes its Dialect.
 type CustomerEventStore struct {
 	db                                *sql.DB
-	eventStoreTableName               string
-	marshalDomainEvent                es.MarshalDomainEvent
-	unmarshalDomainEvent              es.UnmarshalDomainEvent
+	dialect                           es.SQLDialect
+	eventStore                        *es.SQLEventStore
 	uniqueEmailAddressesTableName     string
 	buildUniqueEmailAddressAssertions customer.ForBuildingUniqueEmailAddressAssertions
 }
 
 func NewCustomerEventStore(
 	db *sql.DB,
-	eventStoreTableName string,
-	marshalDomainEvent es.MarshalDomainEvent,
-	unmarshalDomainEvent es.UnmarshalDomainEvent,
+	dialect es.SQLDialect,
+	eventStore *es.SQLEventStore,
 	uniqueEmailAddressesTableName string,
 	buildUniqueEmailAddressAssertions customer.ForBuildingUniqueEmailAddressAssertions,
 ) *CustomerEventStore {
 
 	return &CustomerEventStore{
 		db:                                db,
-		eventStoreTableName:               eventStoreTableName,
-		marshalDomainEvent:                marshalDomainEvent,
-		unmarshalDomainEvent:              unmarshalDomainEvent,
+		dialect:                           dialect,
+		eventStore:                        eventStore,
 		uniqueEmailAddressesTableName:     uniqueEmailAddressesTableName,
 		buildUniqueEmailAddressAssertions: buildUniqueEmailAddressAssertions,
 	}
 }
 
-func (s *CustomerEventStore) RetrieveEventStream(id value.CustomerID) (es.EventStream, error) {
+func (s *CustomerEventStore) RetrieveEventStream(ctx context.Context, id value.CustomerID) (es.EventStream, error) {
 	wrapWithMsg := "customerEventStore.RetrieveEventStream"
 
-	eventStream, err := s.loadEventStream(s.streamID(id), 0, math.MaxUint32)
+	eventStream, err := s.eventStore.LoadEventStream(ctx, s.streamID(id))
 	if err != nil {
 		return nil, errors.Wrap(err, wrapWithMsg)
 	}
@@ -60,226 +59,187 @@ func (s *CustomerEventStore) RetrieveEventStream(id value.CustomerID) (es.EventS
 	return eventStream, nil
 }
 
-func (s *CustomerEventStore) StartEventStream(customerRegistered domain.CustomerRegistered) error {
-	var err error
-	wrapWithMsg := "customerEventStore.StartEventStream"
+func (s *CustomerEventStore) RetrieveFullEventStream(ctx context.Context, id value.CustomerID) (es.EventStream, error) {
+	wrapWithMsg := "customerEventStore.RetrieveFullEventStream"
 
-	tx, err := s.db.Begin()
+	eventStream, err := s.eventStore.LoadFullEventStream(ctx, s.streamID(id))
 	if err != nil {
-		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		return nil, errors.Wrap(err, wrapWithMsg)
 	}
 
-	assertionsForUniqueEmailAddresses := s.buildUniqueEmailAddressAssertions(customerRegistered)
-
-	if err = s.assertUniqueEmailAddress(assertionsForUniqueEmailAddresses, tx); err != nil {
-		_ = tx.Rollback()
-
-		return errors.Wrap(err, wrapWithMsg)
+	if len(eventStream) == 0 {
+		err := errors.New("customer not found")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
 	}
 
-	if err = s.appendEventsToStream(tx, s.streamID(customerRegistered.CustomerID()), customerRegistered); err != nil {
-		_ = tx.Rollback()
+	return eventStream, nil
+}
 
-		if errors.Is(err, shared.ErrConcurrencyConflict) {
-			return shared.MarkAndWrapError(errors.New("found duplicate customer"), shared.ErrDuplicate, wrapWithMsg)
-		}
+// RetrieveEventStreamRange returns an empty stream (not ErrNotFound) if fromVersion is beyond the latest event.
+func (s *CustomerEventStore) RetrieveEventStreamRange(
+	ctx context.Context,
+	id value.CustomerID,
+	fromVersion uint,
+	maxEvents uint,
+) (es.EventStream, error) {
 
-		return errors.Wrap(err, wrapWithMsg)
+	wrapWithMsg := "customerEventStore.RetrieveEventStreamRange"
+
+	eventStream, err := s.eventStore.LoadEventStreamRange(ctx, s.streamID(id), fromVersion, maxEvents)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
 	}
 
-	if err = tx.Commit(); err != nil {
-		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	if len(eventStream) == 0 && fromVersion <= 1 {
+		err := errors.New("customer not found")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
 	}
 
-	return nil
+	return eventStream, nil
 }
 
-func (s *CustomerEventStore) AppendToEventStream(recordedEvents es.RecordedEvents, id value.CustomerID) error {
-	var err error
-	wrapWithMsg := "customerEventStore.AppendToEventStream"
-
-	tx, err := s.db.Begin()
-	if err != nil {
-		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
-	}
-
-	assertionsForUniqueEmailAddresses := s.buildUniqueEmailAddressAssertions(recordedEvents...)
+func (s *CustomerEventStore) StartEventStream(
+	ctx context.Context,
+	customerRegistered domain.CustomerRegistered,
+) error {
 
-	if err = s.assertUniqueEmailAddress(assertionsForUniqueEmailAddresses, tx); err != nil {
-		_ = tx.Rollback()
+	wrapWithMsg := "customerEventStore.StartEventStream"
 
-		return errors.Wrap(err, wrapWithMsg)
-	}
+	err := s.eventStore.AppendEventsToStream(
+		ctx,
+		s.streamID(customerRegistered.CustomerID()),
+		es.RecordedEvents{customerRegistered},
+		s.assertUniqueEmailAddresses(customerRegistered),
+	)
 
-	if err = s.appendEventsToStream(tx, s.streamID(id), recordedEvents...); err != nil {
-		_ = tx.Rollback()
+	if err != nil {
+		if errors.Is(err, shared.ErrConcurrencyConflict) {
+			return shared.MarkAndWrapError(errors.New("found duplicate customer"), shared.ErrDuplicate, wrapWithMsg)
+		}
 
 		return errors.Wrap(err, wrapWithMsg)
 	}
 
-	if err = tx.Commit(); err != nil {
-		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
-	}
-
 	return nil
 }
 
-func (s *CustomerEventStore) PurgeEventStream(id value.CustomerID) error {
-	var err error
-	wrapWithMsg := "customerEventStore.PurgeEventStream"
+func (s *CustomerEventStore) AppendToEventStream(
+	ctx context.Context,
+	recordedEvents es.RecordedEvents,
+	id value.CustomerID,
+) error {
+
+	err := s.eventStore.AppendEventsToStream(
+		ctx,
+		s.streamID(id),
+		recordedEvents,
+		s.assertUniqueEmailAddresses(recordedEvents...),
+	)
 
-	tx, err := s.db.Begin()
 	if err != nil {
-		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		return errors.Wrap(err, "customerEventStore.AppendToEventStream")
 	}
 
-	if err = s.clearUniqueEmailAddress(id, tx); err != nil {
-		_ = tx.Rollback()
-
-		return errors.Wrap(err, wrapWithMsg)
-	}
+	return nil
+}
 
-	if err = tx.Commit(); err != nil {
-		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
-	}
+func (s *CustomerEventStore) PurgeEventStream(ctx context.Context, id value.CustomerID) error {
+	err := s.eventStore.PurgeEventStream(
+		ctx,
+		s.streamID(id),
+		func(ctx context.Context, tx *sql.Tx) error {
+			return s.clearUniqueEmailAddress(ctx, id, tx)
+		},
+	)
 
-	if err := s.purgeEventStream(s.streamID(id)); err != nil {
-		return errors.Wrap(err, wrapWithMsg)
+	if err != nil {
+		return errors.Wrap(err, "customerEventStore.PurgeEventStream")
 	}
 
 	return nil
 }
 
-func (s *CustomerEventStore) streamID(id value.CustomerID) es.StreamID {
-	return es.NewStreamID(streamPrefix + "-" + id.String())
-}
-
-/***** local methods for reading from and writing to the event store *****/
-
-func (s *CustomerEventStore) loadEventStream(
-	streamID es.StreamID,
-	fromVersion uint,
-	maxEvents uint,
-) (es.EventStream, error) {
-
-	var err error
-	wrapWithMsg := "loadEventStream"
-
-	queryTemplate := `SELECT event_name, payload, stream_version FROM %name% 
-						WHERE stream_id = $1 AND stream_version >= $2
-						ORDER BY stream_version ASC
-						LIMIT $3`
-
-	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
-
-	eventRows, err := s.db.Query(query, streamID.String(), fromVersion, maxEvents)
-	if err != nil {
-		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
-	}
+func (s *CustomerEventStore) RetrieveCustomerIDByEmailAddress(
+	ctx context.Context,
+	emailAddress value.EmailAddress,
+) (value.CustomerID, error) {
 
-	var eventStream es.EventStream
-	var eventName string
-	var payload string
-	var streamVersion uint
-	var domainEvent es.DomainEvent
+	wrapWithMsg := "customerEventStore.RetrieveCustomerIDByEmailAddress"
 
-	for eventRows.Next() {
-		if eventRows.Err() != nil {
-			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
-		}
+	queryTemplate := `SELECT customer_id FROM %tablename% WHERE email_address = $1`
+	query := strings.Replace(queryTemplate, "%tablename%", s.uniqueEmailAddressesTableName, 1)
 
-		if err = eventRows.Scan(&eventName, &payload, &streamVersion); err != nil {
-			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
-		}
+	var customerID string
 
-		if domainEvent, err = s.unmarshalDomainEvent(eventName, []byte(payload), streamVersion); err != nil {
-			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
-		}
+	err := s.db.QueryRowContext(ctx, query, emailAddress.Canonical()).Scan(&customerID)
 
-		eventStream = append(eventStream, domainEvent)
+	switch {
+	case err == sql.ErrNoRows:
+		err := errors.New("customer not found")
+		return value.CustomerID{}, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	case err != nil:
+		return value.CustomerID{}, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
 	}
 
-	return eventStream, nil
+	return value.RebuildCustomerID(customerID), nil
 }
 
-func (s *CustomerEventStore) appendEventsToStream(
-	tx *sql.Tx,
-	streamID es.StreamID,
-	events ...es.DomainEvent,
-) error {
-
-	var err error
-	wrapWithMsg := "appendEventsToStream"
-
-	queryTemplate := `INSERT INTO %name% (stream_id, stream_version, event_name, occurred_at, payload)
-						VALUES ($1, $2, $3, $4, $5)`
-	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
+func (s *CustomerEventStore) ReadGlobalEvents(
+	ctx context.Context,
+	afterPosition uint64,
+	maxEvents uint,
+) ([]es.GlobalEvent, error) {
 
-	for _, event := range events {
-		var eventJson []byte
+	return s.eventStore.ReadGlobalEvents(ctx, afterPosition, maxEvents)
+}
 
-		eventJson, err = s.marshalDomainEvent(event)
-		if err != nil {
-			return shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
-		}
+func (s *CustomerEventStore) ReadOutboxMessages(
+	ctx context.Context,
+	afterID uint64,
+	maxMessages uint,
+) ([]es.OutboxMessage, error) {
 
-		_, err = tx.Exec(
-			query,
-			streamID.String(),
-			event.Meta().StreamVersion(),
-			event.Meta().EventName(),
-			event.Meta().OccurredAt(),
-			eventJson,
-		)
-
-		if err != nil {
-			return errors.Wrap(s.mapEventStorePostgresErrors(err), wrapWithMsg)
-		}
-	}
+	return s.eventStore.ReadOutboxMessages(ctx, afterID, maxMessages)
+}
 
-	return nil
+func (s *CustomerEventStore) MarkOutboxMessageAsPublished(ctx context.Context, id uint64) error {
+	return s.eventStore.MarkOutboxMessageAsPublished(ctx, id)
 }
 
-func (s *CustomerEventStore) purgeEventStream(streamID es.StreamID) error {
-	queryTemplate := `DELETE FROM %name% WHERE stream_id = $1`
-	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
+func (s *CustomerEventStore) streamID(id value.CustomerID) es.StreamID {
+	return es.NewStreamID(streamPrefix + "-" + id.String())
+}
 
-	if _, err := s.db.Exec(query, streamID.String()); err != nil {
-		return shared.MarkAndWrapError(err, shared.ErrTechnical, "purgeEventStream")
-	}
+/***** local methods for asserting unique email addresses *****/
 
-	return nil
-}
+func (s *CustomerEventStore) assertUniqueEmailAddresses(recordedEvents ...es.DomainEvent) es.SQLPreCommitHook {
+	assertions := s.buildUniqueEmailAddressAssertions(recordedEvents...)
 
-func (s *CustomerEventStore) mapEventStorePostgresErrors(err error) error {
-	switch actualErr := err.(type) {
-	case *pq.Error:
-		switch actualErr.Code {
-		case "23505":
-			return errors.Mark(err, shared.ErrConcurrencyConflict)
-		}
+	return func(ctx context.Context, tx *sql.Tx) error {
+		return s.assertUniqueEmailAddress(ctx, assertions, tx)
 	}
-
-	return errors.Mark(err, shared.ErrTechnical) // some other DB error (Tx closed, wrong table, ...)
 }
 
-/***** local methods for asserting unique email addresses *****/
+func (s *CustomerEventStore) assertUniqueEmailAddress(
+	ctx context.Context,
+	assertions customer.UniqueEmailAddressAssertions,
+	tx *sql.Tx,
+) error {
 
-func (s *CustomerEventStore) assertUniqueEmailAddress(assertions customer.UniqueEmailAddressAssertions, tx *sql.Tx) error {
 	wrapWithMsg := "assertUniqueEmailAddresse"
 
 	for _, assertion := range assertions {
 		switch assertion.DesiredAction() {
 		case customer.ShouldAddUniqueEmailAddress:
-			if err := s.tryToAdd(assertion.EmailAddressToAdd(), assertion.CustomerID(), tx); err != nil {
+			if err := s.tryToAdd(ctx, assertion.EmailAddressToAdd(), assertion.CustomerID(), tx); err != nil {
 				return errors.Wrap(err, wrapWithMsg)
 			}
 		case customer.ShouldReplaceUniqueEmailAddress:
-			if err := s.tryToReplace(assertion.EmailAddressToRemove(), assertion.EmailAddressToAdd(), tx); err != nil {
+			if err := s.tryToReplace(ctx, assertion.EmailAddressToRemove(), assertion.EmailAddressToAdd(), tx); err != nil {
 				return errors.Wrap(err, wrapWithMsg)
 			}
 		case customer.ShouldRemoveUniqueEmailAddress:
-			if err := s.remove(assertion.EmailAddressToRemove(), tx); err != nil {
+			if err := s.remove(ctx, assertion.EmailAddressToRemove(), tx); err != nil {
 				return errors.Wrap(err, wrapWithMsg)
 			}
 		}
@@ -288,23 +248,30 @@ func (s *CustomerEventStore) assertUniqueEmailAddress(assertions customer.Unique
 	return nil
 }
 
-func (s *CustomerEventStore) clearUniqueEmailAddress(customerID value.CustomerID, tx *sql.Tx) error {
+func (s *CustomerEventStore) clearUniqueEmailAddress(
+	ctx context.Context,
+	customerID value.CustomerID,
+	tx *sql.Tx,
+) error {
+
 	queryTemplate := `DELETE FROM %tablename% WHERE customer_id = $1`
 	query := strings.Replace(queryTemplate, "%tablename%", s.uniqueEmailAddressesTableName, 1)
 
-	_, err := tx.Exec(
+	_, err := tx.ExecContext(
+		ctx,
 		query,
 		customerID.String(),
 	)
 
 	if err != nil {
-		return s.mapUniqueEmailAddressPostgresErrors(err)
+		return s.mapUniqueEmailAddressErrors(err)
 	}
 
 	return nil
 }
 
 func (s *CustomerEventStore) tryToAdd(
+	ctx context.Context,
 	emailAddress value.EmailAddress,
 	customerID value.CustomerID,
 	tx *sql.Tx,
@@ -313,20 +280,22 @@ func (s *CustomerEventStore) tryToAdd(
 	queryTemplate := `INSERT INTO %tablename% VALUES ($1, $2)`
 	query := strings.Replace(queryTemplate, "%tablename%", s.uniqueEmailAddressesTableName, 1)
 
-	_, err := tx.Exec(
+	_, err := tx.ExecContext(
+		ctx,
 		query,
-		emailAddress.String(),
+		emailAddress.Canonical(),
 		customerID.String(),
 	)
 
 	if err != nil {
-		return s.mapUniqueEmailAddressPostgresErrors(err)
+		return s.mapUniqueEmailAddressErrors(err)
 	}
 
 	return nil
 }
 
 func (s *CustomerEventStore) tryToReplace(
+	ctx context.Context,
 	previousEmailAddress value.EmailAddress,
 	newEmailAddress value.EmailAddress,
 	tx *sql.Tx,
@@ -335,20 +304,22 @@ func (s *CustomerEventStore) tryToReplace(
 	queryTemplate := `UPDATE %tablename% set email_address = $1 where email_address = $2`
 	query := strings.Replace(queryTemplate, "%tablename%", s.uniqueEmailAddressesTableName, 1)
 
-	_, err := tx.Exec(
+	_, err := tx.ExecContext(
+		ctx,
 		query,
-		newEmailAddress.String(),
-		previousEmailAddress.String(),
+		newEmailAddress.Canonical(),
+		previousEmailAddress.Canonical(),
 	)
 
 	if err != nil {
-		return s.mapUniqueEmailAddressPostgresErrors(err)
+		return s.mapUniqueEmailAddressErrors(err)
 	}
 
 	return nil
 }
 
 func (s *CustomerEventStore) remove(
+	ctx context.Context,
 	newEmailAddress value.EmailAddress,
 	tx *sql.Tx,
 ) error {
@@ -356,25 +327,22 @@ func (s *CustomerEventStore) remove(
 	queryTemplate := `DELETE FROM %tablename% where email_address = $1`
 	query := strings.Replace(queryTemplate, "%tablename%", s.uniqueEmailAddressesTableName, 1)
 
-	_, err := tx.Exec(
+	_, err := tx.ExecContext(
+		ctx,
 		query,
-		newEmailAddress.String(),
+		newEmailAddress.Canonical(),
 	)
 
 	if err != nil {
-		return s.mapUniqueEmailAddressPostgresErrors(err)
+		return s.mapUniqueEmailAddressErrors(err)
 	}
 
 	return nil
 }
 
-func (s *CustomerEventStore) mapUniqueEmailAddressPostgresErrors(err error) error {
-	switch actualErr := err.(type) {
-	case *pq.Error:
-		switch actualErr.Code {
-		case "23505":
-			return errors.Mark(errors.Newf("duplicate email address"), shared.ErrDuplicate)
-		}
+func (s *CustomerEventStore) mapUniqueEmailAddressErrors(err error) error {
+	if s.dialect.IsUniqueViolation(err) {
+		return errors.Mark(errors.Newf("duplicate email address"), shared.ErrDuplicate)
 	}
 
 	return errors.Mark(err, shared.ErrTechnical) // some other DB error (Tx closed, wrong table, ...)
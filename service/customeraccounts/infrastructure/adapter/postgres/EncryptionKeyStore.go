@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+// EncryptionKeyStore holds one key per subject for crypto-shredding.
+// A shredded key is set to NULL but its row is kept, so that no new key can be created for the subject.
+// Keys are read and written inside the transaction which ctx carries, if any (see es.ContextWithSQLTx).
+// It works with Postgres and SQLite.
+type EncryptionKeyStore struct {
+	db        *sql.DB
+	tableName string
+}
+
+func NewEncryptionKeyStore(db *sql.DB, tableName string) *EncryptionKeyStore {
+	return &EncryptionKeyStore{
+		db:        db,
+		tableName: tableName,
+	}
+}
+
+// RetrieveOrCreateEncryptionKey only writes if there is no key yet,
+// because SQLite has a single writer and readers would otherwise wait for each other.
+func (s *EncryptionKeyStore) RetrieveOrCreateEncryptionKey(ctx context.Context, subjectID string) ([]byte, error) {
+	wrapWithMsg := "encryptionKeyStore.RetrieveOrCreateEncryptionKey"
+
+	key, found, err := s.retrieveKey(ctx, subjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if found {
+		if key == nil {
+			err = errors.New("encryption key was shredded")
+			return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+		}
+
+		return key, nil
+	}
+
+	newKey, err := es.GenerateEncryptionKey()
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	// a concurrent insert for the same subject wins, so both callers get the same key
+	queryTemplate := `INSERT INTO %name% (subject_id, key, created_at) VALUES ($1, $2, $3)
+						ON CONFLICT (subject_id) DO NOTHING`
+	query := strings.Replace(queryTemplate, "%name%", s.tableName, 1)
+
+	if _, err = es.SQLTxOrDB(ctx, s.db).ExecContext(ctx, query, subjectID, newKey, time.Now()); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	key, err = s.RetrieveEncryptionKey(ctx, subjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	return key, nil
+}
+
+func (s *EncryptionKeyStore) RetrieveEncryptionKey(ctx context.Context, subjectID string) ([]byte, error) {
+	wrapWithMsg := "encryptionKeyStore.RetrieveEncryptionKey"
+
+	key, found, err := s.retrieveKey(ctx, subjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if !found || key == nil {
+		err = errors.New("encryption key not found or shredded")
+		return nil, shared.MarkAndWrapError(err, shared.ErrNotFound, wrapWithMsg)
+	}
+
+	return key, nil
+}
+
+func (s *EncryptionKeyStore) ShredEncryptionKey(ctx context.Context, subjectID string) error {
+	queryTemplate := `INSERT INTO %name% (subject_id, key, created_at, shredded_at) VALUES ($1, NULL, $2, $2)
+						ON CONFLICT (subject_id) DO UPDATE SET key = NULL, shredded_at = excluded.shredded_at`
+	query := strings.Replace(queryTemplate, "%name%", s.tableName, 1)
+
+	if _, err := es.SQLTxOrDB(ctx, s.db).ExecContext(ctx, query, subjectID, time.Now()); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, "encryptionKeyStore.ShredEncryptionKey")
+	}
+
+	return nil
+}
+
+func (s *EncryptionKeyStore) retrieveKey(ctx context.Context, subjectID string) ([]byte, bool, error) {
+	queryTemplate := `SELECT key FROM %name% WHERE subject_id = $1`
+	query := strings.Replace(queryTemplate, "%name%", s.tableName, 1)
+
+	var key []byte
+
+	err := es.SQLTxOrDB(ctx, s.db).QueryRowContext(ctx, query, subjectID).Scan(&key)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, false, nil
+	case err != nil:
+		return nil, false, shared.MarkAndWrapError(err, shared.ErrTechnical, "retrieveKey")
+	}
+
+	return key, true, nil
+}
@@ -0,0 +1,46 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	_ "github.com/lib/pq"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestComputeEventHash_WithJSONBRoundTrip(t *testing.T) {
+	db, err := sql.Open("postgres", os.Getenv("POSTGRES_DSN"))
+	if err == nil {
+		err = db.Ping()
+	}
+
+	if err != nil {
+		t.Skipf("needs a Postgres DB in POSTGRES_DSN: %s", err)
+	}
+
+	defer db.Close()
+
+	Convey("Given a payload with numbers which JSONB writes differently", t, func() {
+		streamID := es.NewStreamID("customer-123")
+		marshaled := `{"a":1e2,"b":1.50,"c":1.5e-1,"d":-0,"e":1.50E+1,"f":[0.001e1,-12E1,7],"g":"é"}`
+
+		Convey("When it is round-tripped through JSONB", func() {
+			var fromJSONB string
+
+			err := db.QueryRow(`SELECT $1::jsonb::text`, marshaled).Scan(&fromJSONB)
+			So(err, ShouldBeNil)
+
+			Convey("Then the hashes of both should be equal", func() {
+				hashOfMarshaled, err := es.ComputeEventHash("", streamID, 1, "SomeEvent", es.ContentTypeJSON, []byte(marshaled))
+				So(err, ShouldBeNil)
+
+				hashFromJSONB, err := es.ComputeEventHash("", streamID, 1, "SomeEvent", es.ContentTypeJSON, []byte(fromJSONB))
+				So(err, ShouldBeNil)
+
+				So(hashFromJSONB, ShouldEqual, hashOfMarshaled)
+			})
+		})
+	})
+}
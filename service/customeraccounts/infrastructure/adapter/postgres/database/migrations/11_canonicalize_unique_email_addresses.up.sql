@@ -0,0 +1,38 @@
+BEGIN;
+
+-- Email addresses are unique by their canonical form (trimmed and lowercased, see value.EmailAddress) from now on.
+-- Rows which can't be canonicalized here are kept as they are and their Customers are listed in
+-- unique_email_address_conflicts, they must be resolved manually: different Customers whose addresses only differ
+-- in case (collision), and domains with non-ASCII characters, which must be converted to punycode (non-ascii).
+-- Only the customer IDs are listed, so that forgetting a Customer leaves no plaintext email address behind.
+CREATE TABLE IF NOT EXISTS unique_email_address_conflicts
+(
+    customer_id varchar(255) not null,
+    reason varchar(16) not null,
+    CONSTRAINT unique_email_address_conflicts_pk
+        PRIMARY KEY (customer_id, reason)
+);
+
+INSERT INTO unique_email_address_conflicts (customer_id, reason)
+    SELECT customer_id, 'collision'
+    FROM unique_email_addresses
+    WHERE lower(trim(email_address)) IN (
+        SELECT lower(trim(email_address))
+        FROM unique_email_addresses
+        GROUP BY lower(trim(email_address))
+        HAVING count(*) > 1
+    )
+ON CONFLICT DO NOTHING;
+
+INSERT INTO unique_email_address_conflicts (customer_id, reason)
+    SELECT customer_id, 'non-ascii'
+    FROM unique_email_addresses
+    WHERE email_address ~ '[^[:ascii:]]'
+ON CONFLICT DO NOTHING;
+
+UPDATE unique_email_addresses
+    SET email_address = lower(trim(email_address))
+    WHERE email_address <> lower(trim(email_address))
+        AND customer_id NOT IN (SELECT customer_id FROM unique_email_address_conflicts);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id bigserial
+        CONSTRAINT outbox_pk
+            PRIMARY KEY,
+    stream_id varchar(255) not null,
+    stream_version integer not null,
+    event_name varchar(255) not null,
+    occurred_at timestamp with time zone not null,
+    payload jsonb default '{}'::jsonb not null
+);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE eventstore
+    ADD COLUMN IF NOT EXISTS event_id uuid,
+    ADD COLUMN IF NOT EXISTS correlation_id varchar(255),
+    ADD COLUMN IF NOT EXISTS causation_id varchar(255),
+    ADD COLUMN IF NOT EXISTS actor varchar(255);
+
+CREATE INDEX IF NOT EXISTS correlation_id_idx
+    on eventstore (correlation_id);
+
+COMMIT;
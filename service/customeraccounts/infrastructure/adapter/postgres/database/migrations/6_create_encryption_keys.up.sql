@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS encryption_keys
+(
+    subject_id varchar(255) not null
+        CONSTRAINT encryption_keys_pk
+            PRIMARY KEY,
+    key bytea,
+    created_at timestamp with time zone not null,
+    shredded_at timestamp with time zone
+);
+
+COMMIT;
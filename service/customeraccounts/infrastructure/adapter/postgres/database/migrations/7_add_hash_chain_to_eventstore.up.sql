@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE eventstore
+    ADD COLUMN IF NOT EXISTS event_hash char(64);
+
+COMMIT;
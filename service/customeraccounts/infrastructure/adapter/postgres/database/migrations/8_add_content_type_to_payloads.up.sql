@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE eventstore
+    ADD COLUMN IF NOT EXISTS content_type varchar(64) default 'application/json' not null,
+    ADD COLUMN IF NOT EXISTS payload_binary bytea;
+
+ALTER TABLE snapshots
+    ADD COLUMN IF NOT EXISTS content_type varchar(64) default 'application/json' not null,
+    ADD COLUMN IF NOT EXISTS payload_binary bytea;
+
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS content_type varchar(64) default 'application/json' not null,
+    ADD COLUMN IF NOT EXISTS payload_binary bytea;
+
+COMMIT;
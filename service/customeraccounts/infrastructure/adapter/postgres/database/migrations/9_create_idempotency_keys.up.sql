@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    idempotency_key varchar(255) not null
+        CONSTRAINT idempotency_keys_pk
+            PRIMARY KEY,
+    fingerprint char(64) not null,
+    result varchar(255),
+    expires_at bigint not null
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx
+    on idempotency_keys (expires_at);
+
+COMMIT;
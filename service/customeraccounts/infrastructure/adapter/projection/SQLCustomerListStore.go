@@ -0,0 +1,262 @@
+package projection
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+const customerListProjectionName = "customer_list"
+
+var customerListOrderByColumns = map[string]string{
+	application.CustomerListOrderByRegisteredAt: "registered_at",
+	application.CustomerListOrderByEmailAddress: "email_address",
+	application.CustomerListOrderByFamilyName:   "family_name",
+}
+
+// SQLCustomerListStore holds the Customer list projection, it works with Postgres and SQLite.
+// The registration times are stored as Unix nanoseconds, so that both can sort and compare them the same way.
+type SQLCustomerListStore struct {
+	db                  *sql.DB
+	tableName           string
+	checkpointTableName string
+}
+
+func NewSQLCustomerListStore(db *sql.DB, tableName string, checkpointTableName string) *SQLCustomerListStore {
+	return &SQLCustomerListStore{
+		db:                  db,
+		tableName:           tableName,
+		checkpointTableName: checkpointTableName,
+	}
+}
+
+func (s *SQLCustomerListStore) SaveCustomerListView(
+	ctx context.Context,
+	view customer.ListView,
+	globalPosition uint64,
+) error {
+
+	wrapWithMsg := "sqlCustomerListStore.SaveCustomerListView"
+
+	registeredAt, err := registeredAtToUnixNano(view.RegisteredAt)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	queryTemplate := `INSERT INTO %name% (id, email_address, is_email_address_confirmed, given_name, family_name,
+							is_deleted, registered_at, version)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+						ON CONFLICT (id) DO UPDATE SET email_address = excluded.email_address,
+							is_email_address_confirmed = excluded.is_email_address_confirmed,
+							given_name = excluded.given_name, family_name = excluded.family_name,
+							is_deleted = excluded.is_deleted, version = excluded.version
+						WHERE %name%.version < excluded.version AND NOT %name%.is_forgotten`
+	query := strings.Replace(queryTemplate, "%name%", s.tableName, -1)
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		view.ID,
+		view.EmailAddress,
+		view.IsEmailAddressConfirmed,
+		view.GivenName,
+		view.FamilyName,
+		view.IsDeleted,
+		registeredAt,
+		view.Version,
+	)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	checkpointQueryTemplate := `INSERT INTO %name% (projection_name, checkpoint) VALUES ($1, $2)
+						ON CONFLICT (projection_name) DO UPDATE SET checkpoint = excluded.checkpoint`
+	checkpointQuery := strings.Replace(checkpointQueryTemplate, "%name%", s.checkpointTableName, 1)
+
+	if _, err = tx.ExecContext(ctx, checkpointQuery, customerListProjectionName, globalPosition); err != nil {
+		_ = tx.Rollback()
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return nil
+}
+
+// ForgetCustomerListView redacts the personal data of a forgotten Customer, which the projection has stored
+// in plain text. A forgotten view is never replaced, in case the projection has loaded it before the key was shredded.
+func (s *SQLCustomerListStore) ForgetCustomerListView(ctx context.Context, customerID string) error {
+	queryTemplate := `UPDATE %name% SET email_address = $1, given_name = $1, family_name = $1,
+							is_deleted = $2, is_forgotten = $2
+						WHERE id = $3`
+	query := strings.Replace(queryTemplate, "%name%", s.tableName, 1)
+
+	if _, err := s.db.ExecContext(ctx, query, es.RedactedPII, true, customerID); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, "sqlCustomerListStore.ForgetCustomerListView")
+	}
+
+	return nil
+}
+
+// CustomerListCheckpoint is the global position of the last projected event, 0 if none was projected yet.
+func (s *SQLCustomerListStore) CustomerListCheckpoint(ctx context.Context) (uint64, error) {
+	queryTemplate := `SELECT checkpoint FROM %name% WHERE projection_name = $1`
+	query := strings.Replace(queryTemplate, "%name%", s.checkpointTableName, 1)
+
+	var checkpoint uint64
+
+	err := s.db.QueryRowContext(ctx, query, customerListProjectionName).Scan(&checkpoint)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, nil
+	case err != nil:
+		return 0, shared.MarkAndWrapError(err, shared.ErrTechnical, "sqlCustomerListStore.CustomerListCheckpoint")
+	}
+
+	return checkpoint, nil
+}
+
+func (s *SQLCustomerListStore) RetrieveCustomerListViews(
+	ctx context.Context,
+	query application.CustomerListQuery,
+) ([]customer.ListView, error) {
+
+	wrapWithMsg := "sqlCustomerListStore.RetrieveCustomerListViews"
+
+	var conditions []string
+	var args []interface{}
+
+	placeholder := func(arg interface{}) string {
+		args = append(args, arg)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	orderByColumn := customerListOrderByColumns[query.OrderBy]
+	comparison, direction := ">", "ASC"
+
+	if query.Descending {
+		comparison, direction = "<", "DESC"
+	}
+
+	if query.After != nil {
+		sortKey := interface{}(query.After.SortKey)
+
+		if query.OrderBy == application.CustomerListOrderByRegisteredAt {
+			registeredAt, err := registeredAtToUnixNano(query.After.SortKey)
+			if err != nil {
+				return nil, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+			}
+
+			sortKey = registeredAt
+		}
+
+		conditions = append(
+			conditions,
+			"("+orderByColumn+", id) "+comparison+" ("+placeholder(sortKey)+", "+placeholder(query.After.CustomerID)+")",
+		)
+	}
+
+	if query.IsEmailAddressConfirmed != nil {
+		conditions = append(conditions, "is_email_address_confirmed = "+placeholder(*query.IsEmailAddressConfirmed))
+	}
+
+	if query.IsDeleted != nil {
+		conditions = append(conditions, "is_deleted = "+placeholder(*query.IsDeleted))
+	}
+
+	if !query.RegisteredFrom.IsZero() {
+		conditions = append(conditions, "registered_at >= "+placeholder(query.RegisteredFrom.UnixNano()))
+	}
+
+	if !query.RegisteredTo.IsZero() {
+		conditions = append(conditions, "registered_at < "+placeholder(query.RegisteredTo.UnixNano()))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	queryTemplate := `SELECT id, email_address, is_email_address_confirmed, given_name, family_name, is_deleted,
+							registered_at, version
+						FROM %name% %where%
+						ORDER BY %orderBy% %direction%, id %direction%
+						LIMIT %limit%`
+
+	sqlQuery := strings.NewReplacer(
+		"%name%", s.tableName,
+		"%where%", where,
+		"%orderBy%", orderByColumn,
+		"%direction%", direction,
+		"%limit%", placeholder(query.Limit),
+	).Replace(queryTemplate)
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	defer rows.Close()
+
+	var views []customer.ListView
+
+	for rows.Next() {
+		var view customer.ListView
+		var registeredAt int64
+
+		err = rows.Scan(
+			&view.ID,
+			&view.EmailAddress,
+			&view.IsEmailAddressConfirmed,
+			&view.GivenName,
+			&view.FamilyName,
+			&view.IsDeleted,
+			&registeredAt,
+			&view.Version,
+		)
+
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+
+		view.RegisteredAt = time.Unix(0, registeredAt).UTC().Format(time.RFC3339Nano)
+		views = append(views, view)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return views, nil
+}
+
+func registeredAtToUnixNano(registeredAt string) (int64, error) {
+	if registeredAt == "" {
+		return 0, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, registeredAt)
+	if err != nil {
+		return 0, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, "registeredAtToUnixNano")
+	}
+
+	return parsed.UnixNano(), nil
+}
@@ -0,0 +1,33 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+// ChannelPublisher delivers messages to an in-process Go channel, e.g. for tests.
+// It never blocks: if the channel is full, publishing fails and the relay will retry it later.
+type ChannelPublisher struct {
+	messages chan es.OutboxMessage
+}
+
+func NewChannelPublisher(bufferSize uint) *ChannelPublisher {
+	return &ChannelPublisher{messages: make(chan es.OutboxMessage, bufferSize)}
+}
+
+func (publisher *ChannelPublisher) Publish(_ context.Context, message es.OutboxMessage) error {
+	select {
+	case publisher.messages <- message:
+		return nil
+	default:
+		err := errors.New("channel is full")
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, "channelPublisher.Publish")
+	}
+}
+
+func (publisher *ChannelPublisher) Messages() <-chan es.OutboxMessage {
+	return publisher.messages
+}
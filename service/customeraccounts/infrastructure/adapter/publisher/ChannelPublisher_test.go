@@ -0,0 +1,36 @@
+package publisher_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/publisher"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChannelPublisher(t *testing.T) {
+	Convey("Given a ChannelPublisher with a buffer size of 1", t, func() {
+		channelPublisher := publisher.NewChannelPublisher(1)
+		message := es.RebuildOutboxMessage(1, es.NewStreamID("customer-123"), "CustomerRegistered", "", 1, es.ContentTypeJSON, []byte("{}"))
+
+		Convey("When a message is published", func() {
+			err := channelPublisher.Publish(context.Background(), message)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be received from the channel", func() {
+				So(<-channelPublisher.Messages(), ShouldResemble, message)
+			})
+
+			Convey("And when another message is published while the channel is full", func() {
+				err = channelPublisher.Publish(context.Background(), message)
+
+				Convey("Then it should fail", func() {
+					So(errors.Is(err, shared.ErrTechnical), ShouldBeTrue)
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+// ConfirmationEmailPublisher sends the confirmation emails for the Customer events before it hands the messages
+// on to the next publisher. The relay retries a message until both have succeeded, so an email can be sent twice.
+type ConfirmationEmailPublisher struct {
+	unmarshalCustomerEvent es.UnmarshalDomainEvent
+	sendConfirmationEmail  hexagon.ForSendingConfirmationEmails
+	next                   es.PublishOutboxMessage
+}
+
+func NewConfirmationEmailPublisher(
+	unmarshalCustomerEvent es.UnmarshalDomainEvent,
+	sendConfirmationEmail hexagon.ForSendingConfirmationEmails,
+	next es.PublishOutboxMessage,
+) *ConfirmationEmailPublisher {
+
+	return &ConfirmationEmailPublisher{
+		unmarshalCustomerEvent: unmarshalCustomerEvent,
+		sendConfirmationEmail:  sendConfirmationEmail,
+		next:                   next,
+	}
+}
+
+func (publisher *ConfirmationEmailPublisher) Publish(ctx context.Context, message es.OutboxMessage) error {
+	wrapWithMsg := "confirmationEmailPublisher.Publish"
+
+	event, err := publisher.unmarshalCustomerEvent(ctx, message.EventName(), message.Payload(), message.StreamVersion())
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	if err = publisher.sendConfirmationEmail(ctx, event); err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	return publisher.next(ctx, message)
+}
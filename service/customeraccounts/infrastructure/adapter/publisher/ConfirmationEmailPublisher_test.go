@@ -0,0 +1,195 @@
+package publisher_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/publisher"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConfirmationEmailPublisher(t *testing.T) {
+	Convey("Given a ConfirmationEmailPublisher with german confirmation emails", t, func() {
+		customerID := value.GenerateCustomerID()
+		emailAddress, err := value.BuildEmailAddress("fiona@gallagher.net")
+		So(err, ShouldBeNil)
+		personName, err := value.BuildPersonName("Fiona", "Gallagher")
+		So(err, ShouldBeNil)
+		confirmationHash := value.GenerateConfirmationHash()
+
+		customerRegistered := domain.BuildCustomerRegistered(
+			customerID,
+			emailAddress,
+			confirmationHash,
+			personName,
+			es.MessageMeta{},
+			1,
+		)
+
+		ctx := context.Background()
+		eventStream := es.EventStream{customerRegistered}
+		var event es.DomainEvent
+		var sentEmails []application.Email
+		var sendingErr error
+		var publishedMessages []es.OutboxMessage
+
+		retrieveCustomerEventStream := func(ctx context.Context, id value.CustomerID) (es.EventStream, error) {
+			return eventStream, nil
+		}
+
+		sendEmail := func(ctx context.Context, email application.Email) error {
+			if sendingErr != nil {
+				return sendingErr
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			sentEmails = append(sentEmails, email)
+
+			return nil
+		}
+
+		unmarshalCustomerEvent := func(ctx context.Context, name string, payload []byte, streamVersion uint) (es.DomainEvent, error) {
+			return event, nil
+		}
+
+		next := func(ctx context.Context, message es.OutboxMessage) error {
+			publishedMessages = append(publishedMessages, message)
+			return nil
+		}
+
+		sender, err := application.NewConfirmationEmailSender(retrieveCustomerEventStream, sendEmail, "de")
+		So(err, ShouldBeNil)
+
+		confirmationEmailPublisher := publisher.NewConfirmationEmailPublisher(
+			unmarshalCustomerEvent,
+			sender.SendConfirmationEmail,
+			next,
+		)
+
+		message := es.RebuildOutboxMessage(
+			1,
+			es.NewStreamID("customer-"+customerID.String()),
+			"CustomerRegistered",
+			"",
+			1,
+			es.ContentTypeJSON,
+			[]byte("{}"),
+		)
+
+		Convey("When the message of a CustomerRegistered event is published", func() {
+			event = customerRegistered
+			err = confirmationEmailPublisher.Publish(ctx, message)
+
+			Convey("Then it should send a confirmation email in german", func() {
+				So(err, ShouldBeNil)
+				So(sentEmails, ShouldHaveLength, 1)
+				So(sentEmails[0].To, ShouldEqual, emailAddress.String())
+				So(sentEmails[0].Subject, ShouldEqual, "Bitte bestätige deine E-Mail-Adresse")
+				So(sentEmails[0].Body, ShouldStartWith, "Hallo Fiona Gallagher,")
+				So(sentEmails[0].Body, ShouldContainSubstring, "Registrierung mit fiona@gallagher.net")
+				So(sentEmails[0].Body, ShouldContainSubstring, customerID.String())
+				So(sentEmails[0].Body, ShouldContainSubstring, confirmationHash.String())
+
+				Convey("and it should hand the message on to the next publisher", func() {
+					So(publishedMessages, ShouldResemble, []es.OutboxMessage{message})
+				})
+			})
+		})
+
+		Convey("When the message of a CustomerEmailAddressChanged event is published", func() {
+			newEmailAddress, err := value.BuildEmailAddress("fiona@milkovich.net")
+			So(err, ShouldBeNil)
+			newConfirmationHash := value.GenerateConfirmationHash()
+
+			event = domain.BuildCustomerEmailAddressChanged(
+				customerID,
+				newEmailAddress,
+				newConfirmationHash,
+				emailAddress,
+				es.MessageMeta{},
+				2,
+			)
+
+			eventStream = append(eventStream, event)
+			err = confirmationEmailPublisher.Publish(ctx, message)
+
+			Convey("Then it should send a confirmation email for the new email address", func() {
+				So(err, ShouldBeNil)
+				So(sentEmails, ShouldHaveLength, 1)
+				So(sentEmails[0].To, ShouldEqual, newEmailAddress.String())
+				So(sentEmails[0].Body, ShouldContainSubstring, "E-Mail-Adresse zu fiona@milkovich.net geändert")
+				So(sentEmails[0].Body, ShouldContainSubstring, newConfirmationHash.String())
+				So(publishedMessages, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the message of a CustomerRegistered event is published after the email address was confirmed", func() {
+			event = customerRegistered
+			eventStream = append(
+				eventStream,
+				domain.BuildCustomerEmailAddressConfirmed(customerID, emailAddress, es.MessageMeta{}, 2),
+			)
+
+			err = confirmationEmailPublisher.Publish(ctx, message)
+
+			Convey("Then it should not send a confirmation email", func() {
+				So(err, ShouldBeNil)
+				So(sentEmails, ShouldBeEmpty)
+				So(publishedMessages, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the message of an event without confirmation is published", func() {
+			event = domain.BuildCustomerEmailAddressConfirmed(customerID, emailAddress, es.MessageMeta{}, 2)
+			err = confirmationEmailPublisher.Publish(ctx, message)
+
+			Convey("Then it should not send a confirmation email", func() {
+				So(err, ShouldBeNil)
+				So(sentEmails, ShouldBeEmpty)
+				So(publishedMessages, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the confirmation email can't be sent", func() {
+			event = customerRegistered
+			sendingErr = shared.MarkAndWrapError(errors.New("mock"), shared.ErrTechnical, "mock")
+			err = confirmationEmailPublisher.Publish(ctx, message)
+
+			Convey("Then it should fail and not hand the message on to the next publisher", func() {
+				So(errors.Is(err, shared.ErrTechnical), ShouldBeTrue)
+				So(publishedMessages, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the message is published with a canceled ctx", func() {
+			event = customerRegistered
+			canceledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			err = confirmationEmailPublisher.Publish(canceledCtx, message)
+
+			Convey("Then sending the email should get the ctx of the caller and fail", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+				So(sentEmails, ShouldBeEmpty)
+				So(publishedMessages, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("When a ConfirmationEmailSender is created with an unsupported locale", t, func() {
+		_, err := application.NewConfirmationEmailSender(nil, nil, "xx")
+
+		Convey("Then it should fail", func() {
+			So(errors.Is(err, shared.ErrInputIsInvalid), ShouldBeTrue)
+		})
+	})
+}
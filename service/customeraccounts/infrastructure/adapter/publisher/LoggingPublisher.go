@@ -0,0 +1,36 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// LoggingPublisher does not deliver messages anywhere, it only logs them.
+type LoggingPublisher struct {
+	logger *shared.Logger
+}
+
+func NewLoggingPublisher(logger *shared.Logger) *LoggingPublisher {
+	return &LoggingPublisher{logger: logger}
+}
+
+func (publisher *LoggingPublisher) Publish(_ context.Context, message es.OutboxMessage) error {
+	payload := string(message.Payload())
+
+	if message.ContentType() != es.ContentTypeJSON {
+		payload = fmt.Sprintf("%d bytes of %s", len(message.Payload()), message.ContentType())
+	}
+
+	publisher.logger.Infof(
+		"loggingPublisher: published [%s] of stream [%s] with version [%d]: %s",
+		message.EventName(),
+		message.StreamID().String(),
+		message.StreamVersion(),
+		payload,
+	)
+
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"net/http"
 
+	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
 )
 
@@ -25,7 +27,7 @@ func CustomHTTPError(
 	const fallback = `{"error": "failed to marshal error message"}`
 
 	w.Header().Set("Content-type", marshaler.ContentType())
-	w.WriteHeader(runtime.HTTPStatusFromCode(status.Code(err)))
+	w.WriteHeader(httpStatusFrom(err))
 
 	jErr := json.NewEncoder(w).Encode(
 		errorBody{
@@ -37,3 +39,19 @@ func CustomHTTPError(
 		_, _ = w.Write([]byte(fallback)) // useless to handle an error happening while writing a fallback error
 	}
 }
+
+// httpStatusFrom responds with 412 Precondition Failed instead of 409 Conflict if the expected version
+// (If-Match) of a Customer was outdated.
+func httpStatusFrom(err error) int {
+	for _, detail := range status.Convert(err).Details() {
+		if preconditionFailure, ok := detail.(*errdetails.PreconditionFailure); ok {
+			for _, violation := range preconditionFailure.GetViolations() {
+				if violation.GetType() == customergrpc.PreconditionViolationTypeVersion {
+					return http.StatusPreconditionFailed
+				}
+			}
+		}
+	}
+
+	return runtime.HTTPStatusFromCode(status.Code(err))
+}
@@ -0,0 +1,34 @@
+package customerrest
+
+import (
+	"strings"
+
+	customergrpc "github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/grpc"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+)
+
+// CustomHeaderMatcher forwards the tracing headers, the Idempotency-Key and the If-Match header as gRPC metadata,
+// in addition to the default ones.
+func CustomHeaderMatcher(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case customergrpc.MetadataKeyRequestID,
+		customergrpc.MetadataKeyCorrelationID,
+		customergrpc.MetadataKeyCausationID,
+		customergrpc.MetadataKeyActor,
+		customergrpc.MetadataKeyIdempotencyKey,
+		customergrpc.MetadataKeyIfMatch:
+
+		return strings.ToLower(key), true
+	default:
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
+
+// CustomOutgoingHeaderMatcher sends the etag metadata as ETag header, other metadata gets the default prefix.
+func CustomOutgoingHeaderMatcher(key string) (string, bool) {
+	if strings.ToLower(key) == customergrpc.MetadataKeyETag {
+		return "ETag", true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
@@ -0,0 +1,166 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/postgres"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/sqlite"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/sqlite/database"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCustomerEventStore_WithSnapshots(t *testing.T) {
+	noEncoding := func(marshal es.MarshalDomainEvent) es.MarshalDomainEvent { return marshal }
+	noDecoding := func(unmarshal es.UnmarshalDomainEvent) es.UnmarshalDomainEvent { return unmarshal }
+
+	encodings := []struct {
+		contentType string
+		encode      func(marshal es.MarshalDomainEvent) es.MarshalDomainEvent
+		decode      func(unmarshal es.UnmarshalDomainEvent) es.UnmarshalDomainEvent
+	}{
+		{
+			contentType: es.ContentTypeJSON,
+			encode:      noEncoding,
+			decode:      noDecoding,
+		},
+		{
+			contentType: es.ContentTypeProtobuf,
+			encode:      serialization.EncodeCustomerEventsAsProtobuf,
+			decode:      serialization.DecodeCustomerEventsFromProtobuf,
+		},
+	}
+
+	for _, encoding := range encodings {
+		encoding := encoding
+
+		Convey(fmt.Sprintf("Given a CustomerEventStore on SQLite with encrypted %s payloads and a snapshot interval of 3", encoding.contentType), t, func() {
+			ctx := context.Background()
+			db := openMigratedDBForTest()
+			db.SetMaxOpenConns(1) // as the service does it, so the PII protection must use the transaction of the append
+
+			Reset(func() {
+				_ = db.Close()
+			})
+
+			keyStore := postgres.NewEncryptionKeyStore(db, "encryption_keys")
+			piiProtection := serialization.NewCustomerPIIProtection(
+				keyStore.RetrieveOrCreateEncryptionKey,
+				keyStore.RetrieveEncryptionKey,
+			)
+
+			eventStore := postgres.NewCustomerEventStore(
+				db,
+				sqlite.Dialect{},
+				es.NewSQLEventStore(
+					db,
+					sqlite.Dialect{},
+					"eventstore",
+					encoding.encode(piiProtection.Marshal(serialization.MarshalCustomerEvent)),
+					encoding.decode(piiProtection.Unmarshal(serialization.UnmarshalCustomerEvent)),
+					encoding.contentType,
+					"snapshots",
+					3,
+					customer.BuildSnapshot,
+					"outbox",
+					"eventstore_appended",
+				),
+				"unique_email_addresses",
+				customer.BuildUniqueEmailAddressAssertions,
+			)
+
+			customerID := value.GenerateCustomerID()
+			emailAddress := value.RebuildEmailAddress("fiona@gallagher.net")
+			newEmailAddress := value.RebuildEmailAddress("fiona@pratt.net")
+			confirmationHash := value.GenerateConfirmationHash()
+			personName := value.RebuildPersonName("Fiona", "Gallagher")
+			newPersonName := value.RebuildPersonName("Fiona", "Pratt")
+
+			customerRegistered := domain.BuildCustomerRegistered(customerID, emailAddress, confirmationHash, personName, es.MessageMeta{}, 1)
+			err := eventStore.StartEventStream(ctx, customerRegistered)
+			So(err, ShouldBeNil)
+
+			Convey("When enough events are appended to cross the snapshot interval", func() {
+				nameChanged := domain.BuildCustomerNameChanged(customerID, newPersonName, es.MessageMeta{}, 2)
+				emailAddressChanged := domain.BuildCustomerEmailAddressChanged(customerID, newEmailAddress, confirmationHash, emailAddress, es.MessageMeta{}, 3)
+				err = eventStore.AppendToEventStream(ctx, es.RecordedEvents{nameChanged, emailAddressChanged}, customerID)
+				So(err, ShouldBeNil)
+
+				Convey("Then the event stream should start with a snapshot of version 3", func() {
+					eventStream, err := eventStore.RetrieveEventStream(ctx, customerID)
+					So(err, ShouldBeNil)
+					So(eventStream, ShouldHaveLength, 1)
+					So(eventStream[0], ShouldHaveSameTypeAs, domain.CustomerSnapshot{})
+					So(eventStream[0].Meta().StreamVersion(), ShouldEqual, 3)
+
+					snapshot := eventStream[0].(domain.CustomerSnapshot)
+					So(snapshot.PersonName(), ShouldResemble, newPersonName)
+					So(snapshot.EmailAddress(), ShouldResemble, newEmailAddress)
+
+					Convey("And when more events are appended across the next snapshot interval", func() {
+						emailAddressConfirmed := domain.BuildCustomerEmailAddressConfirmed(customerID, newEmailAddress, es.MessageMeta{}, 4)
+						nameChangedBack := domain.BuildCustomerNameChanged(customerID, personName, es.MessageMeta{}, 5)
+						nameChangedAgain := domain.BuildCustomerNameChanged(customerID, newPersonName, es.MessageMeta{}, 6)
+						nameChangedLast := domain.BuildCustomerNameChanged(customerID, personName, es.MessageMeta{}, 7)
+
+						recordedEvents := es.RecordedEvents{emailAddressConfirmed, nameChangedBack, nameChangedAgain}
+						err = eventStore.AppendToEventStream(ctx, recordedEvents, customerID)
+						So(err, ShouldBeNil)
+						err = eventStore.AppendToEventStream(ctx, es.RecordedEvents{nameChangedLast}, customerID)
+						So(err, ShouldBeNil)
+
+						Convey("Then the event stream should contain the newer snapshot and the newer event", func() {
+							eventStream, err := eventStore.RetrieveEventStream(ctx, customerID)
+							So(err, ShouldBeNil)
+							So(eventStream, ShouldHaveLength, 2)
+							So(eventStream[0].Meta().StreamVersion(), ShouldEqual, 6)
+							So(eventStream[1].Meta().StreamVersion(), ShouldEqual, 7)
+
+							view := customer.BuildViewFrom(eventStream)
+							So(view.IsEmailAddressConfirmed, ShouldBeTrue)
+							So(view.GivenName, ShouldEqual, personName.GivenName())
+							So(view.FamilyName, ShouldEqual, personName.FamilyName())
+							So(view.Version, ShouldEqual, 7)
+
+							Convey("And the full event stream should still contain all events", func() {
+								fullEventStream, err := eventStore.RetrieveFullEventStream(ctx, customerID)
+								So(err, ShouldBeNil)
+								So(fullEventStream, ShouldHaveLength, 7)
+								So(customer.BuildViewFrom(fullEventStream), ShouldResemble, view)
+							})
+						})
+					})
+				})
+			})
+		})
+	}
+}
+
+func openMigratedDBForTest() *sql.DB {
+	directory, err := ioutil.TempDir("", "go-iddd-sqlite-test")
+	So(err, ShouldBeNil)
+
+	Reset(func() {
+		_ = os.RemoveAll(directory)
+	})
+
+	dsn := "file:" + filepath.Join(directory, "test.db") + "?_busy_timeout=5000&_txlock=immediate&_journal_mode=WAL"
+	db, err := sql.Open("sqlite3", dsn)
+	So(err, ShouldBeNil)
+
+	migrator, err := database.NewMigrator(db, "database/migrations")
+	So(err, ShouldBeNil)
+	So(migrator.Up(), ShouldBeNil)
+
+	return db
+}
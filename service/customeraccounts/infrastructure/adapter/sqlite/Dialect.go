@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Dialect is the es.SQLDialect for SQLite.
+type Dialect struct{}
+
+func (d Dialect) IsUniqueViolation(err error) bool {
+	actualErr, ok := err.(sqlite3.Error)
+	if !ok {
+		return false
+	}
+
+	return actualErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+		actualErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
+
+// Notify does nothing, SQLite has no notifications - consumers have to poll.
+func (d Dialect) Notify(ctx context.Context, tx *sql.Tx, channel string, payload string) error {
+	return nil
+}
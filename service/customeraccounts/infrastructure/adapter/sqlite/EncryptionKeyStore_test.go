@@ -0,0 +1,57 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/adapter/postgres"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEncryptionKeyStore_WithTransaction(t *testing.T) {
+	Convey("Given an EncryptionKeyStore on an SQLite DB with a single connection", t, func() {
+		ctx := context.Background()
+		db := openMigratedDBForTest()
+		db.SetMaxOpenConns(1)
+
+		Reset(func() {
+			_ = db.Close()
+		})
+
+		keyStore := postgres.NewEncryptionKeyStore(db, "encryption_keys")
+
+		Convey("When a key is created with a ctx which carries an open transaction", func() {
+			tx, err := db.BeginTx(ctx, nil)
+			So(err, ShouldBeNil)
+
+			txCtx := es.ContextWithSQLTx(ctx, tx)
+
+			key, err := keyStore.RetrieveOrCreateEncryptionKey(txCtx, "subject-1")
+			So(err, ShouldBeNil)
+
+			Convey("Then it should be retrievable inside the transaction", func() {
+				retrievedKey, err := keyStore.RetrieveEncryptionKey(txCtx, "subject-1")
+				So(err, ShouldBeNil)
+				So(retrievedKey, ShouldResemble, key)
+
+				Convey("and it should be gone when the transaction is rolled back", func() {
+					So(tx.Rollback(), ShouldBeNil)
+
+					_, err = keyStore.RetrieveEncryptionKey(ctx, "subject-1")
+					So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+				})
+
+				Convey("and it should be retrievable without the transaction once it is committed", func() {
+					So(tx.Commit(), ShouldBeNil)
+
+					retrievedKey, err = keyStore.RetrieveEncryptionKey(ctx, "subject-1")
+					So(err, ShouldBeNil)
+					So(retrievedKey, ShouldResemble, key)
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+type Migrator struct {
+	sqliteMigrator *migrate.Migrate
+}
+
+func NewMigrator(sqliteDBConn *sql.DB, migrationsPath string) (*Migrator, error) {
+	migrator := &Migrator{}
+	if err := migrator.configure(sqliteDBConn, migrationsPath); err != nil {
+		return nil, errors.Wrap(err, "NewMigrator")
+	}
+
+	return migrator, nil
+}
+
+func (migrator *Migrator) Up() error {
+	if err := migrator.sqliteMigrator.Up(); err != nil {
+		if err != migrate.ErrNoChange {
+			return errors.Wrap(err, "migrator.Up: failed to run migrations for SQLite DB")
+		}
+	}
+
+	return nil
+}
+
+func (migrator *Migrator) WithLogger(logger migrate.Logger) *Migrator {
+	migrator.sqliteMigrator.Log = logger
+
+	return migrator
+}
+
+func (migrator *Migrator) configure(sqliteDBConn *sql.DB, migrationsPath string) error {
+	config := &sqlite3.Config{MigrationsTable: "customer_migrations"}
+
+	driver, err := sqlite3.WithInstance(sqliteDBConn, config)
+	if err != nil {
+		return errors.Wrap(errors.Mark(err, shared.ErrTechnical), "failed to create SQLite driver for migrator")
+	}
+
+	sourceURL := fmt.Sprintf("file://%s", migrationsPath)
+	realMigrator, err := migrate.NewWithDatabaseInstance(sourceURL, "sqlite3", driver)
+	if err != nil {
+		return errors.Wrap(errors.Mark(err, shared.ErrTechnical), "failed to create migrator instance")
+	}
+
+	migrator.sqliteMigrator = realMigrator
+
+	return nil
+}
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS customers
+(
+    id varchar(255) not null
+        CONSTRAINT customers_pk
+            PRIMARY KEY,
+    email_address varchar(255) not null,
+    is_email_address_confirmed boolean not null,
+    given_name varchar(255) not null,
+    family_name varchar(255) not null,
+    is_deleted boolean not null,
+    is_forgotten boolean default false not null,
+    registered_at bigint not null,
+    version integer not null
+);
+
+CREATE INDEX IF NOT EXISTS customers_registered_at_idx
+    on customers (registered_at, id);
+
+CREATE INDEX IF NOT EXISTS customers_email_address_idx
+    on customers (email_address, id);
+
+CREATE INDEX IF NOT EXISTS customers_family_name_idx
+    on customers (family_name, id);
+
+CREATE TABLE IF NOT EXISTS projection_checkpoints
+(
+    projection_name varchar(255) not null
+        CONSTRAINT projection_checkpoints_pk
+            PRIMARY KEY,
+    checkpoint bigint not null
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS unique_email_addresses
+(
+    email_address varchar(255)
+        CONSTRAINT unique_email_addresses_pk
+            PRIMARY KEY,
+    customer_id varchar(255) not null
+);
+
+CREATE INDEX IF NOT EXISTS email_addresses_customer_id_idx
+    on unique_email_addresses (customer_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS snapshots
+(
+    stream_id varchar(255)
+        CONSTRAINT snapshots_pk
+            PRIMARY KEY,
+    stream_version integer not null,
+    snapshot_name varchar(255) not null,
+    payload text default '{}' not null,
+    created_at timestamp not null
+);
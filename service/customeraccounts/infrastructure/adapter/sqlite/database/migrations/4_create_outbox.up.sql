@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id integer not null
+        CONSTRAINT outbox_pk
+            PRIMARY KEY AUTOINCREMENT,
+    stream_id varchar(255) not null,
+    stream_version integer not null,
+    event_name varchar(255) not null,
+    occurred_at timestamp not null,
+    payload text default '{}' not null
+);
@@ -0,0 +1,7 @@
+ALTER TABLE eventstore ADD COLUMN event_id varchar(36);
+ALTER TABLE eventstore ADD COLUMN correlation_id varchar(255);
+ALTER TABLE eventstore ADD COLUMN causation_id varchar(255);
+ALTER TABLE eventstore ADD COLUMN actor varchar(255);
+
+CREATE INDEX IF NOT EXISTS correlation_id_idx
+    on eventstore (correlation_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS encryption_keys
+(
+    subject_id varchar(255) not null
+        CONSTRAINT encryption_keys_pk
+            PRIMARY KEY,
+    key blob,
+    created_at timestamp not null,
+    shredded_at timestamp
+);
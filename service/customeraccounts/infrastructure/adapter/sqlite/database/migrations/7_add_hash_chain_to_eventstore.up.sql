@@ -0,0 +1 @@
+ALTER TABLE eventstore ADD COLUMN event_hash char(64);
@@ -0,0 +1,8 @@
+ALTER TABLE eventstore ADD COLUMN content_type varchar(64) default 'application/json' not null;
+ALTER TABLE eventstore ADD COLUMN payload_binary blob;
+
+ALTER TABLE snapshots ADD COLUMN content_type varchar(64) default 'application/json' not null;
+ALTER TABLE snapshots ADD COLUMN payload_binary blob;
+
+ALTER TABLE outbox ADD COLUMN content_type varchar(64) default 'application/json' not null;
+ALTER TABLE outbox ADD COLUMN payload_binary blob;
@@ -22,6 +22,7 @@ type CustomerEmailAddressConfirmationFailedForJSON struct {
 	EmailAddress     string              `json:"emailAddress"`
 	ConfirmationHash string              `json:"confirmationHash"`
 	Reason           string              `json:"reason"`
+	ReasonCode       string              `json:"reasonCode"`
 	Meta             es.EventMetaForJSON `json:"meta"`
 }
 
@@ -33,6 +34,13 @@ type CustomerEmailAddressChangedForJSON struct {
 	Meta                 es.EventMetaForJSON `json:"meta"`
 }
 
+type CustomerEmailConfirmationRegeneratedForJSON struct {
+	CustomerID       string              `json:"customerID"`
+	EmailAddress     string              `json:"emailAddress"`
+	ConfirmationHash string              `json:"confirmationHash"`
+	Meta             es.EventMetaForJSON `json:"meta"`
+}
+
 type CustomerNameChangedForJSON struct {
 	CustomerID string              `json:"customerID"`
 	GivenName  string              `json:"givenName"`
@@ -45,3 +53,16 @@ type CustomerDeletedForJSON struct {
 	EmailAddress string              `json:"emailAddress"`
 	Meta         es.EventMetaForJSON `json:"meta"`
 }
+
+type CustomerSnapshotForJSON struct {
+	CustomerID               string              `json:"customerID"`
+	EmailAddress             string              `json:"emailAddress"`
+	ConfirmationHash         string              `json:"confirmationHash"`
+	ConfirmationHashIssuedAt string              `json:"confirmationHashIssuedAt,omitempty"`
+	ConfirmationFailedAt     []string            `json:"confirmationFailedAt,omitempty"`
+	PersonGivenName          string              `json:"personGivenName"`
+	PersonFamilyName         string              `json:"personFamilyName"`
+	IsEmailAddressConfirmed  bool                `json:"isEmailAddressConfirmed"`
+	IsDeleted                bool                `json:"isDeleted"`
+	Meta                     es.EventMetaForJSON `json:"meta"`
+}
@@ -0,0 +1,104 @@
+package serialization
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization/customerevents"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// EncodeCustomerEventsAsProtobuf converts the json which marshal produces into a message of the customerevents package.
+// Going through json keeps the PII protection and the upcasters working for both formats.
+func EncodeCustomerEventsAsProtobuf(marshal es.MarshalDomainEvent) es.MarshalDomainEvent {
+	return func(ctx context.Context, event es.DomainEvent) ([]byte, error) {
+		wrapWithMsg := "encodeCustomerEventsAsProtobuf"
+
+		payload, err := marshal(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+
+		message, err := newCustomerEventMessage(event.Meta().EventName())
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+		}
+
+		unmarshaler := jsonpb.Unmarshaler{}
+
+		if err = unmarshaler.Unmarshal(bytes.NewReader(payload), message); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+		}
+
+		if payload, err = proto.Marshal(message); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+		}
+
+		return payload, nil
+	}
+}
+
+// DecodeCustomerEventsFromProtobuf converts protobuf payloads back to json before unmarshal gets them.
+// JSON payloads, e.g. those which were stored before the format was switched, are passed through unchanged.
+func DecodeCustomerEventsFromProtobuf(unmarshal es.UnmarshalDomainEvent) es.UnmarshalDomainEvent {
+	return func(ctx context.Context, name string, payload []byte, streamVersion uint) (es.DomainEvent, error) {
+		wrapWithMsg := "decodeCustomerEventsFromProtobuf"
+
+		if IsJSONPayload(payload) {
+			return unmarshal(ctx, name, payload, streamVersion)
+		}
+
+		message, err := newCustomerEventMessage(name)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+		}
+
+		if err = proto.Unmarshal(payload, message); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+		}
+
+		marshaler := jsonpb.Marshaler{OrigName: true}
+		buffer := &bytes.Buffer{}
+
+		if err = marshaler.Marshal(buffer, message); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+		}
+
+		return unmarshal(ctx, name, buffer.Bytes(), streamVersion)
+	}
+}
+
+// IsJSONPayload is true for json objects.
+// Protobuf messages can't start with '{', that would be field 15 with the deprecated group wire type.
+func IsJSONPayload(payload []byte) bool {
+	trimmed := bytes.TrimLeft(payload, " \t\r\n")
+
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+func newCustomerEventMessage(eventName string) (proto.Message, error) {
+	switch eventName {
+	case "CustomerRegistered":
+		return &customerevents.CustomerRegistered{}, nil
+	case "CustomerEmailAddressConfirmed":
+		return &customerevents.CustomerEmailAddressConfirmed{}, nil
+	case "CustomerEmailAddressConfirmationFailed":
+		return &customerevents.CustomerEmailAddressConfirmationFailed{}, nil
+	case "CustomerEmailAddressChanged":
+		return &customerevents.CustomerEmailAddressChanged{}, nil
+	case "CustomerEmailConfirmationRegenerated":
+		return &customerevents.CustomerEmailConfirmationRegenerated{}, nil
+	case "CustomerNameChanged":
+		return &customerevents.CustomerNameChanged{}, nil
+	case "CustomerDeleted":
+		return &customerevents.CustomerDeleted{}, nil
+	case "CustomerSnapshot":
+		return &customerevents.CustomerSnapshot{}, nil
+	default:
+		return nil, errors.Newf("event [%s] has no protobuf message", eventName)
+	}
+}
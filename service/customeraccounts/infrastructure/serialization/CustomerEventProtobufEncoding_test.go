@@ -0,0 +1,130 @@
+package serialization_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization/customerevents"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCustomerEventProtobufEncoding(t *testing.T) {
+	ctx := context.Background()
+
+	customerID := value.GenerateCustomerID()
+	emailAddress := value.RebuildEmailAddress("lip@gallagher.net")
+	newEmailAddress := value.RebuildEmailAddress("phillip@gallagher.net")
+	confirmationHash := value.GenerateConfirmationHash()
+	personName := value.RebuildPersonName("Lip", "Gallagher")
+	messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
+	failedConfirmations := []time.Time{time.Date(2020, 11, 3, 10, 15, 30, 123456789, time.UTC)}
+
+	myEvents := []es.DomainEvent{
+		domain.BuildCustomerRegistered(customerID, emailAddress, confirmationHash, personName, messageMeta, 1),
+		domain.BuildCustomerEmailAddressConfirmed(customerID, emailAddress, messageMeta, 2),
+		domain.BuildCustomerEmailAddressChanged(customerID, newEmailAddress, confirmationHash, emailAddress, messageMeta, 3),
+		domain.BuildCustomerEmailConfirmationRegenerated(customerID, newEmailAddress, confirmationHash, messageMeta, 4),
+		domain.BuildCustomerNameChanged(customerID, personName, messageMeta, 5),
+		domain.BuildCustomerDeleted(customerID, newEmailAddress, messageMeta, 6),
+		domain.BuildCustomerSnapshot(customerID, personName, newEmailAddress, confirmationHash, failedConfirmations, false, true, 6),
+	}
+
+	marshal := serialization.EncodeCustomerEventsAsProtobuf(serialization.MarshalCustomerEvent)
+	unmarshal := serialization.DecodeCustomerEventsFromProtobuf(serialization.UnmarshalCustomerEvent)
+
+	for _, event := range myEvents {
+		originalEvent := event
+		eventName := originalEvent.Meta().EventName()
+
+		Convey(fmt.Sprintf("When %s is encoded as protobuf and decoded", eventName), t, func() {
+			payload, err := marshal(ctx, originalEvent)
+			So(err, ShouldBeNil)
+			So(serialization.IsJSONPayload(payload), ShouldBeFalse)
+
+			unmarshaledEvent, err := unmarshal(ctx, eventName, payload, originalEvent.Meta().StreamVersion())
+			So(err, ShouldBeNil)
+
+			Convey(fmt.Sprintf("Then the decoded %s should resemble the original one", eventName), func() {
+				So(unmarshaledEvent, ShouldResemble, originalEvent)
+			})
+		})
+	}
+
+	Convey("When CustomerRegistered is encoded as protobuf", t, func() {
+		payload, err := marshal(ctx, myEvents[0])
+		So(err, ShouldBeNil)
+
+		Convey("Then external consumers should be able to read it with the customerevents package", func() {
+			message := &customerevents.CustomerRegistered{}
+			err = proto.Unmarshal(payload, message)
+			So(err, ShouldBeNil)
+			So(message.CustomerID, ShouldEqual, customerID.String())
+			So(message.EmailAddress, ShouldEqual, emailAddress.String())
+			So(message.Meta.EventName, ShouldEqual, "CustomerRegistered")
+			So(message.Meta.CorrelationID, ShouldEqual, "some-correlation-id")
+			So(message.Meta.SchemaVersion, ShouldBeGreaterThanOrEqualTo, 1)
+		})
+	})
+
+	Convey("When a json payload is decoded", t, func() {
+		payload, err := serialization.MarshalCustomerEvent(ctx, myEvents[0])
+		So(err, ShouldBeNil)
+
+		unmarshaledEvent, err := unmarshal(ctx, myEvents[0].Meta().EventName(), payload, 1)
+
+		Convey("Then it should be passed through unchanged", func() {
+			So(err, ShouldBeNil)
+			So(unmarshaledEvent, ShouldResemble, myEvents[0])
+		})
+	})
+
+	Convey("When an event with PII protection is encoded as protobuf", t, func() {
+		key, _ := es.GenerateEncryptionKey()
+		retrieveKey := func(ctx context.Context, subjectID string) ([]byte, error) { return key, nil }
+		piiProtection := serialization.NewCustomerPIIProtection(retrieveKey, retrieveKey)
+
+		marshalWithPIIProtection := serialization.EncodeCustomerEventsAsProtobuf(
+			piiProtection.Marshal(serialization.MarshalCustomerEvent),
+		)
+
+		unmarshalWithPIIProtection := serialization.DecodeCustomerEventsFromProtobuf(
+			piiProtection.Unmarshal(serialization.UnmarshalCustomerEvent),
+		)
+
+		payload, err := marshalWithPIIProtection(ctx, myEvents[0])
+		So(err, ShouldBeNil)
+
+		Convey("Then its PII should be encrypted", func() {
+			message := &customerevents.CustomerRegistered{}
+			So(proto.Unmarshal(payload, message), ShouldBeNil)
+			So(es.IsEncryptedPII(message.EmailAddress), ShouldBeTrue)
+
+			Convey("And when it is decoded", func() {
+				unmarshaledEvent, err := unmarshalWithPIIProtection(ctx, myEvents[0].Meta().EventName(), payload, 1)
+
+				Convey("Then it should be the original event", func() {
+					So(err, ShouldBeNil)
+					So(unmarshaledEvent, ShouldResemble, myEvents[0])
+				})
+			})
+		})
+	})
+
+	Convey("When an event without protobuf message is decoded", t, func() {
+		_, err := unmarshal(ctx, "SomeEvent", []byte{0x0a, 0x00}, 1)
+
+		Convey("Then it should fail", func() {
+			So(err, ShouldBeError)
+			So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+		})
+	})
+}
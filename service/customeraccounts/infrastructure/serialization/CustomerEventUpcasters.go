@@ -0,0 +1,43 @@
+package serialization
+
+import (
+	"encoding/json" // jsoniter can't handle maps with the reflect2 version we are stuck with
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+)
+
+// customerEventUpcasters migrates stored payloads of older schema versions to the current *ForJSON shapes.
+// Whenever the json shape of a Customer event changes, register an Upcast for it here,
+// which also bumps the schema version that MarshalCustomerEvent writes.
+var customerEventUpcasters = es.NewUpcasters().
+	Register("CustomerEmailAddressConfirmationFailed", 1, addConfirmationFailedReasonCode)
+
+// addConfirmationFailedReasonCode derives the reasonCode from the reason, events before schema version 2 had no code.
+func addConfirmationFailedReasonCode(payload []byte) ([]byte, error) {
+	var data map[string]json.RawMessage
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	var reason string
+
+	if err := json.Unmarshal(data["reason"], &reason); err != nil {
+		return nil, err
+	}
+
+	reasonCode := domain.ConfirmationFailedWithWrongHash
+	if reason == domain.ErrConfirmationHashExpired.Error() {
+		reasonCode = domain.ConfirmationFailedWithExpiredHash
+	}
+
+	raw, err := json.Marshal(reasonCode)
+	if err != nil {
+		return nil, err
+	}
+
+	data["reasonCode"] = raw
+
+	return json.Marshal(data)
+}
@@ -0,0 +1,211 @@
+package serialization
+
+import (
+	"context"
+	"encoding/json" // jsoniter can't handle maps with the reflect2 version we are stuck with
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+)
+
+// customerPIIFields are the json keys of all *ForJSON shapes which hold personally identifiable information.
+var customerPIIFields = []string{
+	"emailAddress",
+	"previousEmailAddress",
+	"personGivenName",
+	"personFamilyName",
+	"givenName",
+	"familyName",
+}
+
+// CustomerPIIProtection encrypts the PII of Customer events with a key per Customer (crypto-shredding).
+// Once the key of a Customer is shredded, the events stay in place but their PII is unmarshaled as es.RedactedPII.
+// Payloads which were stored before the encryption was introduced are passed through unchanged.
+// Redacted values are not PII anymore, they are marshaled as they are, so forgotten Customers need no key.
+type CustomerPIIProtection struct {
+	retrieveOrCreateKey es.RetrieveOrCreateEncryptionKey
+	retrieveKey         es.RetrieveEncryptionKey
+}
+
+func NewCustomerPIIProtection(
+	retrieveOrCreateKey es.RetrieveOrCreateEncryptionKey,
+	retrieveKey es.RetrieveEncryptionKey,
+) *CustomerPIIProtection {
+
+	return &CustomerPIIProtection{
+		retrieveOrCreateKey: retrieveOrCreateKey,
+		retrieveKey:         retrieveKey,
+	}
+}
+
+func (p *CustomerPIIProtection) Marshal(marshal es.MarshalDomainEvent) es.MarshalDomainEvent {
+	return func(ctx context.Context, event es.DomainEvent) ([]byte, error) {
+		wrapWithMsg := "customerPIIProtection.Marshal"
+
+		payload, err := marshal(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+
+		fields, customerID, err := unmarshalPIIFields(payload)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+		}
+
+		for name, value := range fields {
+			if value == es.RedactedPII {
+				delete(fields, name)
+			}
+		}
+
+		if len(fields) == 0 {
+			return payload, nil
+		}
+
+		key, err := p.retrieveOrCreateKey(ctx, customerID)
+		if err != nil {
+			return nil, errors.Wrap(err, wrapWithMsg)
+		}
+
+		for name, value := range fields {
+			if fields[name], err = es.EncryptPII(key, value); err != nil {
+				return nil, errors.Wrap(err, wrapWithMsg)
+			}
+		}
+
+		if payload, err = replacePIIFields(payload, fields); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+		}
+
+		return payload, nil
+	}
+}
+
+func (p *CustomerPIIProtection) Unmarshal(unmarshal es.UnmarshalDomainEvent) es.UnmarshalDomainEvent {
+	return p.unmarshal(unmarshal, true)
+}
+
+// UnmarshalWithRequiredKeys fails with shared.ErrNotFound instead of redacting the PII if the key is missing,
+// e.g. when importing events which were encrypted with the keys of another environment.
+func (p *CustomerPIIProtection) UnmarshalWithRequiredKeys(unmarshal es.UnmarshalDomainEvent) es.UnmarshalDomainEvent {
+	return p.unmarshal(unmarshal, false)
+}
+
+func (p *CustomerPIIProtection) unmarshal(
+	unmarshal es.UnmarshalDomainEvent,
+	redactWithoutKey bool,
+) es.UnmarshalDomainEvent {
+
+	return func(ctx context.Context, name string, payload []byte, streamVersion uint) (es.DomainEvent, error) {
+		wrapWithMsg := "customerPIIProtection.Unmarshal"
+
+		fields, customerID, err := unmarshalPIIFields(payload)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+		}
+
+		for fieldName, value := range fields {
+			if !es.IsEncryptedPII(value) {
+				delete(fields, fieldName) // stored before the encryption was introduced
+			}
+		}
+
+		if len(fields) > 0 {
+			if err = p.decryptPIIFields(ctx, customerID, fields, redactWithoutKey); err != nil {
+				return nil, errors.Wrap(err, wrapWithMsg)
+			}
+
+			if payload, err = replacePIIFields(payload, fields); err != nil {
+				return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+			}
+		}
+
+		return unmarshal(ctx, name, payload, streamVersion)
+	}
+}
+
+func (p *CustomerPIIProtection) decryptPIIFields(
+	ctx context.Context,
+	customerID string,
+	fields map[string]string,
+	redactWithoutKey bool,
+) error {
+
+	key, err := p.retrieveKey(ctx, customerID)
+
+	switch {
+	case errors.Is(err, shared.ErrNotFound) && !redactWithoutKey:
+		return errors.Wrapf(err, "no key to decrypt the pii of customer [%s]", customerID)
+	case errors.Is(err, shared.ErrNotFound):
+		for name := range fields {
+			fields[name] = es.RedactedPII
+		}
+
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for name, value := range fields {
+		if fields[name], err = es.DecryptPII(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmarshalPIIFields returns all PII fields with a non-empty value and the customerID of a payload.
+func unmarshalPIIFields(payload []byte) (map[string]string, string, error) {
+	var data map[string]json.RawMessage
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, "", err
+	}
+
+	var customerID string
+
+	if err := json.Unmarshal(data["customerID"], &customerID); err != nil || customerID == "" {
+		return nil, "", errors.New("payload has no customerID")
+	}
+
+	fields := make(map[string]string)
+
+	for _, name := range customerPIIFields {
+		raw, ok := data[name]
+		if !ok {
+			continue
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, "", errors.Wrapf(err, "pii field [%s] is not a string", name)
+		}
+
+		if value != "" {
+			fields[name] = value
+		}
+	}
+
+	return fields, customerID, nil
+}
+
+func replacePIIFields(payload []byte, fields map[string]string) ([]byte, error) {
+	var data map[string]json.RawMessage
+
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	for name, value := range fields {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		data[name] = raw
+	}
+
+	return json.Marshal(data)
+}
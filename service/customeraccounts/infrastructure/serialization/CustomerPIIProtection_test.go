@@ -0,0 +1,115 @@
+package serialization_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
+	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/infrastructure/serialization"
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCustomerPIIProtection(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Prepare test artifacts", t, func() {
+		keys := make(map[string][]byte)
+
+		retrieveOrCreateKey := func(ctx context.Context, subjectID string) ([]byte, error) {
+			if _, ok := keys[subjectID]; !ok {
+				keys[subjectID], _ = es.GenerateEncryptionKey()
+			}
+
+			return keys[subjectID], nil
+		}
+
+		retrieveKey := func(ctx context.Context, subjectID string) ([]byte, error) {
+			key, ok := keys[subjectID]
+			if !ok {
+				return nil, errors.Mark(errors.New("mocked error"), shared.ErrNotFound)
+			}
+
+			return key, nil
+		}
+
+		piiProtection := serialization.NewCustomerPIIProtection(retrieveOrCreateKey, retrieveKey)
+		marshal := piiProtection.Marshal(serialization.MarshalCustomerEvent)
+		unmarshal := piiProtection.Unmarshal(serialization.UnmarshalCustomerEvent)
+
+		customerID := value.GenerateCustomerID()
+		emailAddress := value.RebuildEmailAddress("kevin@ball.com")
+
+		registered := domain.BuildCustomerRegistered(
+			customerID,
+			emailAddress,
+			value.GenerateConfirmationHash(),
+			value.RebuildPersonName("Kevin", "Ball"),
+			es.MessageMeta{},
+			1,
+		)
+
+		Convey("When a Customer event is marshaled", func() {
+			payload, err := marshal(ctx, registered)
+			So(err, ShouldBeNil)
+
+			Convey("Then its PII should be encrypted", func() {
+				So(string(payload), ShouldNotContainSubstring, "kevin@ball.com")
+				So(string(payload), ShouldNotContainSubstring, "Kevin")
+				So(string(payload), ShouldContainSubstring, customerID.String())
+
+				Convey("And when it is unmarshaled", func() {
+					unmarshaled, err := unmarshal(ctx, registered.Meta().EventName(), payload, 1)
+					So(err, ShouldBeNil)
+
+					Convey("Then it should be the original event", func() {
+						So(unmarshaled, ShouldResemble, registered)
+					})
+				})
+
+				Convey("And when the Customer's key was shredded and it is unmarshaled", func() {
+					delete(keys, customerID.String())
+
+					unmarshaled, err := unmarshal(ctx, registered.Meta().EventName(), payload, 1)
+					So(err, ShouldBeNil)
+
+					Convey("Then its PII should be redacted", func() {
+						actualEvent, ok := unmarshaled.(domain.CustomerRegistered)
+						So(ok, ShouldBeTrue)
+						So(actualEvent.CustomerID().Equals(customerID), ShouldBeTrue)
+						So(actualEvent.EmailAddress().String(), ShouldEqual, es.RedactedPII)
+						So(actualEvent.PersonName().GivenName(), ShouldEqual, es.RedactedPII)
+						So(actualEvent.PersonName().FamilyName(), ShouldEqual, es.RedactedPII)
+						So(actualEvent.ConfirmationHash().Equals(registered.ConfirmationHash()), ShouldBeTrue)
+					})
+				})
+
+				Convey("And when the Customer's key is missing and it is unmarshaled with required keys", func() {
+					delete(keys, customerID.String())
+
+					unmarshalWithRequiredKeys := piiProtection.UnmarshalWithRequiredKeys(serialization.UnmarshalCustomerEvent)
+					_, err := unmarshalWithRequiredKeys(ctx, registered.Meta().EventName(), payload, 1)
+
+					Convey("Then it should fail", func() {
+						So(errors.Is(err, shared.ErrNotFound), ShouldBeTrue)
+					})
+				})
+			})
+		})
+
+		Convey("When a payload which was stored before the encryption was introduced is unmarshaled", func() {
+			payload, err := serialization.MarshalCustomerEvent(ctx, registered)
+			So(err, ShouldBeNil)
+
+			unmarshaled, err := unmarshal(ctx, registered.Meta().EventName(), payload, 1)
+
+			Convey("Then it should be unmarshaled unchanged", func() {
+				So(err, ShouldBeNil)
+				So(unmarshaled, ShouldResemble, registered)
+			})
+		})
+	})
+}
@@ -1,14 +1,19 @@
 package serialization
 
 import (
+	"context"
+	stdjson "encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain/customer/value"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
 	"github.com/cockroachdb/errors"
+	jsoniter "github.com/json-iterator/go"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -16,45 +21,74 @@ func TestMarshalAndUnmarshalCustomerEvents(t *testing.T) {
 	customerID := value.GenerateCustomerID()
 	emailAddress := value.RebuildEmailAddress("john@doe.com")
 	newEmailAddress := value.RebuildEmailAddress("john.frank@doe.com")
-	confirmationHash := value.GenerateConfirmationHash(emailAddress.String())
+	confirmationHash := value.GenerateConfirmationHash()
 	personName := value.RebuildPersonName("John", "Doe")
 	newPersonName := value.RebuildPersonName("John Frank", "Doe")
-	failureReason := "wrong confirmation hash supplied"
+	messageMeta := es.BuildMessageMeta("some-correlation-id", "some-causation-id", "some-actor")
 
 	var myEvents []es.DomainEvent
 	streamVersion := uint(1)
 
 	myEvents = append(
 		myEvents,
-		domain.BuildCustomerRegistered(customerID, emailAddress, confirmationHash, personName, streamVersion),
+		domain.BuildCustomerRegistered(customerID, emailAddress, confirmationHash, personName, messageMeta, streamVersion),
 	)
 
 	streamVersion++
 
 	myEvents = append(
 		myEvents,
-		domain.BuildCustomerEmailAddressConfirmed(customerID, emailAddress, streamVersion),
+		domain.BuildCustomerEmailAddressConfirmed(customerID, emailAddress, messageMeta, streamVersion),
 	)
 
 	streamVersion++
 
 	myEvents = append(
 		myEvents,
-		domain.BuildCustomerEmailAddressChanged(customerID, newEmailAddress, confirmationHash, emailAddress, streamVersion),
+		domain.BuildCustomerEmailAddressChanged(customerID, newEmailAddress, confirmationHash, emailAddress, messageMeta, streamVersion),
 	)
 
 	streamVersion++
 
 	myEvents = append(
 		myEvents,
-		domain.BuildCustomerNameChanged(customerID, newPersonName, streamVersion),
+		domain.BuildCustomerEmailConfirmationRegenerated(customerID, newEmailAddress, confirmationHash, messageMeta, streamVersion),
 	)
 
 	streamVersion++
 
 	myEvents = append(
 		myEvents,
-		domain.BuildCustomerDeleted(customerID, emailAddress, streamVersion),
+		domain.BuildCustomerNameChanged(customerID, newPersonName, messageMeta, streamVersion),
+	)
+
+	streamVersion++
+
+	myEvents = append(
+		myEvents,
+		domain.BuildCustomerDeleted(customerID, emailAddress, messageMeta, streamVersion),
+	)
+
+	streamVersion++
+
+	myEvents = append(
+		myEvents,
+		domain.BuildCustomerSnapshot(customerID, newPersonName, emailAddress, confirmationHash, nil, true, true, streamVersion),
+	)
+
+	streamVersion++
+
+	issuedConfirmationHash := myEvents[0].(domain.CustomerRegistered).ConfirmationHash()
+	failedConfirmations := []time.Time{
+		time.Date(2020, 11, 3, 10, 15, 30, 123456789, time.UTC),
+		time.Date(2020, 11, 3, 10, 17, 45, 0, time.UTC),
+	}
+
+	myEvents = append(
+		myEvents,
+		domain.BuildCustomerSnapshot(
+			customerID, personName, emailAddress, issuedConfirmationHash, failedConfirmations, false, false, streamVersion,
+		),
 	)
 
 	for idx, event := range myEvents {
@@ -63,10 +97,10 @@ func TestMarshalAndUnmarshalCustomerEvents(t *testing.T) {
 		eventName := originalEvent.Meta().EventName()
 
 		Convey(fmt.Sprintf("When %s is marshaled and unmarshaled", eventName), t, func() {
-			json, err := MarshalCustomerEvent(originalEvent)
+			json, err := MarshalCustomerEvent(context.Background(), originalEvent)
 			So(err, ShouldBeNil)
 
-			unmarshaledEvent, err := UnmarshalCustomerEvent(originalEvent.Meta().EventName(), json, streamVersion)
+			unmarshaledEvent, err := UnmarshalCustomerEvent(context.Background(), originalEvent.Meta().EventName(), json, streamVersion)
 			So(err, ShouldBeNil)
 
 			Convey(fmt.Sprintf("Then the unmarshaled %s should resemble the original %s", eventName, eventName), func() {
@@ -80,15 +114,15 @@ func TestMarshalAndUnmarshalCustomerEvents(t *testing.T) {
 
 	Convey("When CustomerEmailAddressConfirmationFailed is marshaled and unmarshaled", t, func() {
 		originalEvent := domain.BuildCustomerEmailAddressConfirmationFailed(
-			customerID, emailAddress, confirmationHash, errors.Mark(errors.New(failureReason), shared.ErrDomainConstraintsViolation), streamVersion,
+			customerID, emailAddress, confirmationHash, errors.Mark(domain.ErrConfirmationHashExpired, shared.ErrDomainConstraintsViolation), messageMeta, streamVersion,
 		)
 
 		oEventName := originalEvent.Meta().EventName()
 
-		json, err := MarshalCustomerEvent(originalEvent)
+		json, err := MarshalCustomerEvent(context.Background(), originalEvent)
 		So(err, ShouldBeNil)
 
-		unmarshaledEvent, err := UnmarshalCustomerEvent(originalEvent.Meta().EventName(), json, streamVersion)
+		unmarshaledEvent, err := UnmarshalCustomerEvent(context.Background(), originalEvent.Meta().EventName(), json, streamVersion)
 		So(err, ShouldBeNil)
 
 		uEventName := unmarshaledEvent.Meta().EventName()
@@ -99,6 +133,8 @@ func TestMarshalAndUnmarshalCustomerEvents(t *testing.T) {
 			So(unmarshaledEvent.CustomerID().Equals(originalEvent.CustomerID()), ShouldBeTrue)
 			So(unmarshaledEvent.EmailAddress().Equals(originalEvent.EmailAddress()), ShouldBeTrue)
 			So(unmarshaledEvent.ConfirmationHash().Equals(originalEvent.ConfirmationHash()), ShouldBeTrue)
+			So(unmarshaledEvent.ReasonCode(), ShouldEqual, domain.ConfirmationFailedWithExpiredHash)
+			So(errors.Is(unmarshaledEvent.FailureReason(), domain.ErrConfirmationHashExpired), ShouldBeTrue)
 			assertEventMetaResembles(originalEvent, unmarshaledEvent)
 		})
 	})
@@ -117,7 +153,7 @@ func assertEventMetaResembles(originalEvent es.DomainEvent, unmarshaledEvent es.
 
 func TestMarshalCustomerEvent_WithUnknownEvent(t *testing.T) {
 	Convey("When an unknown event is marshaled", t, func() {
-		_, err := MarshalCustomerEvent(SomeEvent{})
+		_, err := MarshalCustomerEvent(context.Background(), SomeEvent{})
 
 		Convey("Then it should fail", func() {
 			So(errors.Is(err, shared.ErrMarshalingFailed), ShouldBeTrue)
@@ -127,7 +163,7 @@ func TestMarshalCustomerEvent_WithUnknownEvent(t *testing.T) {
 
 func TestUnmarshalCustomerEvent_WithUnknownEvent(t *testing.T) {
 	Convey("When an unknown event is unmarshaled", t, func() {
-		_, err := UnmarshalCustomerEvent("unknown", []byte{}, 1)
+		_, err := UnmarshalCustomerEvent(context.Background(), "unknown", []byte{}, 1)
 
 		Convey("Then it should fail", func() {
 			So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
@@ -135,12 +171,119 @@ func TestUnmarshalCustomerEvent_WithUnknownEvent(t *testing.T) {
 	})
 }
 
+func TestUnmarshalCustomerEvent_WithInvalidPayload(t *testing.T) {
+	Convey("When a known event with an invalid payload is unmarshaled", t, func() {
+		_, err := UnmarshalCustomerEvent(context.Background(), "CustomerNameChanged", []byte(`{"customerID":`), 1)
+
+		Convey("Then it should fail", func() {
+			So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+		})
+	})
+}
+
+func TestMarshalAndUnmarshalCustomerEvent_WithSchemaVersions(t *testing.T) {
+	customerID := value.GenerateCustomerID()
+	personName := value.RebuildPersonName("Kevin", "Ball")
+
+	Convey("When an event is marshaled", t, func() {
+		json, err := MarshalCustomerEvent(context.Background(), domain.BuildCustomerNameChanged(customerID, personName, es.MessageMeta{}, 2))
+		So(err, ShouldBeNil)
+
+		Convey("Then it should contain the current schema version", func() {
+			So(jsoniter.ConfigFastest.Get(json, "meta", "schemaVersion").ToUint(), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When a payload which was stored before schema versions existed is unmarshaled", t, func() {
+		payload := `{"customerID":"` + customerID.String() + `","givenName":"Kevin","familyName":"Ball",` +
+			`"meta":{"eventName":"CustomerNameChanged","occurredAt":"2020-02-02T20:20:20Z"}}`
+
+		event, err := UnmarshalCustomerEvent(context.Background(), "CustomerNameChanged", []byte(payload), 2)
+
+		Convey("Then it should be treated as schema version 1", func() {
+			So(err, ShouldBeNil)
+			So(event.(domain.CustomerNameChanged).PersonName(), ShouldResemble, personName)
+		})
+	})
+
+	Convey("When a CustomerEmailAddressConfirmationFailed of schema version 1 with an expired hash is unmarshaled", t, func() {
+		payload := `{"customerID":"` + customerID.String() + `","emailAddress":"kevin@ball.com",` +
+			`"confirmationHash":"some-hash","reason":"confirmation hash has expired",` +
+			`"meta":{"eventName":"CustomerEmailAddressConfirmationFailed","occurredAt":"2020-02-02T20:20:20Z"}}`
+
+		event, err := UnmarshalCustomerEvent(context.Background(), "CustomerEmailAddressConfirmationFailed", []byte(payload), 2)
+		So(err, ShouldBeNil)
+
+		Convey("Then its reason code should be derived from the reason", func() {
+			actualEvent, ok := event.(domain.CustomerEmailAddressConfirmationFailed)
+			So(ok, ShouldBeTrue)
+			So(actualEvent.ReasonCode(), ShouldEqual, domain.ConfirmationFailedWithExpiredHash)
+			So(errors.Is(actualEvent.FailureReason(), domain.ErrConfirmationHashExpired), ShouldBeTrue)
+		})
+	})
+
+	Convey("When a payload with a schema version newer than the current one is unmarshaled", t, func() {
+		payload := `{"customerID":"` + customerID.String() + `","meta":{"schemaVersion":2}}`
+
+		_, err := UnmarshalCustomerEvent(context.Background(), "CustomerNameChanged", []byte(payload), 2)
+
+		Convey("Then it should fail", func() {
+			So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given an Upcast which splits a former name field into givenName and familyName", t, func() {
+		originalUpcasters := customerEventUpcasters
+
+		customerEventUpcasters = es.NewUpcasters().Register(
+			"CustomerNameChanged",
+			1,
+			func(payload []byte) ([]byte, error) {
+				data := make(map[string]interface{})
+				if err := stdjson.Unmarshal(payload, &data); err != nil {
+					return nil, err
+				}
+
+				nameParts := strings.SplitN(data["name"].(string), " ", 2)
+				data["givenName"], data["familyName"] = nameParts[0], nameParts[1]
+				delete(data, "name")
+
+				return stdjson.Marshal(data)
+			},
+		)
+
+		Convey("When a payload with schema version 1 is unmarshaled", func() {
+			payload := `{"customerID":"` + customerID.String() + `","name":"Kevin Ball","meta":{"schemaVersion":1}}`
+
+			event, err := UnmarshalCustomerEvent(context.Background(), "CustomerNameChanged", []byte(payload), 2)
+
+			Convey("Then it should be upcast to the current shape", func() {
+				So(err, ShouldBeNil)
+				So(event.(domain.CustomerNameChanged).PersonName(), ShouldResemble, personName)
+			})
+		})
+
+		Convey("When an event is marshaled", func() {
+			json, err := MarshalCustomerEvent(context.Background(), domain.BuildCustomerNameChanged(customerID, personName, es.MessageMeta{}, 2))
+			So(err, ShouldBeNil)
+
+			Convey("Then it should contain the bumped schema version", func() {
+				So(jsoniter.ConfigFastest.Get(json, "meta", "schemaVersion").ToUint(), ShouldEqual, 2)
+			})
+		})
+
+		Reset(func() {
+			customerEventUpcasters = originalUpcasters
+		})
+	})
+}
+
 /***** a mock event to test marshaling unknown event *****/
 
 type SomeEvent struct{}
 
 func (event SomeEvent) Meta() es.EventMeta {
-	return es.RebuildEventMeta("SomeEvent", "never", 1)
+	return es.RebuildEventMeta("", "SomeEvent", "never", es.MessageMeta{}, 1)
 }
 
 func (event SomeEvent) IsFailureEvent() bool {
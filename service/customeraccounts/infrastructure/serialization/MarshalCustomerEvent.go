@@ -1,6 +1,9 @@
 package serialization
 
 import (
+	"context"
+	"time"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
@@ -11,7 +14,7 @@ import (
 // MarshalCustomerEvent marshals every known Customer event to json.
 // It intentionally ignores marshaling errors, because they can't happen with the data types we are using.
 // We have a rich test suite which would catch such issues.
-func MarshalCustomerEvent(event es.DomainEvent) ([]byte, error) {
+func MarshalCustomerEvent(_ context.Context, event es.DomainEvent) ([]byte, error) {
 	var err error
 	var json []byte
 
@@ -24,10 +27,14 @@ func MarshalCustomerEvent(event es.DomainEvent) ([]byte, error) {
 		json = marshalCustomerEmailAddressConfirmationFailed(actualEvent)
 	case domain.CustomerEmailAddressChanged:
 		json = marshalCustomerEmailAddressChanged(actualEvent)
+	case domain.CustomerEmailConfirmationRegenerated:
+		json = marshalCustomerEmailConfirmationRegenerated(actualEvent)
 	case domain.CustomerNameChanged:
 		json = marshalCustomerNameChanged(actualEvent)
 	case domain.CustomerDeleted:
 		json = marshalCustomerDeleted(actualEvent)
+	case domain.CustomerSnapshot:
+		json = marshalCustomerSnapshot(actualEvent)
 	default:
 		err = errors.Wrapf(errors.New("event is unknown"), "marshalCustomerEvent [%s] failed", event.Meta().EventName())
 		return nil, errors.Mark(err, shared.ErrMarshalingFailed)
@@ -70,6 +77,7 @@ func marshalCustomerEmailAddressConfirmationFailed(event domain.CustomerEmailAdd
 		EmailAddress:     event.EmailAddress().String(),
 		ConfirmationHash: event.ConfirmationHash().String(),
 		Reason:           event.FailureReason().Error(),
+		ReasonCode:       event.ReasonCode(),
 		Meta:             marshalEventMeta(event),
 	}
 
@@ -92,6 +100,19 @@ func marshalCustomerEmailAddressChanged(event domain.CustomerEmailAddressChanged
 	return json
 }
 
+func marshalCustomerEmailConfirmationRegenerated(event domain.CustomerEmailConfirmationRegenerated) []byte {
+	data := CustomerEmailConfirmationRegeneratedForJSON{
+		CustomerID:       event.CustomerID().String(),
+		EmailAddress:     event.EmailAddress().String(),
+		ConfirmationHash: event.ConfirmationHash().String(),
+		Meta:             marshalEventMeta(event),
+	}
+
+	json, _ := jsoniter.ConfigFastest.Marshal(data) // err intentionally ignored - see top comment
+
+	return json
+}
+
 func marshalCustomerNameChanged(event domain.CustomerNameChanged) []byte {
 	data := CustomerNameChangedForJSON{
 		CustomerID: event.CustomerID().String(),
@@ -117,9 +138,39 @@ func marshalCustomerDeleted(event domain.CustomerDeleted) []byte {
 	return json
 }
 
+func marshalCustomerSnapshot(snapshot domain.CustomerSnapshot) []byte {
+	data := CustomerSnapshotForJSON{
+		CustomerID:              snapshot.CustomerID().String(),
+		EmailAddress:            snapshot.EmailAddress().String(),
+		ConfirmationHash:        snapshot.EmailAddressConfirmationHash().String(),
+		PersonGivenName:         snapshot.PersonName().GivenName(),
+		PersonFamilyName:        snapshot.PersonName().FamilyName(),
+		IsEmailAddressConfirmed: snapshot.IsEmailAddressConfirmed(),
+		IsDeleted:               snapshot.IsDeleted(),
+		Meta:                    marshalEventMeta(snapshot),
+	}
+
+	if issuedAt := snapshot.EmailAddressConfirmationHash().IssuedAt(); !issuedAt.IsZero() {
+		data.ConfirmationHashIssuedAt = issuedAt.Format(time.RFC3339Nano)
+	}
+
+	for _, failedAt := range snapshot.FailedConfirmations() {
+		data.ConfirmationFailedAt = append(data.ConfirmationFailedAt, failedAt.Format(time.RFC3339Nano))
+	}
+
+	json, _ := jsoniter.ConfigFastest.Marshal(data) // err intentionally ignored - see top comment
+
+	return json
+}
+
 func marshalEventMeta(event es.DomainEvent) es.EventMetaForJSON {
 	return es.EventMetaForJSON{
-		EventName:  event.Meta().EventName(),
-		OccurredAt: event.Meta().OccurredAt(),
+		EventID:       event.Meta().EventID(),
+		EventName:     event.Meta().EventName(),
+		OccurredAt:    event.Meta().OccurredAt(),
+		CorrelationID: event.Meta().MessageMeta().CorrelationID(),
+		CausationID:   event.Meta().MessageMeta().CausationID(),
+		Actor:         event.Meta().MessageMeta().Actor(),
+		SchemaVersion: customerEventUpcasters.CurrentSchemaVersion(event.Meta().EventName()),
 	}
 }
@@ -1,6 +1,9 @@
 package serialization
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/AntonStoeckl/go-iddd/service/customeraccounts/hexagon/application/domain"
 	"github.com/AntonStoeckl/go-iddd/service/shared"
 	"github.com/AntonStoeckl/go-iddd/service/shared/es"
@@ -8,33 +11,46 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
-// UnmarshalCustomerEvent unmarshals every know Customer event.
-// It intentionally ignores unmarshaling errors, which could only happen if we would store invalid json to the EventStore.
-// We have a rich test suite which would catch such issues.
+// UnmarshalCustomerEvent unmarshals every known Customer event.
+// Payloads of older schema versions are upcast to the current one first.
 func UnmarshalCustomerEvent(
+	_ context.Context,
 	name string,
 	payload []byte,
 	streamVersion uint,
 ) (es.DomainEvent, error) {
 
+	var err error
 	var event es.DomainEvent
+	wrapWithMsg := fmt.Sprintf("unmarshalCustomerEvent [%s] failed", name)
+
+	if payload, err = customerEventUpcasters.Upcast(name, payload); err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
 
 	switch name {
 	case "CustomerRegistered":
-		event = unmarshalCustomerRegisteredFromJSON(payload, streamVersion)
+		event, err = unmarshalCustomerRegisteredFromJSON(payload, streamVersion)
 	case "CustomerEmailAddressConfirmed":
-		event = unmarshalCustomerEmailAddressConfirmedFromJSON(payload, streamVersion)
+		event, err = unmarshalCustomerEmailAddressConfirmedFromJSON(payload, streamVersion)
 	case "CustomerEmailAddressConfirmationFailed":
-		event = unmarshalCustomerEmailAddressConfirmationFailedFromJSON(payload, streamVersion)
+		event, err = unmarshalCustomerEmailAddressConfirmationFailedFromJSON(payload, streamVersion)
 	case "CustomerEmailAddressChanged":
-		event = unmarshalCustomerEmailAddressChangedFromJSON(payload, streamVersion)
+		event, err = unmarshalCustomerEmailAddressChangedFromJSON(payload, streamVersion)
+	case "CustomerEmailConfirmationRegenerated":
+		event, err = unmarshalCustomerEmailConfirmationRegeneratedFromJSON(payload, streamVersion)
 	case "CustomerNameChanged":
-		event = unmarshalCustomerNameChangedFromJSON(payload, streamVersion)
+		event, err = unmarshalCustomerNameChangedFromJSON(payload, streamVersion)
 	case "CustomerDeleted":
-		event = unmarshalCustomerDeletedFromJSON(payload, streamVersion)
+		event, err = unmarshalCustomerDeletedFromJSON(payload, streamVersion)
+	case "CustomerSnapshot":
+		event, err = unmarshalCustomerSnapshotFromJSON(payload, streamVersion)
 	default:
-		err := errors.Wrapf(errors.New("event is unknown"), "unmarshalCustomerEvent [%s] failed", name)
-		return nil, errors.Mark(err, shared.ErrUnmarshalingFailed)
+		err = errors.New("event is unknown")
+	}
+
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
 	}
 
 	return event, nil
@@ -43,11 +59,13 @@ func UnmarshalCustomerEvent(
 func unmarshalCustomerRegisteredFromJSON(
 	data []byte,
 	streamVersion uint,
-) domain.CustomerRegistered {
+) (domain.CustomerRegistered, error) {
 
 	unmarshaledData := &CustomerRegisteredForJSON{}
 
-	_ = jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData) // err intentionally ignored - see top comment
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerRegistered{}, err
+	}
 
 	event := domain.RebuildCustomerRegistered(
 		unmarshaledData.CustomerID,
@@ -58,17 +76,19 @@ func unmarshalCustomerRegisteredFromJSON(
 		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
 	)
 
-	return event
+	return event, nil
 }
 
 func unmarshalCustomerEmailAddressConfirmedFromJSON(
 	data []byte,
 	streamVersion uint,
-) domain.CustomerEmailAddressConfirmed {
+) (domain.CustomerEmailAddressConfirmed, error) {
 
 	unmarshaledData := &CustomerEmailAddressConfirmedForJSON{}
 
-	_ = jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData) // err intentionally ignored - see top comment
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerEmailAddressConfirmed{}, err
+	}
 
 	event := domain.RebuildCustomerEmailAddressConfirmed(
 		unmarshaledData.CustomerID,
@@ -76,37 +96,41 @@ func unmarshalCustomerEmailAddressConfirmedFromJSON(
 		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
 	)
 
-	return event
+	return event, nil
 }
 
 func unmarshalCustomerEmailAddressConfirmationFailedFromJSON(
 	data []byte,
 	streamVersion uint,
-) domain.CustomerEmailAddressConfirmationFailed {
+) (domain.CustomerEmailAddressConfirmationFailed, error) {
 
 	unmarshaledData := &CustomerEmailAddressConfirmationFailedForJSON{}
 
-	_ = jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData) // err intentionally ignored - see top comment
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerEmailAddressConfirmationFailed{}, err
+	}
 
 	event := domain.RebuildCustomerEmailAddressConfirmationFailed(
 		unmarshaledData.CustomerID,
 		unmarshaledData.EmailAddress,
 		unmarshaledData.ConfirmationHash,
-		unmarshaledData.Reason,
+		unmarshaledData.ReasonCode,
 		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
 	)
 
-	return event
+	return event, nil
 }
 
 func unmarshalCustomerEmailAddressChangedFromJSON(
 	data []byte,
 	streamVersion uint,
-) domain.CustomerEmailAddressChanged {
+) (domain.CustomerEmailAddressChanged, error) {
 
 	unmarshaledData := &CustomerEmailAddressChangedForJSON{}
 
-	_ = jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData) // err intentionally ignored - see top comment
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerEmailAddressChanged{}, err
+	}
 
 	event := domain.RebuildCustomerEmailAddressChanged(
 		unmarshaledData.CustomerID,
@@ -116,17 +140,40 @@ func unmarshalCustomerEmailAddressChangedFromJSON(
 		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
 	)
 
-	return event
+	return event, nil
+}
+
+func unmarshalCustomerEmailConfirmationRegeneratedFromJSON(
+	data []byte,
+	streamVersion uint,
+) (domain.CustomerEmailConfirmationRegenerated, error) {
+
+	unmarshaledData := &CustomerEmailConfirmationRegeneratedForJSON{}
+
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerEmailConfirmationRegenerated{}, err
+	}
+
+	event := domain.RebuildCustomerEmailConfirmationRegenerated(
+		unmarshaledData.CustomerID,
+		unmarshaledData.EmailAddress,
+		unmarshaledData.ConfirmationHash,
+		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
+	)
+
+	return event, nil
 }
 
 func unmarshalCustomerNameChangedFromJSON(
 	data []byte,
 	streamVersion uint,
-) domain.CustomerNameChanged {
+) (domain.CustomerNameChanged, error) {
 
 	unmarshaledData := &CustomerNameChangedForJSON{}
 
-	_ = jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData) // err intentionally ignored - see top comment
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerNameChanged{}, err
+	}
 
 	event := domain.RebuildCustomerNameChanged(
 		unmarshaledData.CustomerID,
@@ -135,17 +182,19 @@ func unmarshalCustomerNameChangedFromJSON(
 		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
 	)
 
-	return event
+	return event, nil
 }
 
 func unmarshalCustomerDeletedFromJSON(
 	data []byte,
 	streamVersion uint,
-) domain.CustomerDeleted {
+) (domain.CustomerDeleted, error) {
 
 	unmarshaledData := &CustomerDeletedForJSON{}
 
-	_ = jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData) // err intentionally ignored - see top comment
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerDeleted{}, err
+	}
 
 	event := domain.RebuildCustomerDeleted(
 		unmarshaledData.CustomerID,
@@ -153,13 +202,42 @@ func unmarshalCustomerDeletedFromJSON(
 		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
 	)
 
-	return event
+	return event, nil
+}
+
+func unmarshalCustomerSnapshotFromJSON(
+	data []byte,
+	streamVersion uint,
+) (domain.CustomerSnapshot, error) {
+
+	unmarshaledData := &CustomerSnapshotForJSON{}
+
+	if err := jsoniter.ConfigFastest.Unmarshal(data, unmarshaledData); err != nil {
+		return domain.CustomerSnapshot{}, err
+	}
+
+	snapshot := domain.RebuildCustomerSnapshot(
+		unmarshaledData.CustomerID,
+		unmarshaledData.PersonGivenName,
+		unmarshaledData.PersonFamilyName,
+		unmarshaledData.EmailAddress,
+		unmarshaledData.ConfirmationHash,
+		unmarshaledData.ConfirmationHashIssuedAt,
+		unmarshaledData.ConfirmationFailedAt,
+		unmarshaledData.IsEmailAddressConfirmed,
+		unmarshaledData.IsDeleted,
+		unmarshalEventMeta(unmarshaledData.Meta, streamVersion),
+	)
+
+	return snapshot, nil
 }
 
 func unmarshalEventMeta(meta es.EventMetaForJSON, streamVersion uint) es.EventMeta {
 	return es.RebuildEventMeta(
+		meta.EventID,
 		meta.EventName,
 		meta.OccurredAt,
+		es.BuildMessageMeta(meta.CorrelationID, meta.CausationID, meta.Actor),
 		streamVersion,
 	)
 }
@@ -0,0 +1,740 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: customer_events.proto
+
+package customerevents
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type EventMeta struct {
+	EventID              string   `protobuf:"bytes,1,opt,name=eventID,proto3" json:"eventID,omitempty"`
+	EventName            string   `protobuf:"bytes,2,opt,name=eventName,proto3" json:"eventName,omitempty"`
+	OccurredAt           string   `protobuf:"bytes,3,opt,name=occurredAt,proto3" json:"occurredAt,omitempty"`
+	CorrelationID        string   `protobuf:"bytes,4,opt,name=correlationID,proto3" json:"correlationID,omitempty"`
+	CausationID          string   `protobuf:"bytes,5,opt,name=causationID,proto3" json:"causationID,omitempty"`
+	Actor                string   `protobuf:"bytes,6,opt,name=actor,proto3" json:"actor,omitempty"`
+	SchemaVersion        uint32   `protobuf:"varint,7,opt,name=schemaVersion,proto3" json:"schemaVersion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventMeta) Reset()         { *m = EventMeta{} }
+func (m *EventMeta) String() string { return proto.CompactTextString(m) }
+func (*EventMeta) ProtoMessage()    {}
+func (*EventMeta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{0}
+}
+
+func (m *EventMeta) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventMeta.Unmarshal(m, b)
+}
+func (m *EventMeta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventMeta.Marshal(b, m, deterministic)
+}
+func (m *EventMeta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventMeta.Merge(m, src)
+}
+func (m *EventMeta) XXX_Size() int {
+	return xxx_messageInfo_EventMeta.Size(m)
+}
+func (m *EventMeta) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventMeta.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventMeta proto.InternalMessageInfo
+
+func (m *EventMeta) GetEventID() string {
+	if m != nil {
+		return m.EventID
+	}
+	return ""
+}
+
+func (m *EventMeta) GetEventName() string {
+	if m != nil {
+		return m.EventName
+	}
+	return ""
+}
+
+func (m *EventMeta) GetOccurredAt() string {
+	if m != nil {
+		return m.OccurredAt
+	}
+	return ""
+}
+
+func (m *EventMeta) GetCorrelationID() string {
+	if m != nil {
+		return m.CorrelationID
+	}
+	return ""
+}
+
+func (m *EventMeta) GetCausationID() string {
+	if m != nil {
+		return m.CausationID
+	}
+	return ""
+}
+
+func (m *EventMeta) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *EventMeta) GetSchemaVersion() uint32 {
+	if m != nil {
+		return m.SchemaVersion
+	}
+	return 0
+}
+
+type CustomerRegistered struct {
+	Meta                 *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID           string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	EmailAddress         string     `protobuf:"bytes,3,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	ConfirmationHash     string     `protobuf:"bytes,4,opt,name=confirmationHash,proto3" json:"confirmationHash,omitempty"`
+	PersonGivenName      string     `protobuf:"bytes,5,opt,name=personGivenName,proto3" json:"personGivenName,omitempty"`
+	PersonFamilyName     string     `protobuf:"bytes,6,opt,name=personFamilyName,proto3" json:"personFamilyName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CustomerRegistered) Reset()         { *m = CustomerRegistered{} }
+func (m *CustomerRegistered) String() string { return proto.CompactTextString(m) }
+func (*CustomerRegistered) ProtoMessage()    {}
+func (*CustomerRegistered) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{1}
+}
+
+func (m *CustomerRegistered) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerRegistered.Unmarshal(m, b)
+}
+func (m *CustomerRegistered) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerRegistered.Marshal(b, m, deterministic)
+}
+func (m *CustomerRegistered) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerRegistered.Merge(m, src)
+}
+func (m *CustomerRegistered) XXX_Size() int {
+	return xxx_messageInfo_CustomerRegistered.Size(m)
+}
+func (m *CustomerRegistered) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerRegistered.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerRegistered proto.InternalMessageInfo
+
+func (m *CustomerRegistered) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerRegistered) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerRegistered) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+func (m *CustomerRegistered) GetConfirmationHash() string {
+	if m != nil {
+		return m.ConfirmationHash
+	}
+	return ""
+}
+
+func (m *CustomerRegistered) GetPersonGivenName() string {
+	if m != nil {
+		return m.PersonGivenName
+	}
+	return ""
+}
+
+func (m *CustomerRegistered) GetPersonFamilyName() string {
+	if m != nil {
+		return m.PersonFamilyName
+	}
+	return ""
+}
+
+type CustomerEmailAddressConfirmed struct {
+	Meta                 *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID           string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	EmailAddress         string     `protobuf:"bytes,3,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CustomerEmailAddressConfirmed) Reset()         { *m = CustomerEmailAddressConfirmed{} }
+func (m *CustomerEmailAddressConfirmed) String() string { return proto.CompactTextString(m) }
+func (*CustomerEmailAddressConfirmed) ProtoMessage()    {}
+func (*CustomerEmailAddressConfirmed) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{2}
+}
+
+func (m *CustomerEmailAddressConfirmed) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerEmailAddressConfirmed.Unmarshal(m, b)
+}
+func (m *CustomerEmailAddressConfirmed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerEmailAddressConfirmed.Marshal(b, m, deterministic)
+}
+func (m *CustomerEmailAddressConfirmed) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerEmailAddressConfirmed.Merge(m, src)
+}
+func (m *CustomerEmailAddressConfirmed) XXX_Size() int {
+	return xxx_messageInfo_CustomerEmailAddressConfirmed.Size(m)
+}
+func (m *CustomerEmailAddressConfirmed) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerEmailAddressConfirmed.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerEmailAddressConfirmed proto.InternalMessageInfo
+
+func (m *CustomerEmailAddressConfirmed) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerEmailAddressConfirmed) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressConfirmed) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+type CustomerEmailAddressConfirmationFailed struct {
+	Meta                 *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID           string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	EmailAddress         string     `protobuf:"bytes,3,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	ConfirmationHash     string     `protobuf:"bytes,4,opt,name=confirmationHash,proto3" json:"confirmationHash,omitempty"`
+	Reason               string     `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	ReasonCode           string     `protobuf:"bytes,6,opt,name=reasonCode,proto3" json:"reasonCode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CustomerEmailAddressConfirmationFailed) Reset() {
+	*m = CustomerEmailAddressConfirmationFailed{}
+}
+func (m *CustomerEmailAddressConfirmationFailed) String() string { return proto.CompactTextString(m) }
+func (*CustomerEmailAddressConfirmationFailed) ProtoMessage()    {}
+func (*CustomerEmailAddressConfirmationFailed) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{3}
+}
+
+func (m *CustomerEmailAddressConfirmationFailed) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerEmailAddressConfirmationFailed.Unmarshal(m, b)
+}
+func (m *CustomerEmailAddressConfirmationFailed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerEmailAddressConfirmationFailed.Marshal(b, m, deterministic)
+}
+func (m *CustomerEmailAddressConfirmationFailed) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerEmailAddressConfirmationFailed.Merge(m, src)
+}
+func (m *CustomerEmailAddressConfirmationFailed) XXX_Size() int {
+	return xxx_messageInfo_CustomerEmailAddressConfirmationFailed.Size(m)
+}
+func (m *CustomerEmailAddressConfirmationFailed) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerEmailAddressConfirmationFailed.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerEmailAddressConfirmationFailed proto.InternalMessageInfo
+
+func (m *CustomerEmailAddressConfirmationFailed) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerEmailAddressConfirmationFailed) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressConfirmationFailed) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressConfirmationFailed) GetConfirmationHash() string {
+	if m != nil {
+		return m.ConfirmationHash
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressConfirmationFailed) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressConfirmationFailed) GetReasonCode() string {
+	if m != nil {
+		return m.ReasonCode
+	}
+	return ""
+}
+
+type CustomerEmailAddressChanged struct {
+	Meta                 *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID           string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	EmailAddress         string     `protobuf:"bytes,3,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	ConfirmationHash     string     `protobuf:"bytes,4,opt,name=confirmationHash,proto3" json:"confirmationHash,omitempty"`
+	PreviousEmailAddress string     `protobuf:"bytes,5,opt,name=previousEmailAddress,proto3" json:"previousEmailAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CustomerEmailAddressChanged) Reset()         { *m = CustomerEmailAddressChanged{} }
+func (m *CustomerEmailAddressChanged) String() string { return proto.CompactTextString(m) }
+func (*CustomerEmailAddressChanged) ProtoMessage()    {}
+func (*CustomerEmailAddressChanged) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{4}
+}
+
+func (m *CustomerEmailAddressChanged) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerEmailAddressChanged.Unmarshal(m, b)
+}
+func (m *CustomerEmailAddressChanged) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerEmailAddressChanged.Marshal(b, m, deterministic)
+}
+func (m *CustomerEmailAddressChanged) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerEmailAddressChanged.Merge(m, src)
+}
+func (m *CustomerEmailAddressChanged) XXX_Size() int {
+	return xxx_messageInfo_CustomerEmailAddressChanged.Size(m)
+}
+func (m *CustomerEmailAddressChanged) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerEmailAddressChanged.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerEmailAddressChanged proto.InternalMessageInfo
+
+func (m *CustomerEmailAddressChanged) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerEmailAddressChanged) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressChanged) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressChanged) GetConfirmationHash() string {
+	if m != nil {
+		return m.ConfirmationHash
+	}
+	return ""
+}
+
+func (m *CustomerEmailAddressChanged) GetPreviousEmailAddress() string {
+	if m != nil {
+		return m.PreviousEmailAddress
+	}
+	return ""
+}
+
+type CustomerEmailConfirmationRegenerated struct {
+	Meta                 *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID           string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	EmailAddress         string     `protobuf:"bytes,3,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	ConfirmationHash     string     `protobuf:"bytes,4,opt,name=confirmationHash,proto3" json:"confirmationHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CustomerEmailConfirmationRegenerated) Reset()         { *m = CustomerEmailConfirmationRegenerated{} }
+func (m *CustomerEmailConfirmationRegenerated) String() string { return proto.CompactTextString(m) }
+func (*CustomerEmailConfirmationRegenerated) ProtoMessage()    {}
+func (*CustomerEmailConfirmationRegenerated) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{5}
+}
+
+func (m *CustomerEmailConfirmationRegenerated) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerEmailConfirmationRegenerated.Unmarshal(m, b)
+}
+func (m *CustomerEmailConfirmationRegenerated) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerEmailConfirmationRegenerated.Marshal(b, m, deterministic)
+}
+func (m *CustomerEmailConfirmationRegenerated) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerEmailConfirmationRegenerated.Merge(m, src)
+}
+func (m *CustomerEmailConfirmationRegenerated) XXX_Size() int {
+	return xxx_messageInfo_CustomerEmailConfirmationRegenerated.Size(m)
+}
+func (m *CustomerEmailConfirmationRegenerated) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerEmailConfirmationRegenerated.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerEmailConfirmationRegenerated proto.InternalMessageInfo
+
+func (m *CustomerEmailConfirmationRegenerated) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerEmailConfirmationRegenerated) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerEmailConfirmationRegenerated) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+func (m *CustomerEmailConfirmationRegenerated) GetConfirmationHash() string {
+	if m != nil {
+		return m.ConfirmationHash
+	}
+	return ""
+}
+
+type CustomerNameChanged struct {
+	Meta                 *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID           string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	GivenName            string     `protobuf:"bytes,3,opt,name=givenName,proto3" json:"givenName,omitempty"`
+	FamilyName           string     `protobuf:"bytes,4,opt,name=familyName,proto3" json:"familyName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CustomerNameChanged) Reset()         { *m = CustomerNameChanged{} }
+func (m *CustomerNameChanged) String() string { return proto.CompactTextString(m) }
+func (*CustomerNameChanged) ProtoMessage()    {}
+func (*CustomerNameChanged) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{6}
+}
+
+func (m *CustomerNameChanged) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerNameChanged.Unmarshal(m, b)
+}
+func (m *CustomerNameChanged) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerNameChanged.Marshal(b, m, deterministic)
+}
+func (m *CustomerNameChanged) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerNameChanged.Merge(m, src)
+}
+func (m *CustomerNameChanged) XXX_Size() int {
+	return xxx_messageInfo_CustomerNameChanged.Size(m)
+}
+func (m *CustomerNameChanged) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerNameChanged.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerNameChanged proto.InternalMessageInfo
+
+func (m *CustomerNameChanged) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerNameChanged) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerNameChanged) GetGivenName() string {
+	if m != nil {
+		return m.GivenName
+	}
+	return ""
+}
+
+func (m *CustomerNameChanged) GetFamilyName() string {
+	if m != nil {
+		return m.FamilyName
+	}
+	return ""
+}
+
+type CustomerDeleted struct {
+	Meta                 *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID           string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	EmailAddress         string     `protobuf:"bytes,3,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *CustomerDeleted) Reset()         { *m = CustomerDeleted{} }
+func (m *CustomerDeleted) String() string { return proto.CompactTextString(m) }
+func (*CustomerDeleted) ProtoMessage()    {}
+func (*CustomerDeleted) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{7}
+}
+
+func (m *CustomerDeleted) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerDeleted.Unmarshal(m, b)
+}
+func (m *CustomerDeleted) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerDeleted.Marshal(b, m, deterministic)
+}
+func (m *CustomerDeleted) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerDeleted.Merge(m, src)
+}
+func (m *CustomerDeleted) XXX_Size() int {
+	return xxx_messageInfo_CustomerDeleted.Size(m)
+}
+func (m *CustomerDeleted) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerDeleted.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerDeleted proto.InternalMessageInfo
+
+func (m *CustomerDeleted) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerDeleted) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerDeleted) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+type CustomerSnapshot struct {
+	Meta                     *EventMeta `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	CustomerID               string     `protobuf:"bytes,2,opt,name=customerID,proto3" json:"customerID,omitempty"`
+	EmailAddress             string     `protobuf:"bytes,3,opt,name=emailAddress,proto3" json:"emailAddress,omitempty"`
+	ConfirmationHash         string     `protobuf:"bytes,4,opt,name=confirmationHash,proto3" json:"confirmationHash,omitempty"`
+	PersonGivenName          string     `protobuf:"bytes,5,opt,name=personGivenName,proto3" json:"personGivenName,omitempty"`
+	PersonFamilyName         string     `protobuf:"bytes,6,opt,name=personFamilyName,proto3" json:"personFamilyName,omitempty"`
+	IsEmailAddressConfirmed  bool       `protobuf:"varint,7,opt,name=isEmailAddressConfirmed,proto3" json:"isEmailAddressConfirmed,omitempty"`
+	IsDeleted                bool       `protobuf:"varint,8,opt,name=isDeleted,proto3" json:"isDeleted,omitempty"`
+	ConfirmationHashIssuedAt string     `protobuf:"bytes,9,opt,name=confirmationHashIssuedAt,proto3" json:"confirmationHashIssuedAt,omitempty"`
+	ConfirmationFailedAt     []string   `protobuf:"bytes,10,rep,name=confirmationFailedAt,proto3" json:"confirmationFailedAt,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{}   `json:"-"`
+	XXX_unrecognized         []byte     `json:"-"`
+	XXX_sizecache            int32      `json:"-"`
+}
+
+func (m *CustomerSnapshot) Reset()         { *m = CustomerSnapshot{} }
+func (m *CustomerSnapshot) String() string { return proto.CompactTextString(m) }
+func (*CustomerSnapshot) ProtoMessage()    {}
+func (*CustomerSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_72ae4d8c9026e522, []int{8}
+}
+
+func (m *CustomerSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomerSnapshot.Unmarshal(m, b)
+}
+func (m *CustomerSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomerSnapshot.Marshal(b, m, deterministic)
+}
+func (m *CustomerSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomerSnapshot.Merge(m, src)
+}
+func (m *CustomerSnapshot) XXX_Size() int {
+	return xxx_messageInfo_CustomerSnapshot.Size(m)
+}
+func (m *CustomerSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomerSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomerSnapshot proto.InternalMessageInfo
+
+func (m *CustomerSnapshot) GetMeta() *EventMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *CustomerSnapshot) GetCustomerID() string {
+	if m != nil {
+		return m.CustomerID
+	}
+	return ""
+}
+
+func (m *CustomerSnapshot) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+func (m *CustomerSnapshot) GetConfirmationHash() string {
+	if m != nil {
+		return m.ConfirmationHash
+	}
+	return ""
+}
+
+func (m *CustomerSnapshot) GetPersonGivenName() string {
+	if m != nil {
+		return m.PersonGivenName
+	}
+	return ""
+}
+
+func (m *CustomerSnapshot) GetPersonFamilyName() string {
+	if m != nil {
+		return m.PersonFamilyName
+	}
+	return ""
+}
+
+func (m *CustomerSnapshot) GetIsEmailAddressConfirmed() bool {
+	if m != nil {
+		return m.IsEmailAddressConfirmed
+	}
+	return false
+}
+
+func (m *CustomerSnapshot) GetIsDeleted() bool {
+	if m != nil {
+		return m.IsDeleted
+	}
+	return false
+}
+
+func (m *CustomerSnapshot) GetConfirmationHashIssuedAt() string {
+	if m != nil {
+		return m.ConfirmationHashIssuedAt
+	}
+	return ""
+}
+
+func (m *CustomerSnapshot) GetConfirmationFailedAt() []string {
+	if m != nil {
+		return m.ConfirmationFailedAt
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*EventMeta)(nil), "customerevents.EventMeta")
+	proto.RegisterType((*CustomerRegistered)(nil), "customerevents.CustomerRegistered")
+	proto.RegisterType((*CustomerEmailAddressConfirmed)(nil), "customerevents.CustomerEmailAddressConfirmed")
+	proto.RegisterType((*CustomerEmailAddressConfirmationFailed)(nil), "customerevents.CustomerEmailAddressConfirmationFailed")
+	proto.RegisterType((*CustomerEmailAddressChanged)(nil), "customerevents.CustomerEmailAddressChanged")
+	proto.RegisterType((*CustomerEmailConfirmationRegenerated)(nil), "customerevents.CustomerEmailConfirmationRegenerated")
+	proto.RegisterType((*CustomerNameChanged)(nil), "customerevents.CustomerNameChanged")
+	proto.RegisterType((*CustomerDeleted)(nil), "customerevents.CustomerDeleted")
+	proto.RegisterType((*CustomerSnapshot)(nil), "customerevents.CustomerSnapshot")
+}
+
+func init() {
+	proto.RegisterFile("customer_events.proto", fileDescriptor_72ae4d8c9026e522)
+}
+
+var fileDescriptor_72ae4d8c9026e522 = []byte{
+	// 534 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x96, 0x4f, 0x6f, 0xd3, 0x30,
+	0x18, 0xc6, 0xe5, 0xfd, 0xe9, 0x96, 0x77, 0x8c, 0x4d, 0x66, 0x80, 0x11, 0x03, 0x55, 0xd6, 0x84,
+	0x2a, 0x24, 0x7a, 0x18, 0x17, 0xc4, 0xad, 0x6a, 0x37, 0xe8, 0x01, 0x0e, 0x41, 0xe2, 0x8a, 0x4c,
+	0xf2, 0xae, 0xb5, 0x94, 0xd8, 0x95, 0xed, 0x54, 0xe2, 0xce, 0x85, 0x2b, 0x1f, 0x81, 0xef, 0xc2,
+	0x99, 0x8f, 0xc1, 0x95, 0x6f, 0x00, 0xb2, 0x93, 0xac, 0xc9, 0xda, 0x71, 0x43, 0xab, 0xc4, 0xcd,
+	0xfe, 0xbd, 0x6f, 0xd3, 0xe7, 0x79, 0xde, 0xda, 0x29, 0xdc, 0x4d, 0x0a, 0xeb, 0x74, 0x8e, 0xe6,
+	0x03, 0xce, 0x51, 0x39, 0xdb, 0x9f, 0x19, 0xed, 0x34, 0xbd, 0x5d, 0xe3, 0x92, 0xf2, 0x9f, 0x04,
+	0xa2, 0x33, 0xbf, 0x7c, 0x83, 0x4e, 0x50, 0x06, 0x3b, 0x81, 0x8f, 0x47, 0x8c, 0x74, 0x49, 0x2f,
+	0x8a, 0xeb, 0x2d, 0x3d, 0x86, 0x28, 0x2c, 0xdf, 0x8a, 0x1c, 0xd9, 0x46, 0xa8, 0x2d, 0x00, 0x7d,
+	0x0c, 0xa0, 0x93, 0xa4, 0x30, 0x06, 0xd3, 0x81, 0x63, 0x9b, 0xa1, 0xdc, 0x20, 0xf4, 0x04, 0xf6,
+	0x13, 0x6d, 0x0c, 0x66, 0xc2, 0x49, 0xad, 0xc6, 0x23, 0xb6, 0x15, 0x5a, 0xda, 0x90, 0x76, 0x61,
+	0x2f, 0x11, 0x85, 0xad, 0x7b, 0xb6, 0x43, 0x4f, 0x13, 0xd1, 0x23, 0xd8, 0x16, 0x89, 0xd3, 0x86,
+	0x75, 0x42, 0xad, 0xdc, 0xf8, 0xa7, 0xdb, 0x64, 0x8a, 0xb9, 0x78, 0x8f, 0xc6, 0x4a, 0xad, 0xd8,
+	0x4e, 0x97, 0xf4, 0xf6, 0xe3, 0x36, 0xe4, 0x5f, 0x36, 0x80, 0x0e, 0x2b, 0xf3, 0x31, 0x4e, 0xa4,
+	0x75, 0x68, 0x30, 0xa5, 0xcf, 0x60, 0x2b, 0x47, 0x27, 0x82, 0xdf, 0xbd, 0xd3, 0x07, 0xfd, 0x76,
+	0x3e, 0xfd, 0xcb, 0x6c, 0xe2, 0xd0, 0xe6, 0x9d, 0xd6, 0x1d, 0xe3, 0x51, 0x15, 0x44, 0x83, 0x50,
+	0x0e, 0xb7, 0x30, 0x17, 0x32, 0x1b, 0xa4, 0xa9, 0x41, 0x6b, 0xab, 0x2c, 0x5a, 0x8c, 0x3e, 0x85,
+	0xc3, 0x44, 0xab, 0x0b, 0x69, 0xf2, 0xe0, 0xeb, 0xb5, 0xb0, 0xd3, 0x2a, 0x90, 0x25, 0x4e, 0x7b,
+	0x70, 0x30, 0x43, 0x63, 0xb5, 0x7a, 0x25, 0xe7, 0xa8, 0x42, 0xfa, 0x65, 0x2e, 0x57, 0xb1, 0x7f,
+	0x6a, 0x89, 0xce, 0x45, 0x2e, 0xb3, 0x4f, 0xa1, 0xb5, 0x8c, 0x69, 0x89, 0xf3, 0xaf, 0x04, 0x1e,
+	0xd5, 0x59, 0x9c, 0x35, 0xa4, 0x0d, 0xcb, 0xaf, 0xbf, 0x91, 0x58, 0xf8, 0x6f, 0x02, 0x4f, 0xfe,
+	0x22, 0x2a, 0x64, 0x72, 0x2e, 0x64, 0xb6, 0xfe, 0x43, 0xbb, 0x07, 0x1d, 0x83, 0xc2, 0x6a, 0x55,
+	0xcd, 0xaa, 0xda, 0x79, 0x1d, 0xe5, 0x6a, 0xa8, 0xd3, 0x7a, 0x38, 0x0d, 0xc2, 0x7f, 0x11, 0x78,
+	0xb8, 0x32, 0x81, 0xa9, 0x50, 0x93, 0xf5, 0xb7, 0x7d, 0x0a, 0x47, 0x33, 0x83, 0x73, 0xa9, 0x0b,
+	0xdb, 0x54, 0x5f, 0x85, 0xb0, 0xb2, 0xc6, 0xbf, 0x13, 0x38, 0x69, 0x59, 0x6e, 0x4e, 0x3b, 0xc6,
+	0x09, 0x2a, 0x34, 0xc2, 0xad, 0xbd, 0x77, 0xfe, 0x8d, 0xc0, 0x9d, 0xda, 0x87, 0x3f, 0x62, 0xff,
+	0x68, 0x64, 0xc7, 0x10, 0x4d, 0x2e, 0x2f, 0x82, 0x52, 0xf3, 0x02, 0xf8, 0x4f, 0x5f, 0x2c, 0x0e,
+	0x7f, 0x29, 0xb5, 0x41, 0xf8, 0x67, 0x02, 0x07, 0xb5, 0xc8, 0x11, 0x66, 0x78, 0x33, 0xb9, 0xf2,
+	0x1f, 0x9b, 0x70, 0x58, 0xcb, 0x78, 0xa7, 0xc4, 0xcc, 0x4e, 0xb5, 0xfb, 0x2f, 0xef, 0x61, 0xfa,
+	0x02, 0xee, 0x4b, 0xbb, 0xf2, 0x02, 0x0e, 0xef, 0xb0, 0xdd, 0xf8, 0xba, 0xb2, 0xff, 0x21, 0x48,
+	0x5b, 0xcd, 0x90, 0xed, 0x86, 0xde, 0x05, 0xa0, 0x2f, 0x81, 0x5d, 0x75, 0x30, 0xb6, 0xb6, 0x08,
+	0x6f, 0xe7, 0x28, 0x68, 0xb9, 0xb6, 0xee, 0x4f, 0x71, 0xb2, 0x74, 0xe3, 0x0e, 0x1c, 0x83, 0xee,
+	0xa6, 0x3f, 0xc5, 0xab, 0x6a, 0x1f, 0x3b, 0xe1, 0xcf, 0xc5, 0xf3, 0x3f, 0x03, 0x00, 0xdf, 0x2c,
+	0xdf, 0xe1, 0x75, 0x08, 0x00, 0x00,
+}
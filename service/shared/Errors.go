@@ -8,9 +8,11 @@ var (
 	ErrDuplicate      = errors.New("duplicate")
 
 	ErrDomainConstraintsViolation = errors.New("domain constraints violation")
+	ErrTooManyAttempts            = errors.New("too many attempts")
 
 	ErrMaxRetriesExceeded  = errors.New("max retries exceeded")
 	ErrConcurrencyConflict = errors.New("concurrency conflict")
+	ErrVersionMismatch     = errors.New("version mismatch")
 
 	ErrMarshalingFailed   = errors.New("marshaling failed")
 	ErrUnmarshalingFailed = errors.New("unmarshaling failed")
@@ -1,10 +1,13 @@
 package shared
 
 import (
+	"context"
+
 	"github.com/cockroachdb/errors"
 )
 
-func RetryOnConcurrencyConflict(originalFunc func() error, maxRetries uint8) error {
+// RetryOnConcurrencyConflict stops retrying when ctx is done, because nobody waits for the result anymore.
+func RetryOnConcurrencyConflict(ctx context.Context, originalFunc func() error, maxRetries uint8) error {
 	var err error
 	var retries uint8
 
@@ -17,6 +20,10 @@ func RetryOnConcurrencyConflict(originalFunc func() error, maxRetries uint8) err
 		if !errors.Is(err, ErrConcurrencyConflict) {
 			return err // don't retry for different errors
 		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.WithSecondaryError(errors.Wrap(ctxErr, "stopped retrying"), err)
+		}
 	}
 
 	return errors.Wrap(err, ErrMaxRetriesExceeded.Error())
@@ -0,0 +1,4 @@
+package es
+
+// BuildSnapshot folds an EventStream into a snapshot, which can replace all events up to its stream version.
+type BuildSnapshot func(eventStream EventStream) DomainEvent
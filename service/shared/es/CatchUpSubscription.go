@@ -0,0 +1,125 @@
+package es
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+type HandleGlobalEvent func(ctx context.Context, event GlobalEvent) error
+
+// CatchUpSubscription reads all events in global order, starting after a checkpoint.
+// Once it has caught up, it keeps polling for new events (live tailing).
+//
+// Global positions come from a serial column, which can have gaps:
+// a transaction which has not committed yet holds a lower position than a newer one which already committed,
+// and a rolled back transaction leaves a permanent gap. The subscription never delivers past a gap until
+// either the missing event shows up or the gap is older than gapTimeout, so gapTimeout must be longer than
+// the longest transaction which appends events.
+type CatchUpSubscription struct {
+	readGlobalEvents ReadGlobalEvents
+	handleEvent      HandleGlobalEvent
+	checkpoint       uint64
+	batchSize        uint
+	pollInterval     time.Duration
+	gapTimeout       time.Duration
+	wakeUp           chan struct{}
+}
+
+func NewCatchUpSubscription(
+	readGlobalEvents ReadGlobalEvents,
+	handleEvent HandleGlobalEvent,
+	fromCheckpoint uint64,
+	batchSize uint,
+	pollInterval time.Duration,
+	gapTimeout time.Duration,
+) *CatchUpSubscription {
+
+	return &CatchUpSubscription{
+		readGlobalEvents: readGlobalEvents,
+		handleEvent:      handleEvent,
+		checkpoint:       fromCheckpoint,
+		batchSize:        batchSize,
+		pollInterval:     pollInterval,
+		gapTimeout:       gapTimeout,
+		wakeUp:           make(chan struct{}, 1),
+	}
+}
+
+// Run delivers events until ctx is cancelled or an error occurs.
+// If handleEvent fails, Run returns its error and the checkpoint stays at the last handled event,
+// so a subscription which is restarted from Checkpoint() delivers the failed event again (at-least-once).
+func (s *CatchUpSubscription) Run(ctx context.Context) error {
+	wrapWithMsg := "catchUpSubscription.Run"
+
+	var gapDetectedAt time.Time
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		events, err := s.readGlobalEvents(ctx, s.Checkpoint(), s.batchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil // cancelled while reading
+			}
+
+			return errors.Wrap(err, wrapWithMsg)
+		}
+
+		isCaughtUp := uint(len(events)) < s.batchSize
+
+		for _, event := range events {
+			if event.GlobalPosition() > s.Checkpoint()+1 {
+				if gapDetectedAt.IsZero() {
+					gapDetectedAt = time.Now()
+				}
+
+				if time.Since(gapDetectedAt) < s.gapTimeout {
+					isCaughtUp = true // wait for the missing events before delivering more
+					break
+				}
+
+				// the gap is older than gapTimeout, so it was left by a rolled back transaction
+			}
+
+			if err = s.handleEvent(ctx, event); err != nil {
+				return errors.Wrap(err, wrapWithMsg)
+			}
+
+			gapDetectedAt = time.Time{}
+			atomic.StoreUint64(&s.checkpoint, event.GlobalPosition())
+		}
+
+		if isCaughtUp {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(s.pollInterval):
+			case <-s.wakeUp:
+			}
+		}
+	}
+}
+
+// WakeUp makes a waiting subscription poll immediately, e.g. when an es.ChangeNotification was received.
+func (s *CatchUpSubscription) WakeUp() {
+	select {
+	case s.wakeUp <- struct{}{}:
+	default: // a wake up is already pending
+	}
+}
+
+// ContinueAfter lets a stopped subscription resume after another checkpoint, e.g. the one which its handler stored.
+// It must not be called while Run is running.
+func (s *CatchUpSubscription) ContinueAfter(checkpoint uint64) {
+	atomic.StoreUint64(&s.checkpoint, checkpoint)
+}
+
+// Checkpoint is the global position of the last delivered event, it can be stored to resume the subscription later.
+func (s *CatchUpSubscription) Checkpoint() uint64 {
+	return atomic.LoadUint64(&s.checkpoint)
+}
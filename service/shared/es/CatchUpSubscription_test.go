@@ -0,0 +1,233 @@
+package es_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type someEvent struct {
+	meta es.EventMeta
+}
+
+func (event someEvent) Meta() es.EventMeta {
+	return event.meta
+}
+
+func (event someEvent) IsFailureEvent() bool {
+	return false
+}
+
+func (event someEvent) FailureReason() error {
+	return nil
+}
+
+type fakeGlobalEventFeed struct {
+	mutex  sync.Mutex
+	events []es.GlobalEvent
+}
+
+func (feed *fakeGlobalEventFeed) append(globalPositions ...uint64) {
+	feed.mutex.Lock()
+	defer feed.mutex.Unlock()
+
+	for _, globalPosition := range globalPositions {
+		event := someEvent{meta: es.RebuildEventMeta("", "SomeEvent", "", es.MessageMeta{}, uint(globalPosition))}
+		feed.events = append(feed.events, es.BuildGlobalEvent(globalPosition, es.NewStreamID("some-stream"), event))
+	}
+
+	sort.Slice(feed.events, func(i, j int) bool {
+		return feed.events[i].GlobalPosition() < feed.events[j].GlobalPosition()
+	})
+}
+
+func (feed *fakeGlobalEventFeed) read(_ context.Context, afterPosition uint64, maxEvents uint) ([]es.GlobalEvent, error) {
+	feed.mutex.Lock()
+	defer feed.mutex.Unlock()
+
+	var events []es.GlobalEvent
+
+	for _, event := range feed.events {
+		if event.GlobalPosition() > afterPosition && uint(len(events)) < maxEvents {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+type deliveredEvents struct {
+	mutex           sync.Mutex
+	globalPositions []uint64
+}
+
+func (delivered *deliveredEvents) handle(_ context.Context, event es.GlobalEvent) error {
+	delivered.mutex.Lock()
+	defer delivered.mutex.Unlock()
+
+	delivered.globalPositions = append(delivered.globalPositions, event.GlobalPosition())
+
+	return nil
+}
+
+func (delivered *deliveredEvents) get() []uint64 {
+	delivered.mutex.Lock()
+	defer delivered.mutex.Unlock()
+
+	return append([]uint64{}, delivered.globalPositions...)
+}
+
+func (delivered *deliveredEvents) waitFor(numEvents int) []uint64 {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if len(delivered.get()) >= numEvents {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	return delivered.get()
+}
+
+func TestCatchUpSubscription(t *testing.T) {
+	Convey("Prepare test artifacts", t, func() {
+		feed := &fakeGlobalEventFeed{}
+		delivered := &deliveredEvents{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		runSubscription := func(subscription *es.CatchUpSubscription) chan error {
+			done := make(chan error, 1)
+			go func() { done <- subscription.Run(ctx) }()
+
+			return done
+		}
+
+		Convey("Given a feed with events 1 to 5", func() {
+			feed.append(1, 2, 3, 4, 5)
+
+			Convey("When a subscription is started after checkpoint 2 with a batch size of 2", func() {
+				subscription := es.NewCatchUpSubscription(feed.read, delivered.handle, 2, 2, time.Millisecond, time.Second)
+				done := runSubscription(subscription)
+
+				Convey("Then it should deliver events 3 to 5 in order", func() {
+					So(delivered.waitFor(3), ShouldResemble, []uint64{3, 4, 5})
+					So(subscription.Checkpoint(), ShouldEqual, 5)
+
+					Convey("And when new events are appended", func() {
+						feed.append(6, 7)
+
+						Convey("Then it should deliver them as well", func() {
+							So(delivered.waitFor(5), ShouldResemble, []uint64{3, 4, 5, 6, 7})
+						})
+					})
+				})
+
+				Reset(func() {
+					cancel()
+					So(<-done, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("Given a subscription with a long poll interval which has caught up", func() {
+			feed.append(1)
+			subscription := es.NewCatchUpSubscription(feed.read, delivered.handle, 0, 10, time.Minute, time.Second)
+			done := runSubscription(subscription)
+			So(delivered.waitFor(1), ShouldResemble, []uint64{1})
+
+			Convey("When a new event is appended and the subscription is woken up", func() {
+				feed.append(2)
+				subscription.WakeUp()
+
+				Convey("Then it should deliver the new event without waiting for the poll interval", func() {
+					So(delivered.waitFor(2), ShouldResemble, []uint64{1, 2})
+				})
+			})
+
+			Reset(func() {
+				cancel()
+				So(<-done, ShouldBeNil)
+			})
+		})
+
+		Convey("Given a feed with a gap which is younger than the gap timeout", func() {
+			feed.append(1, 2, 4)
+
+			Convey("When a subscription is started", func() {
+				subscription := es.NewCatchUpSubscription(feed.read, delivered.handle, 0, 10, time.Millisecond, time.Minute)
+				done := runSubscription(subscription)
+
+				Convey("Then it should only deliver the events before the gap", func() {
+					So(delivered.waitFor(2), ShouldResemble, []uint64{1, 2})
+					time.Sleep(10 * time.Millisecond)
+					So(delivered.get(), ShouldResemble, []uint64{1, 2})
+					So(subscription.Checkpoint(), ShouldEqual, 2)
+
+					Convey("And when the missing event shows up", func() {
+						feed.append(3)
+
+						Convey("Then it should deliver all events in order", func() {
+							So(delivered.waitFor(4), ShouldResemble, []uint64{1, 2, 3, 4})
+						})
+					})
+				})
+
+				Reset(func() {
+					cancel()
+					So(<-done, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("Given a feed with a gap which is older than the gap timeout", func() {
+			feed.append(1, 3)
+
+			Convey("When a subscription is started", func() {
+				subscription := es.NewCatchUpSubscription(feed.read, delivered.handle, 0, 10, time.Millisecond, 20*time.Millisecond)
+				done := runSubscription(subscription)
+
+				Convey("Then it should skip the gap and deliver all events", func() {
+					So(delivered.waitFor(2), ShouldResemble, []uint64{1, 3})
+				})
+
+				Reset(func() {
+					cancel()
+					So(<-done, ShouldBeNil)
+				})
+			})
+		})
+
+		Convey("Given a handler which fails for event 2", func() {
+			feed.append(1, 2, 3)
+
+			handleEvent := func(_ context.Context, event es.GlobalEvent) error {
+				if event.GlobalPosition() == 2 {
+					return errors.New("mocked error")
+				}
+
+				return delivered.handle(ctx, event)
+			}
+
+			Convey("When a subscription is started", func() {
+				subscription := es.NewCatchUpSubscription(feed.read, handleEvent, 0, 10, time.Millisecond, time.Second)
+				err := subscription.Run(ctx)
+
+				Convey("Then it should fail and keep the checkpoint at the last handled event", func() {
+					So(err, ShouldBeError)
+					So(delivered.get(), ShouldResemble, []uint64{1})
+					So(subscription.Checkpoint(), ShouldEqual, 1)
+				})
+			})
+		})
+
+		Reset(func() {
+			cancel()
+		})
+	})
+}
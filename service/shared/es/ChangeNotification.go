@@ -0,0 +1,72 @@
+package es
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ChangeNotification tells subscribers that an event was appended to a stream.
+// Notifications are only hints, they can be lost. If the notifier can't tell what changed,
+// e.g. while it is disconnected, it sends notifications with IsPollRequest() == true instead,
+// which means subscribers should poll the event store themselves.
+type ChangeNotification struct {
+	streamID      StreamID
+	streamVersion uint
+	isPollRequest bool
+}
+
+func BuildChangeNotification(streamID StreamID, streamVersion uint) ChangeNotification {
+	return ChangeNotification{
+		streamID:      streamID,
+		streamVersion: streamVersion,
+	}
+}
+
+func BuildPollRequest() ChangeNotification {
+	return ChangeNotification{isPollRequest: true}
+}
+
+func (notification ChangeNotification) StreamID() StreamID {
+	return notification.streamID
+}
+
+func (notification ChangeNotification) StreamVersion() uint {
+	return notification.streamVersion
+}
+
+func (notification ChangeNotification) IsPollRequest() bool {
+	return notification.isPollRequest
+}
+
+type changeNotificationForJSON struct {
+	StreamID      string `json:"streamID"`
+	StreamVersion uint   `json:"streamVersion"`
+}
+
+// MarshalChangeNotification builds the payload which SQLEventStore sends (if the SQLDialect supports notifications) with each appended event.
+func MarshalChangeNotification(notification ChangeNotification) string {
+	data := changeNotificationForJSON{
+		StreamID:      notification.StreamID().String(),
+		StreamVersion: notification.StreamVersion(),
+	}
+
+	json, _ := jsoniter.ConfigFastest.MarshalToString(data) // err intentionally ignored - it can't happen with these data types
+
+	return json
+}
+
+func UnmarshalChangeNotification(payload string) (ChangeNotification, error) {
+	data := changeNotificationForJSON{}
+
+	if err := jsoniter.ConfigFastest.UnmarshalFromString(payload, &data); err != nil {
+		return ChangeNotification{}, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, "unmarshalChangeNotification")
+	}
+
+	if data.StreamID == "" {
+		err := errors.New("change notification has no streamID")
+		return ChangeNotification{}, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, "unmarshalChangeNotification")
+	}
+
+	return BuildChangeNotification(NewStreamID(data.StreamID), data.StreamVersion), nil
+}
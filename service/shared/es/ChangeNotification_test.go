@@ -0,0 +1,35 @@
+package es_test
+
+import (
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMarshalAndUnmarshalChangeNotification(t *testing.T) {
+	Convey("Given a ChangeNotification", t, func() {
+		notification := es.BuildChangeNotification(es.NewStreamID("customer-123"), 5)
+
+		Convey("When it is marshaled and unmarshaled", func() {
+			unmarshaled, err := es.UnmarshalChangeNotification(es.MarshalChangeNotification(notification))
+
+			Convey("Then it should be the original ChangeNotification", func() {
+				So(err, ShouldBeNil)
+				So(unmarshaled, ShouldResemble, notification)
+				So(unmarshaled.IsPollRequest(), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("When an invalid payload is unmarshaled", t, func() {
+		_, err := es.UnmarshalChangeNotification(`{"streamVersion": 5}`)
+
+		Convey("Then it should fail", func() {
+			So(err, ShouldBeError)
+			So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+		})
+	})
+}
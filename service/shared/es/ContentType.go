@@ -0,0 +1,8 @@
+package es
+
+// The content types which MarshalDomainEvent implementations can produce.
+// JSON payloads must be json objects, so they can be told apart from protobuf messages by their first byte.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
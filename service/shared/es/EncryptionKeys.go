@@ -0,0 +1,14 @@
+package es
+
+import "context"
+
+// RetrieveOrCreateEncryptionKey returns the key of a subject (e.g. a Customer), a new key is created on first use.
+// It must fail with shared.ErrNotFound if the key of this subject was shredded, so that no new key is created.
+// If ctx carries a transaction (see SQLTxFromContext) the key must be created inside of it.
+type RetrieveOrCreateEncryptionKey func(ctx context.Context, subjectID string) ([]byte, error)
+
+// RetrieveEncryptionKey must fail with shared.ErrNotFound if the subject has no key or it was shredded.
+type RetrieveEncryptionKey func(ctx context.Context, subjectID string) ([]byte, error)
+
+// ShredEncryptionKey destroys the key of a subject, so that all PII which was encrypted with it becomes unreadable.
+type ShredEncryptionKey func(ctx context.Context, subjectID string) error
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -11,13 +13,16 @@ const (
 )
 
 type EventMeta struct {
+	eventID       string
 	eventName     string
 	occurredAt    string
+	messageMeta   MessageMeta
 	streamVersion uint
 }
 
 func BuildEventMeta(
 	event DomainEvent,
+	messageMeta MessageMeta,
 	streamVersion uint,
 ) EventMeta {
 
@@ -26,8 +31,10 @@ func BuildEventMeta(
 	eventName := eventTypeParts[len(eventTypeParts)-1]
 
 	meta := EventMeta{
+		eventID:       uuid.New().String(),
 		eventName:     eventName,
 		occurredAt:    time.Now().Format(metaTimestampFormat),
+		messageMeta:   messageMeta,
 		streamVersion: streamVersion,
 	}
 
@@ -35,18 +42,26 @@ func BuildEventMeta(
 }
 
 func RebuildEventMeta(
+	eventID string,
 	eventName string,
 	occurredAt string,
+	messageMeta MessageMeta,
 	streamVersion uint,
 ) EventMeta {
 
 	return EventMeta{
+		eventID:       eventID,
 		eventName:     eventName,
 		occurredAt:    occurredAt,
+		messageMeta:   messageMeta,
 		streamVersion: streamVersion,
 	}
 }
 
+func (eventMeta EventMeta) EventID() string {
+	return eventMeta.eventID
+}
+
 func (eventMeta EventMeta) EventName() string {
 	return eventMeta.eventName
 }
@@ -55,6 +70,10 @@ func (eventMeta EventMeta) OccurredAt() string {
 	return eventMeta.occurredAt
 }
 
+func (eventMeta EventMeta) MessageMeta() MessageMeta {
+	return eventMeta.messageMeta
+}
+
 func (eventMeta EventMeta) StreamVersion() uint {
 	return eventMeta.streamVersion
 }
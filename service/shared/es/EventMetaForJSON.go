@@ -1,6 +1,12 @@
 package es
 
+// SchemaVersion is missing in payloads which were stored before it was introduced, those have schema version 1.
 type EventMetaForJSON struct {
-	EventName  string `json:"eventName"`
-	OccurredAt string `json:"occurredAt"`
+	EventID       string `json:"eventID,omitempty"`
+	EventName     string `json:"eventName"`
+	OccurredAt    string `json:"occurredAt"`
+	CorrelationID string `json:"correlationID,omitempty"`
+	CausationID   string `json:"causationID,omitempty"`
+	Actor         string `json:"actor,omitempty"`
+	SchemaVersion uint   `json:"schemaVersion,omitempty"`
 }
@@ -0,0 +1,28 @@
+package es
+
+// GlobalEvent is a DomainEvent together with its position in the global order of all streams.
+type GlobalEvent struct {
+	globalPosition uint64
+	streamID       StreamID
+	event          DomainEvent
+}
+
+func BuildGlobalEvent(globalPosition uint64, streamID StreamID, event DomainEvent) GlobalEvent {
+	return GlobalEvent{
+		globalPosition: globalPosition,
+		streamID:       streamID,
+		event:          event,
+	}
+}
+
+func (globalEvent GlobalEvent) GlobalPosition() uint64 {
+	return globalEvent.globalPosition
+}
+
+func (globalEvent GlobalEvent) StreamID() StreamID {
+	return globalEvent.streamID
+}
+
+func (globalEvent GlobalEvent) Event() DomainEvent {
+	return globalEvent.event
+}
@@ -0,0 +1,214 @@
+package es
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json" // jsoniter can't handle maps with the reflect2 version we are stuck with
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+)
+
+// ComputeEventHash chains an event to the hash of its predecessor in the same stream (empty for the first event).
+// JSON payloads are canonicalized first, because a JSONB column does not give back the bytes which were inserted.
+func ComputeEventHash(
+	previousHash string,
+	streamID StreamID,
+	streamVersion uint,
+	eventName string,
+	contentType string,
+	payload []byte,
+) (string, error) {
+
+	canonicalPayload := payload
+
+	if contentType == ContentTypeJSON {
+		var err error
+
+		if canonicalPayload, err = canonicalizeJSON(payload); err != nil {
+			return "", shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, "computeEventHash")
+		}
+	}
+
+	hash := sha256.New()
+
+	for _, part := range [][]byte{
+		[]byte(previousHash),
+		[]byte(streamID.String()),
+		[]byte(strconv.FormatUint(uint64(streamVersion), 10)),
+		[]byte(eventName),
+		canonicalPayload,
+	} {
+		_, _ = hash.Write([]byte(strconv.Itoa(len(part)) + ":")) // length prefixes make the concatenation unambiguous
+		_, _ = hash.Write(part)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// canonicalizeJSON sorts all object keys, strips insignificant whitespace and writes numbers like JSONB does.
+func canonicalizeJSON(payload []byte) ([]byte, error) {
+	var data interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(canonicalizeJSONNumbers(data)); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buffer.Bytes(), "\n"), nil
+}
+
+func canonicalizeJSONNumbers(data interface{}) interface{} {
+	switch actual := data.(type) {
+	case map[string]interface{}:
+		for key, value := range actual {
+			actual[key] = canonicalizeJSONNumbers(value)
+		}
+	case []interface{}:
+		for idx, value := range actual {
+			actual[idx] = canonicalizeJSONNumbers(value)
+		}
+	case json.Number:
+		return json.Number(canonicalizeJSONNumber(actual.String()))
+	}
+
+	return data
+}
+
+// canonicalizeJSONNumber writes a (valid) JSON number like the numeric type of Postgres, which JSONB uses:
+// without exponent, with as many decimal places as the input has after applying the exponent, e.g. 1e2 -> 100,
+// 1.50 -> 1.50, 1.5e-1 -> 0.15 and -0 -> 0.
+func canonicalizeJSONNumber(number string) string {
+	negative := strings.HasPrefix(number, "-")
+	mantissa := strings.TrimPrefix(number, "-")
+	exponent := 0
+
+	if idx := strings.IndexAny(mantissa, "eE"); idx >= 0 {
+		exponent, _ = strconv.Atoi(strings.TrimPrefix(mantissa[idx+1:], "+"))
+		mantissa = mantissa[:idx]
+	}
+
+	integerPart, fractionPart := mantissa, ""
+
+	if idx := strings.Index(mantissa, "."); idx >= 0 {
+		integerPart, fractionPart = mantissa[:idx], mantissa[idx+1:]
+	}
+
+	digits := integerPart + fractionPart
+	pointPosition := len(integerPart) + exponent
+
+	if pointPosition < 0 {
+		digits = strings.Repeat("0", -pointPosition) + digits
+		pointPosition = 0
+	}
+
+	if pointPosition > len(digits) {
+		digits += strings.Repeat("0", pointPosition-len(digits))
+	}
+
+	integerPart = strings.TrimLeft(digits[:pointPosition], "0")
+	if integerPart == "" {
+		integerPart = "0"
+	}
+
+	canonical := integerPart
+	if fractionPart = digits[pointPosition:]; fractionPart != "" {
+		canonical += "." + fractionPart
+	}
+
+	if negative && strings.Trim(digits, "0") != "" {
+		canonical = "-" + canonical
+	}
+
+	return canonical
+}
+
+// HashChainBreak is a stored event whose hash does not prove that it (or its predecessor) is unmodified.
+type HashChainBreak struct {
+	StreamID      StreamID
+	StreamVersion uint
+	Reason        string
+}
+
+func (b HashChainBreak) String() string {
+	return fmt.Sprintf("%s@%d: %s", b.StreamID.String(), b.StreamVersion, b.Reason)
+}
+
+// HashChainVerifier checks events in the order of (stream_id, stream_version), one stream after the other.
+// Events which were stored before the hash chain was introduced have no hash, they are only accepted at the
+// beginning of a stream - the chain then starts with the first hashed event.
+type HashChainVerifier struct {
+	currentStreamID string
+	previousVersion uint
+	previousHash    string
+	breaks          []HashChainBreak
+}
+
+func NewHashChainVerifier() *HashChainVerifier {
+	return &HashChainVerifier{}
+}
+
+func (v *HashChainVerifier) Verify(
+	streamID StreamID,
+	streamVersion uint,
+	eventName string,
+	contentType string,
+	payload []byte,
+	storedHash string,
+) {
+
+	if streamID.String() != v.currentStreamID {
+		v.currentStreamID = streamID.String()
+		v.previousVersion = 0
+		v.previousHash = ""
+	}
+
+	defer func() {
+		v.previousVersion = streamVersion
+		v.previousHash = storedHash
+	}()
+
+	if streamVersion != v.previousVersion+1 {
+		v.addBreak(streamID, streamVersion, fmt.Sprintf("expected version %d", v.previousVersion+1))
+	}
+
+	if storedHash == "" {
+		if v.previousHash != "" {
+			v.addBreak(streamID, streamVersion, "hash is missing")
+		}
+
+		return
+	}
+
+	expectedHash, err := ComputeEventHash(v.previousHash, streamID, streamVersion, eventName, contentType, payload)
+	if err != nil {
+		v.addBreak(streamID, streamVersion, "payload is not valid json")
+
+		return
+	}
+
+	if expectedHash != storedHash {
+		v.addBreak(streamID, streamVersion, "hash does not match")
+	}
+}
+
+func (v *HashChainVerifier) Breaks() []HashChainBreak {
+	return v.breaks
+}
+
+func (v *HashChainVerifier) addBreak(streamID StreamID, streamVersion uint, reason string) {
+	v.breaks = append(v.breaks, HashChainBreak{StreamID: streamID, StreamVersion: streamVersion, Reason: reason})
+}
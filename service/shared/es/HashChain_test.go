@@ -0,0 +1,165 @@
+package es_test
+
+import (
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestComputeEventHash(t *testing.T) {
+	Convey("Given a payload as it was marshaled and as JSONB gives it back", t, func() {
+		streamID := es.NewStreamID("customer-123")
+		marshaled := []byte(`{"customerID":"123","meta":{"streamVersion":1,"eventName":"CustomerRegistered"}}`)
+		fromJSONB := []byte(`{"meta": {"eventName": "CustomerRegistered", "streamVersion": 1}, "customerID": "123"}`)
+
+		Convey("When the hashes are computed", func() {
+			hashOfMarshaled, err := es.ComputeEventHash("", streamID, 1, "CustomerRegistered", es.ContentTypeJSON, marshaled)
+			So(err, ShouldBeNil)
+
+			hashFromJSONB, err := es.ComputeEventHash("", streamID, 1, "CustomerRegistered", es.ContentTypeJSON, fromJSONB)
+			So(err, ShouldBeNil)
+
+			Convey("Then they should be equal", func() {
+				So(hashOfMarshaled, ShouldHaveLength, 64)
+				So(hashFromJSONB, ShouldEqual, hashOfMarshaled)
+			})
+
+			Convey("And when the previous hash differs", func() {
+				otherHash, err := es.ComputeEventHash(hashOfMarshaled, streamID, 1, "CustomerRegistered", es.ContentTypeJSON, marshaled)
+				So(err, ShouldBeNil)
+
+				Convey("Then the hash should differ", func() {
+					So(otherHash, ShouldNotEqual, hashOfMarshaled)
+				})
+			})
+		})
+	})
+
+	Convey("Given a payload with numbers which are not written like JSONB gives them back", t, func() {
+		streamID := es.NewStreamID("customer-123")
+		marshaled := []byte(`{"a":1e2,"b":1.50,"c":1.5e-1,"d":-0,"e":1.50E+1,"f":[0.001e1,-12E1,7]}`)
+		fromJSONB := []byte(`{"a": 100, "b": 1.50, "c": 0.15, "d": 0, "e": 15.0, "f": [0.01, -120, 7]}`)
+
+		Convey("When the hashes are computed", func() {
+			hashOfMarshaled, err := es.ComputeEventHash("", streamID, 1, "SomeEvent", es.ContentTypeJSON, marshaled)
+			So(err, ShouldBeNil)
+
+			hashFromJSONB, err := es.ComputeEventHash("", streamID, 1, "SomeEvent", es.ContentTypeJSON, fromJSONB)
+			So(err, ShouldBeNil)
+
+			Convey("Then they should be equal", func() {
+				So(hashFromJSONB, ShouldEqual, hashOfMarshaled)
+			})
+		})
+
+		Convey("When the hash of a payload with a different number is computed", func() {
+			hashOfMarshaled, err := es.ComputeEventHash("", streamID, 1, "SomeEvent", es.ContentTypeJSON, marshaled)
+			So(err, ShouldBeNil)
+
+			different := []byte(`{"a":1e3,"b":1.50,"c":1.5e-1,"d":-0,"e":1.50E+1,"f":[0.001e1,-12E1,7]}`)
+			hashOfDifferent, err := es.ComputeEventHash("", streamID, 1, "SomeEvent", es.ContentTypeJSON, different)
+			So(err, ShouldBeNil)
+
+			Convey("Then the hash should differ", func() {
+				So(hashOfDifferent, ShouldNotEqual, hashOfMarshaled)
+			})
+		})
+	})
+
+	Convey("When the hash of an invalid payload is computed", t, func() {
+		_, err := es.ComputeEventHash("", es.NewStreamID("customer-123"), 1, "CustomerRegistered", es.ContentTypeJSON, []byte(`{`))
+
+		Convey("Then it should fail", func() {
+			So(err, ShouldBeError)
+		})
+	})
+}
+
+func TestHashChainVerifier(t *testing.T) {
+	Convey("Given a hash chain of two streams", t, func() {
+		type row struct {
+			streamID      es.StreamID
+			streamVersion uint
+			payload       []byte
+			hash          string
+		}
+
+		var rows []*row
+		var previousHash string
+
+		for _, streamID := range []es.StreamID{es.NewStreamID("customer-1"), es.NewStreamID("customer-2")} {
+			previousHash = ""
+
+			for version := uint(1); version <= 3; version++ {
+				payload := []byte(`{"version":"` + string(rune('0'+version)) + `"}`)
+				hash, err := es.ComputeEventHash(previousHash, streamID, version, "SomeEvent", es.ContentTypeJSON, payload)
+				So(err, ShouldBeNil)
+
+				rows = append(rows, &row{streamID: streamID, streamVersion: version, payload: payload, hash: hash})
+				previousHash = hash
+			}
+		}
+
+		verify := func(rows []*row) []es.HashChainBreak {
+			verifier := es.NewHashChainVerifier()
+
+			for _, r := range rows {
+				verifier.Verify(r.streamID, r.streamVersion, "SomeEvent", es.ContentTypeJSON, r.payload, r.hash)
+			}
+
+			return verifier.Breaks()
+		}
+
+		Convey("When it is verified unchanged", func() {
+			breaks := verify(rows)
+
+			Convey("Then there should be no breaks", func() {
+				So(breaks, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a payload was modified", func() {
+			rows[1].payload = []byte(`{"version":"9"}`)
+			breaks := verify(rows)
+
+			Convey("Then the modified event should be reported", func() {
+				So(breaks, ShouldHaveLength, 1)
+				So(breaks[0].StreamID, ShouldResemble, rows[1].streamID)
+				So(breaks[0].StreamVersion, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When an event was removed", func() {
+			breaks := verify(append(rows[:1:1], rows[2:]...))
+
+			Convey("Then the successor should be reported", func() {
+				So(breaks, ShouldHaveLength, 2) // version gap and hash mismatch
+				So(breaks[0].StreamVersion, ShouldEqual, 3)
+				So(breaks[1].StreamVersion, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When a hash was removed in the middle of a stream", func() {
+			rows[4].hash = ""
+			breaks := verify(rows)
+
+			Convey("Then the event without hash and its successor should be reported", func() {
+				So(breaks, ShouldHaveLength, 2)
+				So(breaks[0].StreamVersion, ShouldEqual, 2)
+				So(breaks[1].StreamVersion, ShouldEqual, 3)
+			})
+		})
+
+		Convey("When a stream starts with events which were stored before the hash chain was introduced", func() {
+			rows[0].hash = ""
+			rows[1].hash, _ = es.ComputeEventHash("", rows[1].streamID, 2, "SomeEvent", es.ContentTypeJSON, rows[1].payload)
+			rows[2].hash, _ = es.ComputeEventHash(rows[1].hash, rows[2].streamID, 3, "SomeEvent", es.ContentTypeJSON, rows[2].payload)
+			breaks := verify(rows)
+
+			Convey("Then there should be no breaks", func() {
+				So(breaks, ShouldBeEmpty)
+			})
+		})
+	})
+}
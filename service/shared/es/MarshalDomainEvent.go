@@ -1,3 +1,5 @@
 package es
 
-type MarshalDomainEvent func(event DomainEvent) ([]byte, error)
+import "context"
+
+type MarshalDomainEvent func(ctx context.Context, event DomainEvent) ([]byte, error)
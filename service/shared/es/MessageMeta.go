@@ -0,0 +1,42 @@
+package es
+
+// MessageMeta describes the request which caused a command, it is copied into the meta of all resulting events.
+// CorrelationID groups all messages which belong to one business process, CausationID is the ID of the message
+// which directly caused this one and Actor is the principal who acted.
+// The optional IdempotencyKey lets clients retry a command safely, it is not stored with the events.
+type MessageMeta struct {
+	correlationID  string
+	causationID    string
+	actor          string
+	idempotencyKey string
+}
+
+func BuildMessageMeta(correlationID, causationID, actor string) MessageMeta {
+	return MessageMeta{
+		correlationID: correlationID,
+		causationID:   causationID,
+		actor:         actor,
+	}
+}
+
+func (messageMeta MessageMeta) CorrelationID() string {
+	return messageMeta.correlationID
+}
+
+func (messageMeta MessageMeta) CausationID() string {
+	return messageMeta.causationID
+}
+
+func (messageMeta MessageMeta) Actor() string {
+	return messageMeta.actor
+}
+
+func (messageMeta MessageMeta) WithIdempotencyKey(idempotencyKey string) MessageMeta {
+	messageMeta.idempotencyKey = idempotencyKey
+
+	return messageMeta
+}
+
+func (messageMeta MessageMeta) IdempotencyKey() string {
+	return messageMeta.idempotencyKey
+}
@@ -0,0 +1,62 @@
+package es
+
+// OutboxMessage is a marshaled DomainEvent which was stored in the outbox in the same transaction as the event itself.
+type OutboxMessage struct {
+	id            uint64
+	streamID      StreamID
+	eventName     string
+	occurredAt    string
+	streamVersion uint
+	contentType   string
+	payload       []byte
+}
+
+func RebuildOutboxMessage(
+	id uint64,
+	streamID StreamID,
+	eventName string,
+	occurredAt string,
+	streamVersion uint,
+	contentType string,
+	payload []byte,
+) OutboxMessage {
+
+	return OutboxMessage{
+		id:            id,
+		streamID:      streamID,
+		eventName:     eventName,
+		occurredAt:    occurredAt,
+		streamVersion: streamVersion,
+		contentType:   contentType,
+		payload:       payload,
+	}
+}
+
+func (message OutboxMessage) ID() uint64 {
+	return message.id
+}
+
+func (message OutboxMessage) StreamID() StreamID {
+	return message.streamID
+}
+
+func (message OutboxMessage) EventName() string {
+	return message.eventName
+}
+
+func (message OutboxMessage) OccurredAt() string {
+	return message.occurredAt
+}
+
+func (message OutboxMessage) StreamVersion() uint {
+	return message.streamVersion
+}
+
+// ContentType is one of the es.ContentType* constants.
+func (message OutboxMessage) ContentType() string {
+	return message.contentType
+}
+
+func (message OutboxMessage) Payload() []byte {
+	return message.payload
+}
@@ -0,0 +1,136 @@
+package es
+
+import (
+	"context"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+// OutboxRelay publishes the OutboxMessages which were written together with the events.
+//
+// Delivery is at-least-once: a message is only marked as published after the publisher succeeded,
+// so it is published again if marking fails or the relay stops in between.
+// Messages of the same stream are published in order: if publishing fails, all further messages of that stream
+// are held back until the failed one was published, while other streams continue.
+// Failed messages are retried with an exponential backoff, starting at pollInterval up to maxRetryInterval.
+type OutboxRelay struct {
+	readOutboxMessages           ReadOutboxMessages
+	publishOutboxMessage         PublishOutboxMessage
+	markOutboxMessageAsPublished MarkOutboxMessageAsPublished
+	logger                       *shared.Logger
+	batchSize                    uint
+	pollInterval                 time.Duration
+	maxRetryInterval             time.Duration
+}
+
+func NewOutboxRelay(
+	readOutboxMessages ReadOutboxMessages,
+	publishOutboxMessage PublishOutboxMessage,
+	markOutboxMessageAsPublished MarkOutboxMessageAsPublished,
+	logger *shared.Logger,
+	batchSize uint,
+	pollInterval time.Duration,
+	maxRetryInterval time.Duration,
+) *OutboxRelay {
+
+	return &OutboxRelay{
+		readOutboxMessages:           readOutboxMessages,
+		publishOutboxMessage:         publishOutboxMessage,
+		markOutboxMessageAsPublished: markOutboxMessageAsPublished,
+		logger:                       logger,
+		batchSize:                    batchSize,
+		pollInterval:                 pollInterval,
+		maxRetryInterval:             maxRetryInterval,
+	}
+}
+
+// Run relays messages until ctx is cancelled or reading from or writing to the outbox fails.
+// Each pass pages through the whole outbox, so that held back messages can't hide the messages of other streams.
+func (relay *OutboxRelay) Run(ctx context.Context) error {
+	wrapWithMsg := "outboxRelay.Run"
+
+	retryInterval := relay.pollInterval
+	afterID := uint64(0)
+	blockedStreams := make(map[string]bool)
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		messages, err := relay.readOutboxMessages(ctx, afterID, relay.batchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil // cancelled while reading, the messages will be read again after a restart
+			}
+
+			return errors.Wrap(err, wrapWithMsg)
+		}
+
+		if err = relay.publish(ctx, messages, blockedStreams); err != nil {
+			if ctx.Err() != nil {
+				return nil // cancelled while marking, the message will be published again (at-least-once)
+			}
+
+			return errors.Wrap(err, wrapWithMsg)
+		}
+
+		if uint(len(messages)) == relay.batchSize {
+			afterID = messages[len(messages)-1].ID()
+
+			continue // read the next page of this pass
+		}
+
+		waitFor := relay.pollInterval
+
+		if len(blockedStreams) > 0 {
+			waitFor = retryInterval
+
+			if retryInterval *= 2; retryInterval > relay.maxRetryInterval {
+				retryInterval = relay.maxRetryInterval
+			}
+		} else {
+			retryInterval = relay.pollInterval
+		}
+
+		afterID = 0
+		blockedStreams = make(map[string]bool)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(waitFor):
+		}
+	}
+}
+
+// publish adds the streams which failed to blockedStreams, their further messages are skipped.
+func (relay *OutboxRelay) publish(ctx context.Context, messages []OutboxMessage, blockedStreams map[string]bool) error {
+	for _, message := range messages {
+		if blockedStreams[message.StreamID().String()] {
+			continue
+		}
+
+		if err := relay.publishOutboxMessage(ctx, message); err != nil {
+			relay.logger.Warnf(
+				"outboxRelay: failed to publish [%s] of stream [%s] with version [%d], will retry: %s",
+				message.EventName(),
+				message.StreamID().String(),
+				message.StreamVersion(),
+				err,
+			)
+
+			blockedStreams[message.StreamID().String()] = true
+
+			continue
+		}
+
+		if err := relay.markOutboxMessageAsPublished(ctx, message.ID()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
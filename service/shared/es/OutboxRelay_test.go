@@ -0,0 +1,195 @@
+package es_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeOutbox struct {
+	mutex    sync.Mutex
+	messages []es.OutboxMessage
+}
+
+func (outbox *fakeOutbox) add(streamID string, streamVersion uint) {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	id := uint64(len(outbox.messages) + 1)
+	message := es.RebuildOutboxMessage(id, es.NewStreamID(streamID), "SomeEvent", "", streamVersion, es.ContentTypeJSON, []byte("{}"))
+	outbox.messages = append(outbox.messages, message)
+}
+
+func (outbox *fakeOutbox) read(_ context.Context, afterID uint64, maxMessages uint) ([]es.OutboxMessage, error) {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	var messages []es.OutboxMessage
+
+	for _, message := range outbox.messages {
+		if message.ID() > afterID && uint(len(messages)) < maxMessages {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, nil
+}
+
+func (outbox *fakeOutbox) markAsPublished(_ context.Context, id uint64) error {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	for idx, message := range outbox.messages {
+		if message.ID() == id {
+			outbox.messages = append(outbox.messages[:idx], outbox.messages[idx+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (outbox *fakeOutbox) isEmpty() bool {
+	return outbox.length() == 0
+}
+
+func (outbox *fakeOutbox) length() int {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	return len(outbox.messages)
+}
+
+type publishedMessages struct {
+	mutex        sync.Mutex
+	messages     []string
+	failOnceOn   map[string]bool
+	failAlwaysOn map[string]bool // stream IDs
+}
+
+func (published *publishedMessages) publish(_ context.Context, message es.OutboxMessage) error {
+	published.mutex.Lock()
+	defer published.mutex.Unlock()
+
+	key := fmt.Sprintf("%s/%d", message.StreamID().String(), message.StreamVersion())
+
+	if published.failOnceOn[key] {
+		delete(published.failOnceOn, key)
+		return errors.New("mocked error")
+	}
+
+	if published.failAlwaysOn[message.StreamID().String()] {
+		return errors.New("mocked error")
+	}
+
+	published.messages = append(published.messages, key)
+
+	return nil
+}
+
+func (published *publishedMessages) get() []string {
+	published.mutex.Lock()
+	defer published.mutex.Unlock()
+
+	return append([]string{}, published.messages...)
+}
+
+func TestOutboxRelay(t *testing.T) {
+	Convey("Prepare test artifacts", t, func() {
+		outbox := &fakeOutbox{}
+		published := &publishedMessages{failOnceOn: make(map[string]bool), failAlwaysOn: make(map[string]bool)}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		relay := es.NewOutboxRelay(
+			outbox.read,
+			published.publish,
+			outbox.markAsPublished,
+			shared.NewNilLogger(),
+			10,
+			time.Millisecond,
+			5*time.Millisecond,
+		)
+
+		waitUntilOutboxIsEmpty := func() {
+			for deadline := time.Now().Add(time.Second); time.Now().Before(deadline) && !outbox.isEmpty(); {
+				time.Sleep(time.Millisecond)
+			}
+		}
+
+		done := make(chan error, 1)
+
+		Convey("Given messages of two streams in the outbox", func() {
+			outbox.add("stream-a", 1)
+			outbox.add("stream-b", 1)
+			outbox.add("stream-a", 2)
+			outbox.add("stream-b", 2)
+
+			Convey("When the relay runs", func() {
+				go func() { done <- relay.Run(ctx) }()
+				waitUntilOutboxIsEmpty()
+
+				Convey("Then it should publish all messages in order", func() {
+					So(outbox.isEmpty(), ShouldBeTrue)
+					So(published.get(), ShouldResemble, []string{"stream-a/1", "stream-b/1", "stream-a/2", "stream-b/2"})
+				})
+			})
+
+			Convey("When publishing the first message of one stream fails once", func() {
+				published.failOnceOn["stream-a/1"] = true
+
+				go func() { done <- relay.Run(ctx) }()
+				waitUntilOutboxIsEmpty()
+
+				Convey("Then the other stream should not be held back", func() {
+					So(published.get()[:2], ShouldResemble, []string{"stream-b/1", "stream-b/2"})
+
+					Convey("And the failed stream should be retried in order", func() {
+						So(outbox.isEmpty(), ShouldBeTrue)
+						So(published.get()[2:], ShouldResemble, []string{"stream-a/1", "stream-a/2"})
+					})
+				})
+			})
+
+			Reset(func() {
+				cancel()
+				So(<-done, ShouldBeNil)
+			})
+		})
+
+		Convey("Given more messages of a failing stream than fit into one batch, followed by messages of another stream", func() {
+			for version := uint(1); version <= 25; version++ {
+				outbox.add("stream-a", version)
+			}
+
+			outbox.add("stream-b", 1)
+			outbox.add("stream-b", 2)
+
+			Convey("When publishing the failing stream fails permanently", func() {
+				published.failAlwaysOn["stream-a"] = true
+
+				go func() { done <- relay.Run(ctx) }()
+
+				for deadline := time.Now().Add(time.Second); time.Now().Before(deadline) && outbox.length() > 25; {
+					time.Sleep(time.Millisecond)
+				}
+
+				Convey("Then the other stream should still be published", func() {
+					So(published.get(), ShouldResemble, []string{"stream-b/1", "stream-b/2"})
+					So(outbox.length(), ShouldEqual, 25)
+				})
+			})
+
+			Reset(func() {
+				cancel()
+				So(<-done, ShouldBeNil)
+			})
+		})
+	})
+}
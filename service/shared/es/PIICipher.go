@@ -0,0 +1,97 @@
+package es
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	EncryptionKeyLength = 32 // AES-256
+
+	// RedactedPII replaces PII which can't be decrypted anymore because the key was shredded.
+	RedactedPII = "[redacted]"
+
+	encryptedPIIPrefix = "pii:v1:"
+)
+
+func GenerateEncryptionKey() ([]byte, error) {
+	key := make([]byte, EncryptionKeyLength)
+
+	if _, err := rand.Read(key); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, "generateEncryptionKey")
+	}
+
+	return key, nil
+}
+
+// EncryptPII encrypts with AES-GCM, the result is a prefixed base64 string so that it can replace the plain value.
+func EncryptPII(key []byte, plaintext string) (string, error) {
+	wrapWithMsg := "encryptPII"
+
+	aead, err := buildAEAD(key)
+	if err != nil {
+		return "", errors.Wrap(err, wrapWithMsg)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return encryptedPIIPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func DecryptPII(key []byte, ciphertext string) (string, error) {
+	wrapWithMsg := "decryptPII"
+
+	if !IsEncryptedPII(ciphertext) {
+		return "", errors.Mark(errors.New(wrapWithMsg+": value is not encrypted"), shared.ErrUnmarshalingFailed)
+	}
+
+	aead, err := buildAEAD(key)
+	if err != nil {
+		return "", errors.Wrap(err, wrapWithMsg)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(ciphertext, encryptedPIIPrefix))
+	if err != nil {
+		return "", shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.Mark(errors.New(wrapWithMsg+": ciphertext is too short"), shared.ErrUnmarshalingFailed)
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+	}
+
+	return string(plaintext), nil
+}
+
+func IsEncryptedPII(value string) bool {
+	return strings.HasPrefix(value, encryptedPIIPrefix)
+}
+
+func buildAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, "buildAEAD")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, "buildAEAD")
+	}
+
+	return aead, nil
+}
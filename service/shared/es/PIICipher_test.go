@@ -0,0 +1,52 @@
+package es_test
+
+import (
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPIICipher(t *testing.T) {
+	Convey("Given an encryption key", t, func() {
+		key, err := es.GenerateEncryptionKey()
+		So(err, ShouldBeNil)
+		So(key, ShouldHaveLength, es.EncryptionKeyLength)
+
+		Convey("When PII is encrypted", func() {
+			ciphertext, err := es.EncryptPII(key, "kevin@ball.com")
+			So(err, ShouldBeNil)
+
+			Convey("Then it should not contain the plain value", func() {
+				So(es.IsEncryptedPII(ciphertext), ShouldBeTrue)
+				So(ciphertext, ShouldNotContainSubstring, "kevin")
+
+				Convey("And it should be decryptable with the same key", func() {
+					plaintext, err := es.DecryptPII(key, ciphertext)
+					So(err, ShouldBeNil)
+					So(plaintext, ShouldEqual, "kevin@ball.com")
+				})
+
+				Convey("And it should not be decryptable with another key", func() {
+					otherKey, err := es.GenerateEncryptionKey()
+					So(err, ShouldBeNil)
+
+					_, err = es.DecryptPII(otherKey, ciphertext)
+					So(err, ShouldBeError)
+					So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+				})
+			})
+		})
+
+		Convey("When a plain value is decrypted", func() {
+			_, err := es.DecryptPII(key, "kevin@ball.com")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldBeError)
+				So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,23 @@
+package es
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type PostgresDialect struct{}
+
+func (d PostgresDialect) IsUniqueViolation(err error) bool {
+	actualErr, ok := err.(*pq.Error)
+
+	return ok && actualErr.Code == "23505"
+}
+
+// Notify uses pg_notify, Postgres only delivers the notification once the transaction is committed.
+func (d PostgresDialect) Notify(ctx context.Context, tx *sql.Tx, channel string, payload string) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, payload)
+
+	return err
+}
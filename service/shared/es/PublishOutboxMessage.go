@@ -0,0 +1,7 @@
+package es
+
+import "context"
+
+// PublishOutboxMessage is the port for publishers which deliver OutboxMessages to the outside world.
+// Delivery is at-least-once, so consumers should deduplicate by StreamID and StreamVersion.
+type PublishOutboxMessage func(ctx context.Context, message OutboxMessage) error
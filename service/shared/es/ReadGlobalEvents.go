@@ -0,0 +1,7 @@
+package es
+
+import "context"
+
+// ReadGlobalEvents returns up to maxEvents events with a global position greater than afterPosition,
+// ordered by their global position.
+type ReadGlobalEvents func(ctx context.Context, afterPosition uint64, maxEvents uint) ([]GlobalEvent, error)
@@ -0,0 +1,10 @@
+package es
+
+import "context"
+
+// ReadOutboxMessages returns up to maxMessages unpublished OutboxMessages with an ID greater than afterID,
+// ordered by their ID.
+type ReadOutboxMessages func(ctx context.Context, afterID uint64, maxMessages uint) ([]OutboxMessage, error)
+
+// MarkOutboxMessageAsPublished removes an OutboxMessage from the outbox after it was published.
+type MarkOutboxMessageAsPublished func(ctx context.Context, id uint64) error
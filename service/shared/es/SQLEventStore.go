@@ -0,0 +1,814 @@
+package es
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+// SQLPreCommitHook runs inside the transaction which appends to or purges a stream, right before the commit.
+// Aggregate specific stores use it to keep their own tables consistent with the events, e.g. to assert unique values.
+type SQLPreCommitHook func(ctx context.Context, tx *sql.Tx) error
+
+// SQLDialect covers what differs between the databases which SQLEventStore supports, the SQL itself is portable.
+type SQLDialect interface {
+	IsUniqueViolation(err error) bool
+	// Notify must only deliver the notification once tx is committed, databases without notifications do nothing.
+	Notify(ctx context.Context, tx *sql.Tx, channel string, payload string) error
+}
+
+// queryer is satisfied by *sql.DB and *sql.Tx, so streams can be loaded inside and outside of transactions.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQLEventStore persists the streams of any aggregate, identified by their StreamID.
+// Appending uses optimistic concurrency on (stream_id, stream_version) and also writes the outbox, the snapshots
+// (every snapshotInterval events, 0 disables them) and a notification on notificationChannel in the same transaction.
+// Events are marshaled and unmarshaled with a ctx which carries the open transaction (see ContextWithSQLTx),
+// so marshalDomainEvent and unmarshalDomainEvent can use the same database without a second connection.
+// Each event row carries a hash which is chained to the hash of its predecessor, see VerifyHashChain.
+// The contentType tells what marshalDomainEvent produces, JSON is stored in the jsonb payload column, all other
+// content types in the bytea payload_binary column. unmarshalDomainEvent must be able to read all content types.
+type SQLEventStore struct {
+	db                   *sql.DB
+	dialect              SQLDialect
+	eventStoreTableName  string
+	marshalDomainEvent   MarshalDomainEvent
+	unmarshalDomainEvent UnmarshalDomainEvent
+	contentType          string
+	snapshotsTableName   string
+	snapshotInterval     uint
+	buildSnapshot        BuildSnapshot
+	outboxTableName      string
+	notificationChannel  string
+}
+
+func NewSQLEventStore(
+	db *sql.DB,
+	dialect SQLDialect,
+	eventStoreTableName string,
+	marshalDomainEvent MarshalDomainEvent,
+	unmarshalDomainEvent UnmarshalDomainEvent,
+	contentType string,
+	snapshotsTableName string,
+	snapshotInterval uint,
+	buildSnapshot BuildSnapshot,
+	outboxTableName string,
+	notificationChannel string,
+) *SQLEventStore {
+
+	return &SQLEventStore{
+		db:                   db,
+		dialect:              dialect,
+		eventStoreTableName:  eventStoreTableName,
+		marshalDomainEvent:   marshalDomainEvent,
+		unmarshalDomainEvent: unmarshalDomainEvent,
+		contentType:          contentType,
+		snapshotsTableName:   snapshotsTableName,
+		snapshotInterval:     snapshotInterval,
+		buildSnapshot:        buildSnapshot,
+		outboxTableName:      outboxTableName,
+		notificationChannel:  notificationChannel,
+	}
+}
+
+// LoadEventStream returns the latest snapshot (if any) followed by all newer events, or an empty stream.
+func (s *SQLEventStore) LoadEventStream(ctx context.Context, streamID StreamID) (EventStream, error) {
+	eventStream, err := s.loadEventStreamWithSnapshot(ctx, s.db, streamID)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlEventStore.LoadEventStream")
+	}
+
+	return eventStream, nil
+}
+
+// LoadFullEventStream starts at version 1 even if the stream has a snapshot.
+func (s *SQLEventStore) LoadFullEventStream(ctx context.Context, streamID StreamID) (EventStream, error) {
+	eventStream, err := s.loadEventStream(ctx, s.db, streamID, 0, math.MaxUint32)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlEventStore.LoadFullEventStream")
+	}
+
+	return eventStream, nil
+}
+
+// LoadEventStreamRange ignores the snapshots, it returns at most maxEvents events starting at fromVersion.
+func (s *SQLEventStore) LoadEventStreamRange(
+	ctx context.Context,
+	streamID StreamID,
+	fromVersion uint,
+	maxEvents uint,
+) (EventStream, error) {
+
+	eventStream, err := s.loadEventStream(ctx, s.db, streamID, fromVersion, maxEvents)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlEventStore.LoadEventStreamRange")
+	}
+
+	return eventStream, nil
+}
+
+// AppendEventsToStream fails with shared.ErrConcurrencyConflict if one of the stream versions already exists.
+func (s *SQLEventStore) AppendEventsToStream(
+	ctx context.Context,
+	streamID StreamID,
+	events []DomainEvent,
+	preCommitHooks ...SQLPreCommitHook,
+) error {
+
+	var err error
+	wrapWithMsg := "sqlEventStore.AppendEventsToStream"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	ctx = ContextWithSQLTx(ctx, tx)
+
+	payloads, err := s.marshalEvents(ctx, events...)
+	if err != nil {
+		_ = tx.Rollback()
+
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	if err = s.appendEventsToStream(ctx, tx, streamID, events, payloads); err != nil {
+		_ = tx.Rollback()
+
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	if err = s.saveSnapshotIfDue(ctx, tx, streamID, events...); err != nil {
+		_ = tx.Rollback()
+
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	if err = s.commit(ctx, tx, preCommitHooks); err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	return nil
+}
+
+// PurgeEventStream physically deletes a stream together with its snapshot and unpublished outbox messages.
+func (s *SQLEventStore) PurgeEventStream(
+	ctx context.Context,
+	streamID StreamID,
+	preCommitHooks ...SQLPreCommitHook,
+) error {
+
+	var err error
+	wrapWithMsg := "sqlEventStore.PurgeEventStream"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	for _, tableName := range []string{s.eventStoreTableName, s.snapshotsTableName, s.outboxTableName} {
+		query := strings.Replace(`DELETE FROM %name% WHERE stream_id = $1`, "%name%", tableName, 1)
+
+		if _, err = tx.ExecContext(ctx, query, streamID.String()); err != nil {
+			_ = tx.Rollback()
+
+			return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+	}
+
+	if err = s.commit(ctx, tx, preCommitHooks); err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	return nil
+}
+
+// ReadGlobalEvents reads the events of all streams ordered by the serial id column, which is their global position.
+// It is meant to be used by CatchUpSubscription, which deals with the gaps of the serial column.
+func (s *SQLEventStore) ReadGlobalEvents(
+	ctx context.Context,
+	afterPosition uint64,
+	maxEvents uint,
+) ([]GlobalEvent, error) {
+
+	var err error
+	wrapWithMsg := "sqlEventStore.ReadGlobalEvents"
+
+	queryTemplate := `SELECT id, stream_id, event_name, content_type, payload, payload_binary, stream_version FROM %name%
+						WHERE id > $1
+						ORDER BY id ASC
+						LIMIT $2`
+
+	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
+
+	eventRows, err := s.db.QueryContext(ctx, query, afterPosition, maxEvents)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	defer eventRows.Close()
+
+	var storedEvents []storedEvent
+	var globalPositions []uint64
+	var streamIDs []string
+	var globalPosition uint64
+	var streamID string
+	var contentType string
+	var payload string
+	var payloadBinary []byte
+	var stored storedEvent
+
+	for eventRows.Next() {
+		err = eventRows.Scan(
+			&globalPosition, &streamID, &stored.name, &contentType, &payload, &payloadBinary, &stored.streamVersion,
+		)
+
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+
+		stored.payload = s.payloadFromColumns(contentType, payload, payloadBinary)
+		storedEvents = append(storedEvents, stored)
+		globalPositions = append(globalPositions, globalPosition)
+		streamIDs = append(streamIDs, streamID)
+	}
+
+	if err = eventRows.Err(); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	domainEvents, err := s.unmarshalStoredEvents(ctx, storedEvents)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	globalEvents := make([]GlobalEvent, 0, len(domainEvents))
+
+	for idx, domainEvent := range domainEvents {
+		globalEvents = append(globalEvents, BuildGlobalEvent(globalPositions[idx], NewStreamID(streamIDs[idx]), domainEvent))
+	}
+
+	return globalEvents, nil
+}
+
+// ReadOutboxMessages reads the events which were not published yet, it is meant to be used by OutboxRelay.
+func (s *SQLEventStore) ReadOutboxMessages(
+	ctx context.Context,
+	afterID uint64,
+	maxMessages uint,
+) ([]OutboxMessage, error) {
+
+	var err error
+	wrapWithMsg := "sqlEventStore.ReadOutboxMessages"
+
+	queryTemplate := `SELECT id, stream_id, event_name, occurred_at, stream_version, content_type, payload, payload_binary
+						FROM %name%
+						WHERE id > $1
+						ORDER BY id ASC
+						LIMIT $2`
+
+	query := strings.Replace(queryTemplate, "%name%", s.outboxTableName, 1)
+
+	messageRows, err := s.db.QueryContext(ctx, query, afterID, maxMessages)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	defer messageRows.Close()
+
+	var messages []OutboxMessage
+	var id uint64
+	var streamID string
+	var eventName string
+	var occurredAt time.Time
+	var streamVersion uint
+	var contentType string
+	var payload string
+	var payloadBinary []byte
+
+	for messageRows.Next() {
+		err = messageRows.Scan(
+			&id, &streamID, &eventName, &occurredAt, &streamVersion, &contentType, &payload, &payloadBinary,
+		)
+
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+
+		message := RebuildOutboxMessage(
+			id,
+			NewStreamID(streamID),
+			eventName,
+			occurredAt.Format(time.RFC3339Nano),
+			streamVersion,
+			contentType,
+			s.payloadFromColumns(contentType, payload, payloadBinary),
+		)
+
+		messages = append(messages, message)
+	}
+
+	if err = messageRows.Err(); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return messages, nil
+}
+
+func (s *SQLEventStore) MarkOutboxMessageAsPublished(ctx context.Context, id uint64) error {
+	queryTemplate := `DELETE FROM %name% WHERE id = $1`
+	query := strings.Replace(queryTemplate, "%name%", s.outboxTableName, 1)
+
+	if _, err := s.db.ExecContext(ctx, query, id); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, "sqlEventStore.MarkOutboxMessageAsPublished")
+	}
+
+	return nil
+}
+
+// VerifyHashChain walks one stream and reports all events which were modified, removed or inserted after the fact.
+func (s *SQLEventStore) VerifyHashChain(ctx context.Context, streamID StreamID) ([]HashChainBreak, error) {
+	queryTemplate := `SELECT stream_id, stream_version, event_name, content_type, payload, payload_binary, event_hash
+						FROM %name%
+						WHERE stream_id = $1
+						ORDER BY stream_version ASC`
+
+	breaks, err := s.verifyHashChains(ctx, queryTemplate, streamID.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlEventStore.VerifyHashChain")
+	}
+
+	return breaks, nil
+}
+
+// VerifyAllHashChains does the same as VerifyHashChain for all streams.
+func (s *SQLEventStore) VerifyAllHashChains(ctx context.Context) ([]HashChainBreak, error) {
+	queryTemplate := `SELECT stream_id, stream_version, event_name, content_type, payload, payload_binary, event_hash
+						FROM %name%
+						ORDER BY stream_id ASC, stream_version ASC`
+
+	breaks, err := s.verifyHashChains(ctx, queryTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlEventStore.VerifyAllHashChains")
+	}
+
+	return breaks, nil
+}
+
+func (s *SQLEventStore) verifyHashChains(
+	ctx context.Context,
+	queryTemplate string,
+	args ...interface{},
+) ([]HashChainBreak, error) {
+
+	var err error
+	wrapWithMsg := "verifyHashChains"
+
+	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
+
+	eventRows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	defer eventRows.Close()
+
+	verifier := NewHashChainVerifier()
+
+	var streamID string
+	var streamVersion uint
+	var eventName string
+	var contentType string
+	var payload string
+	var payloadBinary []byte
+	var eventHash sql.NullString
+
+	for eventRows.Next() {
+		err = eventRows.Scan(&streamID, &streamVersion, &eventName, &contentType, &payload, &payloadBinary, &eventHash)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+
+		verifier.Verify(
+			NewStreamID(streamID),
+			streamVersion,
+			eventName,
+			contentType,
+			s.payloadFromColumns(contentType, payload, payloadBinary),
+			eventHash.String,
+		)
+	}
+
+	if err = eventRows.Err(); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return verifier.Breaks(), nil
+}
+
+func (s *SQLEventStore) commit(ctx context.Context, tx *sql.Tx, preCommitHooks []SQLPreCommitHook) error {
+	for _, preCommitHook := range preCommitHooks {
+		if err := preCommitHook(ctx, tx); err != nil {
+			_ = tx.Rollback()
+
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, "commit")
+	}
+
+	return nil
+}
+
+/***** local methods for reading from and writing to the event store *****/
+
+// payloadColumns returns the values for the payload (jsonb) and payload_binary (bytea) columns.
+func (s *SQLEventStore) payloadColumns(payload []byte) ([]byte, []byte) {
+	if s.contentType == ContentTypeJSON {
+		return payload, nil
+	}
+
+	return []byte("{}"), payload
+}
+
+func (s *SQLEventStore) payloadFromColumns(contentType string, payload string, payloadBinary []byte) []byte {
+	if contentType == ContentTypeJSON {
+		return []byte(payload)
+	}
+
+	return payloadBinary
+}
+
+func (s *SQLEventStore) loadEventStream(
+	ctx context.Context,
+	db queryer,
+	streamID StreamID,
+	fromVersion uint,
+	maxEvents uint,
+) (EventStream, error) {
+
+	var err error
+	wrapWithMsg := "loadEventStream"
+
+	queryTemplate := `SELECT event_name, content_type, payload, payload_binary, stream_version FROM %name%
+						WHERE stream_id = $1 AND stream_version >= $2
+						ORDER BY stream_version ASC
+						LIMIT $3`
+
+	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
+
+	eventRows, err := db.QueryContext(ctx, query, streamID.String(), fromVersion, maxEvents)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	defer eventRows.Close()
+
+	var storedEvents []storedEvent
+	var contentType string
+	var payload string
+	var payloadBinary []byte
+	var stored storedEvent
+
+	for eventRows.Next() {
+		if err = eventRows.Scan(&stored.name, &contentType, &payload, &payloadBinary, &stored.streamVersion); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+
+		stored.payload = s.payloadFromColumns(contentType, payload, payloadBinary)
+		storedEvents = append(storedEvents, stored)
+	}
+
+	if err = eventRows.Err(); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	eventStream, err := s.unmarshalStoredEvents(ctx, storedEvents)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	return eventStream, nil
+}
+
+// storedEvent holds a row until all rows are read, unmarshalDomainEvent may query the same connection.
+type storedEvent struct {
+	name          string
+	payload       []byte
+	streamVersion uint
+}
+
+func (s *SQLEventStore) unmarshalStoredEvents(ctx context.Context, storedEvents []storedEvent) (EventStream, error) {
+	var eventStream EventStream
+
+	for _, stored := range storedEvents {
+		domainEvent, err := s.unmarshalDomainEvent(ctx, stored.name, stored.payload, stored.streamVersion)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, "unmarshalStoredEvents")
+		}
+
+		eventStream = append(eventStream, domainEvent)
+	}
+
+	return eventStream, nil
+}
+
+func (s *SQLEventStore) marshalEvents(ctx context.Context, events ...DomainEvent) ([][]byte, error) {
+	payloads := make([][]byte, 0, len(events))
+
+	for _, event := range events {
+		payload, err := s.marshalDomainEvent(ctx, event)
+		if err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, "marshalEvents")
+		}
+
+		payloads = append(payloads, payload)
+	}
+
+	return payloads, nil
+}
+
+func (s *SQLEventStore) appendEventsToStream(
+	ctx context.Context,
+	tx *sql.Tx,
+	streamID StreamID,
+	events []DomainEvent,
+	payloads [][]byte,
+) error {
+
+	var err error
+	wrapWithMsg := "appendEventsToStream"
+
+	queryTemplate := `INSERT INTO %name% (stream_id, stream_version, event_name, occurred_at, payload,
+							event_id, correlation_id, causation_id, actor, event_hash, content_type, payload_binary)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
+
+	outboxQueryTemplate := `INSERT INTO %name% (stream_id, stream_version, event_name, occurred_at, payload,
+							content_type, payload_binary)
+						VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	outboxQuery := strings.Replace(outboxQueryTemplate, "%name%", s.outboxTableName, 1)
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	previousHash, err := s.loadEventHash(ctx, tx, streamID, events[0].Meta().StreamVersion()-1)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	for idx, event := range events {
+		var eventHash string
+		eventPayload := payloads[idx]
+
+		eventHash, err = ComputeEventHash(
+			previousHash,
+			streamID,
+			event.Meta().StreamVersion(),
+			event.Meta().EventName(),
+			s.contentType,
+			eventPayload,
+		)
+
+		if err != nil {
+			return errors.Wrap(err, wrapWithMsg)
+		}
+
+		payloadColumn, payloadBinaryColumn := s.payloadColumns(eventPayload)
+
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			streamID.String(),
+			event.Meta().StreamVersion(),
+			event.Meta().EventName(),
+			event.Meta().OccurredAt(),
+			payloadColumn,
+			event.Meta().EventID(),
+			event.Meta().MessageMeta().CorrelationID(),
+			event.Meta().MessageMeta().CausationID(),
+			event.Meta().MessageMeta().Actor(),
+			eventHash,
+			s.contentType,
+			payloadBinaryColumn,
+		)
+
+		if err != nil {
+			return errors.Wrap(s.mapEventStoreErrors(err), wrapWithMsg)
+		}
+
+		previousHash = eventHash
+
+		_, err = tx.ExecContext(
+			ctx,
+			outboxQuery,
+			streamID.String(),
+			event.Meta().StreamVersion(),
+			event.Meta().EventName(),
+			event.Meta().OccurredAt(),
+			payloadColumn,
+			s.contentType,
+			payloadBinaryColumn,
+		)
+
+		if err != nil {
+			return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+
+		notification := MarshalChangeNotification(BuildChangeNotification(streamID, event.Meta().StreamVersion()))
+
+		if err = s.dialect.Notify(ctx, tx, s.notificationChannel, notification); err != nil {
+			return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+	}
+
+	return nil
+}
+
+// loadEventHash returns an empty hash for version 0 and for events which were stored before the hash chain existed.
+func (s *SQLEventStore) loadEventHash(
+	ctx context.Context,
+	tx *sql.Tx,
+	streamID StreamID,
+	streamVersion uint,
+) (string, error) {
+
+	if streamVersion == 0 {
+		return "", nil
+	}
+
+	queryTemplate := `SELECT event_hash FROM %name% WHERE stream_id = $1 AND stream_version = $2`
+	query := strings.Replace(queryTemplate, "%name%", s.eventStoreTableName, 1)
+
+	var eventHash sql.NullString
+
+	err := tx.QueryRowContext(ctx, query, streamID.String(), streamVersion).Scan(&eventHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", shared.MarkAndWrapError(err, shared.ErrTechnical, "loadEventHash")
+	}
+
+	return eventHash.String, nil
+}
+
+func (s *SQLEventStore) mapEventStoreErrors(err error) error {
+	if s.dialect.IsUniqueViolation(err) {
+		return errors.Mark(err, shared.ErrConcurrencyConflict)
+	}
+
+	return errors.Mark(err, shared.ErrTechnical) // some other DB error (Tx closed, wrong table, ...)
+}
+
+/***** local methods for reading and writing snapshots *****/
+
+func (s *SQLEventStore) loadEventStreamWithSnapshot(
+	ctx context.Context,
+	db queryer,
+	streamID StreamID,
+) (EventStream, error) {
+
+	wrapWithMsg := "loadEventStreamWithSnapshot"
+
+	var eventStream EventStream
+	fromVersion := uint(0)
+
+	snapshot, err := s.loadSnapshot(ctx, db, streamID)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	if snapshot != nil {
+		eventStream = append(eventStream, snapshot)
+		fromVersion = snapshot.Meta().StreamVersion() + 1
+	}
+
+	events, err := s.loadEventStream(ctx, db, streamID, fromVersion, math.MaxUint32)
+	if err != nil {
+		return nil, errors.Wrap(err, wrapWithMsg)
+	}
+
+	return append(eventStream, events...), nil
+}
+
+func (s *SQLEventStore) loadSnapshot(ctx context.Context, db queryer, streamID StreamID) (DomainEvent, error) {
+	var err error
+	wrapWithMsg := "loadSnapshot"
+
+	queryTemplate := `SELECT snapshot_name, content_type, payload, payload_binary, stream_version FROM %name%
+						WHERE stream_id = $1`
+	query := strings.Replace(queryTemplate, "%name%", s.snapshotsTableName, 1)
+
+	snapshotRows, err := db.QueryContext(ctx, query, streamID.String())
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	defer snapshotRows.Close()
+
+	if !snapshotRows.Next() {
+		if err = snapshotRows.Err(); err != nil {
+			return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+		}
+
+		return nil, nil
+	}
+
+	var snapshotName string
+	var contentType string
+	var payload string
+	var payloadBinary []byte
+	var streamVersion uint
+
+	if err = snapshotRows.Scan(&snapshotName, &contentType, &payload, &payloadBinary, &streamVersion); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	// unmarshalDomainEvent may query the same connection
+	if err = snapshotRows.Close(); err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	storedPayload := s.payloadFromColumns(contentType, payload, payloadBinary)
+
+	snapshot, err := s.unmarshalDomainEvent(ctx, snapshotName, storedPayload, streamVersion)
+	if err != nil {
+		return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+	}
+
+	return snapshot, nil
+}
+
+// saveSnapshotIfDue stores a new snapshot if the appended events crossed a multiple of the snapshot interval.
+// It reads the stream inside the transaction, so the snapshot includes the events which were just appended.
+func (s *SQLEventStore) saveSnapshotIfDue(
+	ctx context.Context,
+	tx *sql.Tx,
+	streamID StreamID,
+	events ...DomainEvent,
+) error {
+
+	var err error
+	wrapWithMsg := "saveSnapshotIfDue"
+
+	if s.snapshotInterval == 0 || s.buildSnapshot == nil || len(events) == 0 {
+		return nil
+	}
+
+	firstVersion := events[0].Meta().StreamVersion()
+	lastVersion := events[len(events)-1].Meta().StreamVersion()
+
+	if (firstVersion-1)/s.snapshotInterval == lastVersion/s.snapshotInterval {
+		return nil
+	}
+
+	eventStream, err := s.loadEventStreamWithSnapshot(ctx, tx, streamID)
+	if err != nil {
+		return errors.Wrap(err, wrapWithMsg)
+	}
+
+	snapshot := s.buildSnapshot(eventStream)
+
+	snapshotPayload, err := s.marshalDomainEvent(ctx, snapshot)
+	if err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrMarshalingFailed, wrapWithMsg)
+	}
+
+	queryTemplate := `INSERT INTO %name% (stream_id, stream_version, snapshot_name, payload, created_at,
+							content_type, payload_binary)
+						VALUES ($1, $2, $3, $4, $5, $6, $7)
+						ON CONFLICT (stream_id) DO UPDATE
+						SET stream_version = excluded.stream_version, snapshot_name = excluded.snapshot_name,
+							payload = excluded.payload, created_at = excluded.created_at,
+							content_type = excluded.content_type, payload_binary = excluded.payload_binary
+						WHERE %name%.stream_version < excluded.stream_version`
+	query := strings.ReplaceAll(queryTemplate, "%name%", s.snapshotsTableName)
+
+	payloadColumn, payloadBinaryColumn := s.payloadColumns(snapshotPayload)
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		streamID.String(),
+		snapshot.Meta().StreamVersion(),
+		snapshot.Meta().EventName(),
+		payloadColumn,
+		snapshot.Meta().OccurredAt(),
+		s.contentType,
+		payloadBinaryColumn,
+	)
+
+	if err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	return nil
+}
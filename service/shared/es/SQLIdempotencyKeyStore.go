@@ -0,0 +1,118 @@
+package es
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+)
+
+// SQLIdempotencyKeyStore records the results of commands by idempotency key, it works with all SQLDialects.
+// A reserved key expires after reservationTimeout (the command was aborted without releasing it),
+// a key with a recorded result expires after ttl. Expired keys are deleted with each reservation.
+type SQLIdempotencyKeyStore struct {
+	db                 *sql.DB
+	tableName          string
+	ttl                time.Duration
+	reservationTimeout time.Duration
+}
+
+func NewSQLIdempotencyKeyStore(
+	db *sql.DB,
+	tableName string,
+	ttl time.Duration,
+	reservationTimeout time.Duration,
+) *SQLIdempotencyKeyStore {
+
+	return &SQLIdempotencyKeyStore{
+		db:                 db,
+		tableName:          tableName,
+		ttl:                ttl,
+		reservationTimeout: reservationTimeout,
+	}
+}
+
+func (s *SQLIdempotencyKeyStore) ReserveIdempotencyKey(
+	ctx context.Context,
+	idempotencyKey, fingerprint string,
+) (string, bool, error) {
+
+	wrapWithMsg := "sqlIdempotencyKeyStore.ReserveIdempotencyKey"
+	now := time.Now()
+
+	deleteQuery := strings.Replace(`DELETE FROM %name% WHERE expires_at < $1`, "%name%", s.tableName, 1)
+
+	if _, err := s.db.ExecContext(ctx, deleteQuery, now.UnixNano()); err != nil {
+		return "", false, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	insertQueryTemplate := `INSERT INTO %name% (idempotency_key, fingerprint, result, expires_at) VALUES ($1, $2, NULL, $3)
+						ON CONFLICT (idempotency_key) DO NOTHING`
+	insertQuery := strings.Replace(insertQueryTemplate, "%name%", s.tableName, 1)
+
+	expiresAt := now.Add(s.reservationTimeout).UnixNano()
+
+	inserted, err := s.db.ExecContext(ctx, insertQuery, idempotencyKey, fingerprint, expiresAt)
+	if err != nil {
+		return "", false, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	rowsAffected, err := inserted.RowsAffected()
+	if err != nil {
+		return "", false, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	}
+
+	if rowsAffected == 1 {
+		return "", false, nil
+	}
+
+	selectQuery := strings.Replace(
+		`SELECT fingerprint, result FROM %name% WHERE idempotency_key = $1`, "%name%", s.tableName, 1,
+	)
+
+	var recordedFingerprint string
+	var result sql.NullString
+
+	err = s.db.QueryRowContext(ctx, selectQuery, idempotencyKey).Scan(&recordedFingerprint, &result)
+
+	switch {
+	case err == sql.ErrNoRows: // released in the meantime
+		err = errors.New("a command with this idempotency key was aborted, please retry")
+		return "", false, shared.MarkAndWrapError(err, shared.ErrConcurrencyConflict, wrapWithMsg)
+	case err != nil:
+		return "", false, shared.MarkAndWrapError(err, shared.ErrTechnical, wrapWithMsg)
+	case recordedFingerprint != fingerprint:
+		err = errors.New("idempotency key was already used for another command")
+		return "", false, shared.MarkAndWrapError(err, shared.ErrInputIsInvalid, wrapWithMsg)
+	case !result.Valid:
+		err = errors.New("a command with this idempotency key is still in progress")
+		return "", false, shared.MarkAndWrapError(err, shared.ErrConcurrencyConflict, wrapWithMsg)
+	}
+
+	return result.String, true, nil
+}
+
+func (s *SQLIdempotencyKeyStore) RecordIdempotentResult(ctx context.Context, idempotencyKey, result string) error {
+	queryTemplate := `UPDATE %name% SET result = $1, expires_at = $2 WHERE idempotency_key = $3`
+	query := strings.Replace(queryTemplate, "%name%", s.tableName, 1)
+
+	if _, err := s.db.ExecContext(ctx, query, result, time.Now().Add(s.ttl).UnixNano(), idempotencyKey); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, "sqlIdempotencyKeyStore.RecordIdempotentResult")
+	}
+
+	return nil
+}
+
+func (s *SQLIdempotencyKeyStore) ReleaseIdempotencyKey(ctx context.Context, idempotencyKey string) error {
+	queryTemplate := `DELETE FROM %name% WHERE idempotency_key = $1 AND result IS NULL`
+	query := strings.Replace(queryTemplate, "%name%", s.tableName, 1)
+
+	if _, err := s.db.ExecContext(ctx, query, idempotencyKey); err != nil {
+		return shared.MarkAndWrapError(err, shared.ErrTechnical, "sqlIdempotencyKeyStore.ReleaseIdempotencyKey")
+	}
+
+	return nil
+}
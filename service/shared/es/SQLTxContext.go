@@ -0,0 +1,36 @@
+package es
+
+import (
+	"context"
+	"database/sql"
+)
+
+type sqlTxContextKey struct{}
+
+// ContextWithSQLTx is used by SQLEventStore to hand its open transaction to marshalDomainEvent and
+// unmarshalDomainEvent, so that e.g. encryption keys are read and written in the same transaction as the events.
+func ContextWithSQLTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, sqlTxContextKey{}, tx)
+}
+
+// SQLTxFromContext returns the transaction which ContextWithSQLTx has put into ctx, or nil.
+func SQLTxFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(sqlTxContextKey{}).(*sql.Tx)
+
+	return tx
+}
+
+// SQLExecutor is satisfied by *sql.DB and *sql.Tx.
+type SQLExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SQLTxOrDB returns the transaction in ctx if there is one, else db.
+func SQLTxOrDB(ctx context.Context, db *sql.DB) SQLExecutor {
+	if tx := SQLTxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return db
+}
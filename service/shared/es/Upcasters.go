@@ -0,0 +1,71 @@
+package es
+
+import (
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/cockroachdb/errors"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const initialSchemaVersion = 1
+
+// Upcast migrates a json payload from one schema version to the next one.
+type Upcast func(payload []byte) ([]byte, error)
+
+// Upcasters is a registry of Upcast chains per event name.
+// The current schema version of an event is the one after its last registered Upcast.
+type Upcasters struct {
+	upcasters map[string]map[uint]Upcast
+}
+
+func NewUpcasters() *Upcasters {
+	return &Upcasters{upcasters: make(map[string]map[uint]Upcast)}
+}
+
+// Register adds an Upcast which migrates payloads of eventName from fromSchemaVersion to fromSchemaVersion+1.
+// Upcasts must be registered without gaps, starting at schema version 1.
+func (u *Upcasters) Register(eventName string, fromSchemaVersion uint, upcast Upcast) *Upcasters {
+	if fromSchemaVersion != u.CurrentSchemaVersion(eventName) {
+		panic("upcasters.Register: upcasts must be registered in order without gaps")
+	}
+
+	if u.upcasters[eventName] == nil {
+		u.upcasters[eventName] = make(map[uint]Upcast)
+	}
+
+	u.upcasters[eventName][fromSchemaVersion] = upcast
+
+	return u
+}
+
+func (u *Upcasters) CurrentSchemaVersion(eventName string) uint {
+	return initialSchemaVersion + uint(len(u.upcasters[eventName]))
+}
+
+// Upcast migrates a json payload, which has its schema version in meta.schemaVersion, to the current schema version.
+func (u *Upcasters) Upcast(eventName string, payload []byte) ([]byte, error) {
+	var err error
+	wrapWithMsg := "upcasters.Upcast"
+
+	schemaVersionAny := jsoniter.ConfigFastest.Get(payload, "meta", "schemaVersion")
+
+	schemaVersion := uint(initialSchemaVersion)
+	if schemaVersionAny.LastError() == nil {
+		schemaVersion = schemaVersionAny.ToUint()
+	}
+
+	currentSchemaVersion := u.CurrentSchemaVersion(eventName)
+
+	if schemaVersion > currentSchemaVersion {
+		err = errors.Newf("schema version [%d] of [%s] is newer than the current one [%d]", schemaVersion, eventName, currentSchemaVersion)
+		return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+	}
+
+	for ; schemaVersion < currentSchemaVersion; schemaVersion++ {
+		if payload, err = u.upcasters[eventName][schemaVersion](payload); err != nil {
+			err = errors.Wrapf(err, "upcasting [%s] from schema version [%d] failed", eventName, schemaVersion)
+			return nil, shared.MarkAndWrapError(err, shared.ErrUnmarshalingFailed, wrapWithMsg)
+		}
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,90 @@
+package es_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/AntonStoeckl/go-iddd/service/shared"
+	"github.com/AntonStoeckl/go-iddd/service/shared/es"
+	"github.com/cockroachdb/errors"
+	jsoniter "github.com/json-iterator/go"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUpcasters(t *testing.T) {
+	Convey("Given Upcasters which split name into givenName (v1 -> v2) and rename givenName to firstName (v2 -> v3)", t, func() {
+		upcasters := es.NewUpcasters().
+			Register("SomeEvent", 1, func(payload []byte) ([]byte, error) {
+				data := make(map[string]interface{})
+				if err := json.Unmarshal(payload, &data); err != nil {
+					return nil, err
+				}
+
+				data["givenName"] = data["name"]
+				delete(data, "name")
+
+				return json.Marshal(data)
+			}).
+			Register("SomeEvent", 2, func(payload []byte) ([]byte, error) {
+				data := make(map[string]interface{})
+				if err := json.Unmarshal(payload, &data); err != nil {
+					return nil, err
+				}
+
+				data["firstName"] = data["givenName"]
+				delete(data, "givenName")
+
+				return json.Marshal(data)
+			})
+
+		Convey("Then the current schema version should be 3", func() {
+			So(upcasters.CurrentSchemaVersion("SomeEvent"), ShouldEqual, 3)
+			So(upcasters.CurrentSchemaVersion("OtherEvent"), ShouldEqual, 1)
+		})
+
+		Convey("When a payload without schema version is upcast", func() {
+			payload, err := upcasters.Upcast("SomeEvent", []byte(`{"name":"Kevin","meta":{"eventName":"SomeEvent"}}`))
+
+			Convey("Then all upcasts should be applied in order", func() {
+				So(err, ShouldBeNil)
+				So(jsoniter.ConfigFastest.Get(payload, "firstName").ToString(), ShouldEqual, "Kevin")
+				So(jsoniter.ConfigFastest.Get(payload, "name").LastError(), ShouldBeError)
+			})
+		})
+
+		Convey("When a payload with schema version 2 is upcast", func() {
+			payload, err := upcasters.Upcast("SomeEvent", []byte(`{"givenName":"Kevin","meta":{"schemaVersion":2}}`))
+
+			Convey("Then only the remaining upcasts should be applied", func() {
+				So(err, ShouldBeNil)
+				So(jsoniter.ConfigFastest.Get(payload, "firstName").ToString(), ShouldEqual, "Kevin")
+			})
+		})
+
+		Convey("When a payload with a schema version newer than the current one is upcast", func() {
+			_, err := upcasters.Upcast("SomeEvent", []byte(`{"meta":{"schemaVersion":4}}`))
+
+			Convey("Then it should fail", func() {
+				So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+			})
+		})
+
+		Convey("When an invalid payload is upcast", func() {
+			_, err := upcasters.Upcast("SomeEvent", []byte(`{"meta":`))
+
+			Convey("Then it should fail", func() {
+				So(errors.Is(err, shared.ErrUnmarshalingFailed), ShouldBeTrue)
+			})
+		})
+
+		Convey("When an upcast is registered with a gap", func() {
+			register := func() {
+				upcasters.Register("SomeEvent", 4, func(payload []byte) ([]byte, error) { return payload, nil })
+			}
+
+			Convey("Then it should panic", func() {
+				So(register, ShouldPanic)
+			})
+		})
+	})
+}